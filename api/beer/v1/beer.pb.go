@@ -0,0 +1,1364 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: beer/v1/beer.proto
+
+package beerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BeerStyle struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Uuid     string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name     string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TempMin  float64                `protobuf:"fixed64,3,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax  float64                `protobuf:"fixed64,4,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	Category string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	// Alcohol by volume, in percent, when known.
+	Abv           *float64               `protobuf:"fixed64,6,opt,name=abv,proto3,oneof" json:"abv,omitempty"`
+	Aliases       []string               `protobuf:"bytes,7,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeerStyle) Reset() {
+	*x = BeerStyle{}
+	mi := &file_beer_v1_beer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeerStyle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeerStyle) ProtoMessage() {}
+
+func (x *BeerStyle) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeerStyle.ProtoReflect.Descriptor instead.
+func (*BeerStyle) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BeerStyle) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *BeerStyle) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BeerStyle) GetTempMin() float64 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *BeerStyle) GetTempMax() float64 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *BeerStyle) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *BeerStyle) GetAbv() float64 {
+	if x != nil && x.Abv != nil {
+		return *x.Abv
+	}
+	return 0
+}
+
+func (x *BeerStyle) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *BeerStyle) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BeerStyle) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListBeerStylesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBeerStylesRequest) Reset() {
+	*x = ListBeerStylesRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBeerStylesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBeerStylesRequest) ProtoMessage() {}
+
+func (x *ListBeerStylesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBeerStylesRequest.ProtoReflect.Descriptor instead.
+func (*ListBeerStylesRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{1}
+}
+
+type ListBeerStylesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BeerStyles    []*BeerStyle           `protobuf:"bytes,1,rep,name=beer_styles,json=beerStyles,proto3" json:"beer_styles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBeerStylesResponse) Reset() {
+	*x = ListBeerStylesResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBeerStylesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBeerStylesResponse) ProtoMessage() {}
+
+func (x *ListBeerStylesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBeerStylesResponse.ProtoReflect.Descriptor instead.
+func (*ListBeerStylesResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListBeerStylesResponse) GetBeerStyles() []*BeerStyle {
+	if x != nil {
+		return x.BeerStyles
+	}
+	return nil
+}
+
+type GetBeerStyleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBeerStyleRequest) Reset() {
+	*x = GetBeerStyleRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBeerStyleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBeerStyleRequest) ProtoMessage() {}
+
+func (x *GetBeerStyleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBeerStyleRequest.ProtoReflect.Descriptor instead.
+func (*GetBeerStyleRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBeerStyleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetBeerStyleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BeerStyle     *BeerStyle             `protobuf:"bytes,1,opt,name=beer_style,json=beerStyle,proto3" json:"beer_style,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBeerStyleResponse) Reset() {
+	*x = GetBeerStyleResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBeerStyleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBeerStyleResponse) ProtoMessage() {}
+
+func (x *GetBeerStyleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBeerStyleResponse.ProtoReflect.Descriptor instead.
+func (*GetBeerStyleResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBeerStyleResponse) GetBeerStyle() *BeerStyle {
+	if x != nil {
+		return x.BeerStyle
+	}
+	return nil
+}
+
+type CreateBeerStyleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TempMin       float64                `protobuf:"fixed64,2,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax       float64                `protobuf:"fixed64,3,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Abv           *float64               `protobuf:"fixed64,5,opt,name=abv,proto3,oneof" json:"abv,omitempty"`
+	Aliases       []string               `protobuf:"bytes,6,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBeerStyleRequest) Reset() {
+	*x = CreateBeerStyleRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBeerStyleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBeerStyleRequest) ProtoMessage() {}
+
+func (x *CreateBeerStyleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBeerStyleRequest.ProtoReflect.Descriptor instead.
+func (*CreateBeerStyleRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateBeerStyleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateBeerStyleRequest) GetTempMin() float64 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *CreateBeerStyleRequest) GetTempMax() float64 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *CreateBeerStyleRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateBeerStyleRequest) GetAbv() float64 {
+	if x != nil && x.Abv != nil {
+		return *x.Abv
+	}
+	return 0
+}
+
+func (x *CreateBeerStyleRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type CreateBeerStyleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BeerStyle     *BeerStyle             `protobuf:"bytes,1,opt,name=beer_style,json=beerStyle,proto3" json:"beer_style,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBeerStyleResponse) Reset() {
+	*x = CreateBeerStyleResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBeerStyleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBeerStyleResponse) ProtoMessage() {}
+
+func (x *CreateBeerStyleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBeerStyleResponse.ProtoReflect.Descriptor instead.
+func (*CreateBeerStyleResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateBeerStyleResponse) GetBeerStyle() *BeerStyle {
+	if x != nil {
+		return x.BeerStyle
+	}
+	return nil
+}
+
+// UpdateBeerStyleRequest changes only the fields that are set.
+type UpdateBeerStyleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Uuid     string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name     *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	TempMin  *float64               `protobuf:"fixed64,3,opt,name=temp_min,json=tempMin,proto3,oneof" json:"temp_min,omitempty"`
+	TempMax  *float64               `protobuf:"fixed64,4,opt,name=temp_max,json=tempMax,proto3,oneof" json:"temp_max,omitempty"`
+	Category *string                `protobuf:"bytes,5,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	Abv      *float64               `protobuf:"fixed64,6,opt,name=abv,proto3,oneof" json:"abv,omitempty"`
+	// Replaces the aliases when set, even with an empty list.
+	Aliases       *Aliases `protobuf:"bytes,7,opt,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBeerStyleRequest) Reset() {
+	*x = UpdateBeerStyleRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBeerStyleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBeerStyleRequest) ProtoMessage() {}
+
+func (x *UpdateBeerStyleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBeerStyleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBeerStyleRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateBeerStyleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *UpdateBeerStyleRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateBeerStyleRequest) GetTempMin() float64 {
+	if x != nil && x.TempMin != nil {
+		return *x.TempMin
+	}
+	return 0
+}
+
+func (x *UpdateBeerStyleRequest) GetTempMax() float64 {
+	if x != nil && x.TempMax != nil {
+		return *x.TempMax
+	}
+	return 0
+}
+
+func (x *UpdateBeerStyleRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *UpdateBeerStyleRequest) GetAbv() float64 {
+	if x != nil && x.Abv != nil {
+		return *x.Abv
+	}
+	return 0
+}
+
+func (x *UpdateBeerStyleRequest) GetAliases() *Aliases {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type Aliases struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Aliases) Reset() {
+	*x = Aliases{}
+	mi := &file_beer_v1_beer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Aliases) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aliases) ProtoMessage() {}
+
+func (x *Aliases) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aliases.ProtoReflect.Descriptor instead.
+func (*Aliases) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Aliases) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type UpdateBeerStyleResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BeerStyle *BeerStyle             `protobuf:"bytes,1,opt,name=beer_style,json=beerStyle,proto3" json:"beer_style,omitempty"`
+	// False when the request matched the stored style.
+	Changed       bool `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBeerStyleResponse) Reset() {
+	*x = UpdateBeerStyleResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBeerStyleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBeerStyleResponse) ProtoMessage() {}
+
+func (x *UpdateBeerStyleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBeerStyleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateBeerStyleResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateBeerStyleResponse) GetBeerStyle() *BeerStyle {
+	if x != nil {
+		return x.BeerStyle
+	}
+	return nil
+}
+
+func (x *UpdateBeerStyleResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type DeleteBeerStyleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBeerStyleRequest) Reset() {
+	*x = DeleteBeerStyleRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBeerStyleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBeerStyleRequest) ProtoMessage() {}
+
+func (x *DeleteBeerStyleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBeerStyleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBeerStyleRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteBeerStyleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type DeleteBeerStyleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBeerStyleResponse) Reset() {
+	*x = DeleteBeerStyleResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBeerStyleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBeerStyleResponse) ProtoMessage() {}
+
+func (x *DeleteBeerStyleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBeerStyleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBeerStyleResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{11}
+}
+
+// RecommendRequest mirrors the body of POST /api/recommendations/suggest.
+type RecommendRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Temperature in degrees Celsius.
+	Temperature     float64  `protobuf:"fixed64,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	ExcludeStyles   []string `protobuf:"bytes,2,rep,name=exclude_styles,json=excludeStyles,proto3" json:"exclude_styles,omitempty"`
+	IncludeOnly     []string `protobuf:"bytes,3,rep,name=include_only,json=includeOnly,proto3" json:"include_only,omitempty"`
+	MaxAbv          *float64 `protobuf:"fixed64,4,opt,name=max_abv,json=maxAbv,proto3,oneof" json:"max_abv,omitempty"`
+	Categories      []string `protobuf:"bytes,5,rep,name=categories,proto3" json:"categories,omitempty"`
+	MinTracks       int32    `protobuf:"varint,6,opt,name=min_tracks,json=minTracks,proto3" json:"min_tracks,omitempty"`
+	TrackLimit      int32    `protobuf:"varint,7,opt,name=track_limit,json=trackLimit,proto3" json:"track_limit,omitempty"`
+	Shuffle         bool     `protobuf:"varint,8,opt,name=shuffle,proto3" json:"shuffle,omitempty"`
+	ShuffleSeed     *int64   `protobuf:"varint,9,opt,name=shuffle_seed,json=shuffleSeed,proto3,oneof" json:"shuffle_seed,omitempty"`
+	DistinctArtists bool     `protobuf:"varint,10,opt,name=distinct_artists,json=distinctArtists,proto3" json:"distinct_artists,omitempty"`
+	// Music provider; the configured default when empty.
+	Provider      string `protobuf:"bytes,11,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendRequest) Reset() {
+	*x = RecommendRequest{}
+	mi := &file_beer_v1_beer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendRequest) ProtoMessage() {}
+
+func (x *RecommendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendRequest.ProtoReflect.Descriptor instead.
+func (*RecommendRequest) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RecommendRequest) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetExcludeStyles() []string {
+	if x != nil {
+		return x.ExcludeStyles
+	}
+	return nil
+}
+
+func (x *RecommendRequest) GetIncludeOnly() []string {
+	if x != nil {
+		return x.IncludeOnly
+	}
+	return nil
+}
+
+func (x *RecommendRequest) GetMaxAbv() float64 {
+	if x != nil && x.MaxAbv != nil {
+		return *x.MaxAbv
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *RecommendRequest) GetMinTracks() int32 {
+	if x != nil {
+		return x.MinTracks
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetTrackLimit() int32 {
+	if x != nil {
+		return x.TrackLimit
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetShuffle() bool {
+	if x != nil {
+		return x.Shuffle
+	}
+	return false
+}
+
+func (x *RecommendRequest) GetShuffleSeed() int64 {
+	if x != nil && x.ShuffleSeed != nil {
+		return *x.ShuffleSeed
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetDistinctArtists() bool {
+	if x != nil {
+		return x.DistinctArtists
+	}
+	return false
+}
+
+func (x *RecommendRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type RecommendResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BeerStyle string                 `protobuf:"bytes,1,opt,name=beer_style,json=beerStyle,proto3" json:"beer_style,omitempty"`
+	Playlist  *Playlist              `protobuf:"bytes,2,opt,name=playlist,proto3" json:"playlist,omitempty"`
+	// Set when the playlist came from a fallback step.
+	Fallback *Fallback `protobuf:"bytes,3,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	// The ID of the recommendation in the history, to rate it over REST;
+	// empty when the history did not record it.
+	Id            string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendResponse) Reset() {
+	*x = RecommendResponse{}
+	mi := &file_beer_v1_beer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendResponse) ProtoMessage() {}
+
+func (x *RecommendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendResponse.ProtoReflect.Descriptor instead.
+func (*RecommendResponse) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RecommendResponse) GetBeerStyle() string {
+	if x != nil {
+		return x.BeerStyle
+	}
+	return ""
+}
+
+func (x *RecommendResponse) GetPlaylist() *Playlist {
+	if x != nil {
+		return x.Playlist
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetFallback() *Fallback {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Playlist struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Name     string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Tracks   []*Track               `protobuf:"bytes,4,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	// The seed used to shuffle the tracks, to reproduce the order.
+	ShuffleSeed   *int64 `protobuf:"varint,5,opt,name=shuffle_seed,json=shuffleSeed,proto3,oneof" json:"shuffle_seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Playlist) Reset() {
+	*x = Playlist{}
+	mi := &file_beer_v1_beer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Playlist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Playlist) ProtoMessage() {}
+
+func (x *Playlist) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Playlist.ProtoReflect.Descriptor instead.
+func (*Playlist) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Playlist) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Playlist) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Playlist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Playlist) GetTracks() []*Track {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *Playlist) GetShuffleSeed() int64 {
+	if x != nil && x.ShuffleSeed != nil {
+		return *x.ShuffleSeed
+	}
+	return 0
+}
+
+type Track struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Artist        string                 `protobuf:"bytes,2,opt,name=artist,proto3" json:"artist,omitempty"`
+	Link          string                 `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	Artists       []string               `protobuf:"bytes,4,rep,name=artists,proto3" json:"artists,omitempty"`
+	Album         string                 `protobuf:"bytes,5,opt,name=album,proto3" json:"album,omitempty"`
+	DurationMs    int32                  `protobuf:"varint,6,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Explicit      bool                   `protobuf:"varint,7,opt,name=explicit,proto3" json:"explicit,omitempty"`
+	Popularity    int32                  `protobuf:"varint,8,opt,name=popularity,proto3" json:"popularity,omitempty"`
+	PreviewUrl    string                 `protobuf:"bytes,9,opt,name=preview_url,json=previewUrl,proto3" json:"preview_url,omitempty"`
+	Isrc          string                 `protobuf:"bytes,10,opt,name=isrc,proto3" json:"isrc,omitempty"`
+	AlbumArtUrl   string                 `protobuf:"bytes,11,opt,name=album_art_url,json=albumArtUrl,proto3" json:"album_art_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Track) Reset() {
+	*x = Track{}
+	mi := &file_beer_v1_beer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Track) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Track) ProtoMessage() {}
+
+func (x *Track) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Track) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Track) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *Track) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *Track) GetArtists() []string {
+	if x != nil {
+		return x.Artists
+	}
+	return nil
+}
+
+func (x *Track) GetAlbum() string {
+	if x != nil {
+		return x.Album
+	}
+	return ""
+}
+
+func (x *Track) GetDurationMs() int32 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *Track) GetExplicit() bool {
+	if x != nil {
+		return x.Explicit
+	}
+	return false
+}
+
+func (x *Track) GetPopularity() int32 {
+	if x != nil {
+		return x.Popularity
+	}
+	return 0
+}
+
+func (x *Track) GetPreviewUrl() string {
+	if x != nil {
+		return x.PreviewUrl
+	}
+	return ""
+}
+
+func (x *Track) GetIsrc() string {
+	if x != nil {
+		return x.Isrc
+	}
+	return ""
+}
+
+func (x *Track) GetAlbumArtUrl() string {
+	if x != nil {
+		return x.AlbumArtUrl
+	}
+	return ""
+}
+
+type Fallback struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          string                 `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fallback) Reset() {
+	*x = Fallback{}
+	mi := &file_beer_v1_beer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fallback) ProtoMessage() {}
+
+func (x *Fallback) ProtoReflect() protoreflect.Message {
+	mi := &file_beer_v1_beer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fallback.ProtoReflect.Descriptor instead.
+func (*Fallback) Descriptor() ([]byte, []int) {
+	return file_beer_v1_beer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Fallback) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *Fallback) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+var File_beer_v1_beer_proto protoreflect.FileDescriptor
+
+const file_beer_v1_beer_proto_rawDesc = "" +
+	"\n" +
+	"\x12beer/v1/beer.proto\x12\abeer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb4\x02\n" +
+	"\tBeerStyle\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\btemp_min\x18\x03 \x01(\x01R\atempMin\x12\x19\n" +
+	"\btemp_max\x18\x04 \x01(\x01R\atempMax\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x15\n" +
+	"\x03abv\x18\x06 \x01(\x01H\x00R\x03abv\x88\x01\x01\x12\x18\n" +
+	"\aaliases\x18\a \x03(\tR\aaliases\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x06\n" +
+	"\x04_abv\"\x17\n" +
+	"\x15ListBeerStylesRequest\"M\n" +
+	"\x16ListBeerStylesResponse\x123\n" +
+	"\vbeer_styles\x18\x01 \x03(\v2\x12.beer.v1.BeerStyleR\n" +
+	"beerStyles\")\n" +
+	"\x13GetBeerStyleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"I\n" +
+	"\x14GetBeerStyleResponse\x121\n" +
+	"\n" +
+	"beer_style\x18\x01 \x01(\v2\x12.beer.v1.BeerStyleR\tbeerStyle\"\xb7\x01\n" +
+	"\x16CreateBeerStyleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\btemp_min\x18\x02 \x01(\x01R\atempMin\x12\x19\n" +
+	"\btemp_max\x18\x03 \x01(\x01R\atempMax\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x15\n" +
+	"\x03abv\x18\x05 \x01(\x01H\x00R\x03abv\x88\x01\x01\x12\x18\n" +
+	"\aaliases\x18\x06 \x03(\tR\aaliasesB\x06\n" +
+	"\x04_abv\"L\n" +
+	"\x17CreateBeerStyleResponse\x121\n" +
+	"\n" +
+	"beer_style\x18\x01 \x01(\v2\x12.beer.v1.BeerStyleR\tbeerStyle\"\xa1\x02\n" +
+	"\x16UpdateBeerStyleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x1e\n" +
+	"\btemp_min\x18\x03 \x01(\x01H\x01R\atempMin\x88\x01\x01\x12\x1e\n" +
+	"\btemp_max\x18\x04 \x01(\x01H\x02R\atempMax\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\x05 \x01(\tH\x03R\bcategory\x88\x01\x01\x12\x15\n" +
+	"\x03abv\x18\x06 \x01(\x01H\x04R\x03abv\x88\x01\x01\x12*\n" +
+	"\aaliases\x18\a \x01(\v2\x10.beer.v1.AliasesR\aaliasesB\a\n" +
+	"\x05_nameB\v\n" +
+	"\t_temp_minB\v\n" +
+	"\t_temp_maxB\v\n" +
+	"\t_categoryB\x06\n" +
+	"\x04_abv\"!\n" +
+	"\aAliases\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"f\n" +
+	"\x17UpdateBeerStyleResponse\x121\n" +
+	"\n" +
+	"beer_style\x18\x01 \x01(\v2\x12.beer.v1.BeerStyleR\tbeerStyle\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\bR\achanged\",\n" +
+	"\x16DeleteBeerStyleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x19\n" +
+	"\x17DeleteBeerStyleResponse\"\xa2\x03\n" +
+	"\x10RecommendRequest\x12 \n" +
+	"\vtemperature\x18\x01 \x01(\x01R\vtemperature\x12%\n" +
+	"\x0eexclude_styles\x18\x02 \x03(\tR\rexcludeStyles\x12!\n" +
+	"\finclude_only\x18\x03 \x03(\tR\vincludeOnly\x12\x1c\n" +
+	"\amax_abv\x18\x04 \x01(\x01H\x00R\x06maxAbv\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x05 \x03(\tR\n" +
+	"categories\x12\x1d\n" +
+	"\n" +
+	"min_tracks\x18\x06 \x01(\x05R\tminTracks\x12\x1f\n" +
+	"\vtrack_limit\x18\a \x01(\x05R\n" +
+	"trackLimit\x12\x18\n" +
+	"\ashuffle\x18\b \x01(\bR\ashuffle\x12&\n" +
+	"\fshuffle_seed\x18\t \x01(\x03H\x01R\vshuffleSeed\x88\x01\x01\x12)\n" +
+	"\x10distinct_artists\x18\n" +
+	" \x01(\bR\x0fdistinctArtists\x12\x1a\n" +
+	"\bprovider\x18\v \x01(\tR\bproviderB\n" +
+	"\n" +
+	"\b_max_abvB\x0f\n" +
+	"\r_shuffle_seed\"\xa0\x01\n" +
+	"\x11RecommendResponse\x12\x1d\n" +
+	"\n" +
+	"beer_style\x18\x01 \x01(\tR\tbeerStyle\x12-\n" +
+	"\bplaylist\x18\x02 \x01(\v2\x11.beer.v1.PlaylistR\bplaylist\x12-\n" +
+	"\bfallback\x18\x03 \x01(\v2\x11.beer.v1.FallbackR\bfallback\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\"\xab\x01\n" +
+	"\bPlaylist\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12&\n" +
+	"\x06tracks\x18\x04 \x03(\v2\x0e.beer.v1.TrackR\x06tracks\x12&\n" +
+	"\fshuffle_seed\x18\x05 \x01(\x03H\x00R\vshuffleSeed\x88\x01\x01B\x0f\n" +
+	"\r_shuffle_seed\"\xad\x02\n" +
+	"\x05Track\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06artist\x18\x02 \x01(\tR\x06artist\x12\x12\n" +
+	"\x04link\x18\x03 \x01(\tR\x04link\x12\x18\n" +
+	"\aartists\x18\x04 \x03(\tR\aartists\x12\x14\n" +
+	"\x05album\x18\x05 \x01(\tR\x05album\x12\x1f\n" +
+	"\vduration_ms\x18\x06 \x01(\x05R\n" +
+	"durationMs\x12\x1a\n" +
+	"\bexplicit\x18\a \x01(\bR\bexplicit\x12\x1e\n" +
+	"\n" +
+	"popularity\x18\b \x01(\x05R\n" +
+	"popularity\x12\x1f\n" +
+	"\vpreview_url\x18\t \x01(\tR\n" +
+	"previewUrl\x12\x12\n" +
+	"\x04isrc\x18\n" +
+	" \x01(\tR\x04isrc\x12\"\n" +
+	"\ralbum_art_url\x18\v \x01(\tR\valbumArtUrl\"4\n" +
+	"\bFallback\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\tR\x04step\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query2\xb4\x03\n" +
+	"\x10BeerStyleService\x12Q\n" +
+	"\x0eListBeerStyles\x12\x1e.beer.v1.ListBeerStylesRequest\x1a\x1f.beer.v1.ListBeerStylesResponse\x12K\n" +
+	"\fGetBeerStyle\x12\x1c.beer.v1.GetBeerStyleRequest\x1a\x1d.beer.v1.GetBeerStyleResponse\x12T\n" +
+	"\x0fCreateBeerStyle\x12\x1f.beer.v1.CreateBeerStyleRequest\x1a .beer.v1.CreateBeerStyleResponse\x12T\n" +
+	"\x0fUpdateBeerStyle\x12\x1f.beer.v1.UpdateBeerStyleRequest\x1a .beer.v1.UpdateBeerStyleResponse\x12T\n" +
+	"\x0fDeleteBeerStyle\x12\x1f.beer.v1.DeleteBeerStyleRequest\x1a .beer.v1.DeleteBeerStyleResponse2[\n" +
+	"\x15RecommendationService\x12B\n" +
+	"\tRecommend\x12\x19.beer.v1.RecommendRequest\x1a\x1a.beer.v1.RecommendResponseB!Z\x1fbackend-test/api/beer/v1;beerv1b\x06proto3"
+
+var (
+	file_beer_v1_beer_proto_rawDescOnce sync.Once
+	file_beer_v1_beer_proto_rawDescData []byte
+)
+
+func file_beer_v1_beer_proto_rawDescGZIP() []byte {
+	file_beer_v1_beer_proto_rawDescOnce.Do(func() {
+		file_beer_v1_beer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_beer_v1_beer_proto_rawDesc), len(file_beer_v1_beer_proto_rawDesc)))
+	})
+	return file_beer_v1_beer_proto_rawDescData
+}
+
+var file_beer_v1_beer_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_beer_v1_beer_proto_goTypes = []any{
+	(*BeerStyle)(nil),               // 0: beer.v1.BeerStyle
+	(*ListBeerStylesRequest)(nil),   // 1: beer.v1.ListBeerStylesRequest
+	(*ListBeerStylesResponse)(nil),  // 2: beer.v1.ListBeerStylesResponse
+	(*GetBeerStyleRequest)(nil),     // 3: beer.v1.GetBeerStyleRequest
+	(*GetBeerStyleResponse)(nil),    // 4: beer.v1.GetBeerStyleResponse
+	(*CreateBeerStyleRequest)(nil),  // 5: beer.v1.CreateBeerStyleRequest
+	(*CreateBeerStyleResponse)(nil), // 6: beer.v1.CreateBeerStyleResponse
+	(*UpdateBeerStyleRequest)(nil),  // 7: beer.v1.UpdateBeerStyleRequest
+	(*Aliases)(nil),                 // 8: beer.v1.Aliases
+	(*UpdateBeerStyleResponse)(nil), // 9: beer.v1.UpdateBeerStyleResponse
+	(*DeleteBeerStyleRequest)(nil),  // 10: beer.v1.DeleteBeerStyleRequest
+	(*DeleteBeerStyleResponse)(nil), // 11: beer.v1.DeleteBeerStyleResponse
+	(*RecommendRequest)(nil),        // 12: beer.v1.RecommendRequest
+	(*RecommendResponse)(nil),       // 13: beer.v1.RecommendResponse
+	(*Playlist)(nil),                // 14: beer.v1.Playlist
+	(*Track)(nil),                   // 15: beer.v1.Track
+	(*Fallback)(nil),                // 16: beer.v1.Fallback
+	(*timestamppb.Timestamp)(nil),   // 17: google.protobuf.Timestamp
+}
+var file_beer_v1_beer_proto_depIdxs = []int32{
+	17, // 0: beer.v1.BeerStyle.created_at:type_name -> google.protobuf.Timestamp
+	17, // 1: beer.v1.BeerStyle.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: beer.v1.ListBeerStylesResponse.beer_styles:type_name -> beer.v1.BeerStyle
+	0,  // 3: beer.v1.GetBeerStyleResponse.beer_style:type_name -> beer.v1.BeerStyle
+	0,  // 4: beer.v1.CreateBeerStyleResponse.beer_style:type_name -> beer.v1.BeerStyle
+	8,  // 5: beer.v1.UpdateBeerStyleRequest.aliases:type_name -> beer.v1.Aliases
+	0,  // 6: beer.v1.UpdateBeerStyleResponse.beer_style:type_name -> beer.v1.BeerStyle
+	14, // 7: beer.v1.RecommendResponse.playlist:type_name -> beer.v1.Playlist
+	16, // 8: beer.v1.RecommendResponse.fallback:type_name -> beer.v1.Fallback
+	15, // 9: beer.v1.Playlist.tracks:type_name -> beer.v1.Track
+	1,  // 10: beer.v1.BeerStyleService.ListBeerStyles:input_type -> beer.v1.ListBeerStylesRequest
+	3,  // 11: beer.v1.BeerStyleService.GetBeerStyle:input_type -> beer.v1.GetBeerStyleRequest
+	5,  // 12: beer.v1.BeerStyleService.CreateBeerStyle:input_type -> beer.v1.CreateBeerStyleRequest
+	7,  // 13: beer.v1.BeerStyleService.UpdateBeerStyle:input_type -> beer.v1.UpdateBeerStyleRequest
+	10, // 14: beer.v1.BeerStyleService.DeleteBeerStyle:input_type -> beer.v1.DeleteBeerStyleRequest
+	12, // 15: beer.v1.RecommendationService.Recommend:input_type -> beer.v1.RecommendRequest
+	2,  // 16: beer.v1.BeerStyleService.ListBeerStyles:output_type -> beer.v1.ListBeerStylesResponse
+	4,  // 17: beer.v1.BeerStyleService.GetBeerStyle:output_type -> beer.v1.GetBeerStyleResponse
+	6,  // 18: beer.v1.BeerStyleService.CreateBeerStyle:output_type -> beer.v1.CreateBeerStyleResponse
+	9,  // 19: beer.v1.BeerStyleService.UpdateBeerStyle:output_type -> beer.v1.UpdateBeerStyleResponse
+	11, // 20: beer.v1.BeerStyleService.DeleteBeerStyle:output_type -> beer.v1.DeleteBeerStyleResponse
+	13, // 21: beer.v1.RecommendationService.Recommend:output_type -> beer.v1.RecommendResponse
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_beer_v1_beer_proto_init() }
+func file_beer_v1_beer_proto_init() {
+	if File_beer_v1_beer_proto != nil {
+		return
+	}
+	file_beer_v1_beer_proto_msgTypes[0].OneofWrappers = []any{}
+	file_beer_v1_beer_proto_msgTypes[5].OneofWrappers = []any{}
+	file_beer_v1_beer_proto_msgTypes[7].OneofWrappers = []any{}
+	file_beer_v1_beer_proto_msgTypes[12].OneofWrappers = []any{}
+	file_beer_v1_beer_proto_msgTypes[14].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_beer_v1_beer_proto_rawDesc), len(file_beer_v1_beer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_beer_v1_beer_proto_goTypes,
+		DependencyIndexes: file_beer_v1_beer_proto_depIdxs,
+		MessageInfos:      file_beer_v1_beer_proto_msgTypes,
+	}.Build()
+	File_beer_v1_beer_proto = out.File
+	file_beer_v1_beer_proto_goTypes = nil
+	file_beer_v1_beer_proto_depIdxs = nil
+}
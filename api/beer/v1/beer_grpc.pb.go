@@ -0,0 +1,387 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: beer/v1/beer.proto
+
+package beerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BeerStyleService_ListBeerStyles_FullMethodName  = "/beer.v1.BeerStyleService/ListBeerStyles"
+	BeerStyleService_GetBeerStyle_FullMethodName    = "/beer.v1.BeerStyleService/GetBeerStyle"
+	BeerStyleService_CreateBeerStyle_FullMethodName = "/beer.v1.BeerStyleService/CreateBeerStyle"
+	BeerStyleService_UpdateBeerStyle_FullMethodName = "/beer.v1.BeerStyleService/UpdateBeerStyle"
+	BeerStyleService_DeleteBeerStyle_FullMethodName = "/beer.v1.BeerStyleService/DeleteBeerStyle"
+)
+
+// BeerStyleServiceClient is the client API for BeerStyleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BeerStyleService manages the beer styles. Changes require the editor
+// role.
+type BeerStyleServiceClient interface {
+	ListBeerStyles(ctx context.Context, in *ListBeerStylesRequest, opts ...grpc.CallOption) (*ListBeerStylesResponse, error)
+	GetBeerStyle(ctx context.Context, in *GetBeerStyleRequest, opts ...grpc.CallOption) (*GetBeerStyleResponse, error)
+	CreateBeerStyle(ctx context.Context, in *CreateBeerStyleRequest, opts ...grpc.CallOption) (*CreateBeerStyleResponse, error)
+	UpdateBeerStyle(ctx context.Context, in *UpdateBeerStyleRequest, opts ...grpc.CallOption) (*UpdateBeerStyleResponse, error)
+	DeleteBeerStyle(ctx context.Context, in *DeleteBeerStyleRequest, opts ...grpc.CallOption) (*DeleteBeerStyleResponse, error)
+}
+
+type beerStyleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBeerStyleServiceClient(cc grpc.ClientConnInterface) BeerStyleServiceClient {
+	return &beerStyleServiceClient{cc}
+}
+
+func (c *beerStyleServiceClient) ListBeerStyles(ctx context.Context, in *ListBeerStylesRequest, opts ...grpc.CallOption) (*ListBeerStylesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBeerStylesResponse)
+	err := c.cc.Invoke(ctx, BeerStyleService_ListBeerStyles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerStyleServiceClient) GetBeerStyle(ctx context.Context, in *GetBeerStyleRequest, opts ...grpc.CallOption) (*GetBeerStyleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBeerStyleResponse)
+	err := c.cc.Invoke(ctx, BeerStyleService_GetBeerStyle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerStyleServiceClient) CreateBeerStyle(ctx context.Context, in *CreateBeerStyleRequest, opts ...grpc.CallOption) (*CreateBeerStyleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateBeerStyleResponse)
+	err := c.cc.Invoke(ctx, BeerStyleService_CreateBeerStyle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerStyleServiceClient) UpdateBeerStyle(ctx context.Context, in *UpdateBeerStyleRequest, opts ...grpc.CallOption) (*UpdateBeerStyleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateBeerStyleResponse)
+	err := c.cc.Invoke(ctx, BeerStyleService_UpdateBeerStyle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerStyleServiceClient) DeleteBeerStyle(ctx context.Context, in *DeleteBeerStyleRequest, opts ...grpc.CallOption) (*DeleteBeerStyleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteBeerStyleResponse)
+	err := c.cc.Invoke(ctx, BeerStyleService_DeleteBeerStyle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BeerStyleServiceServer is the server API for BeerStyleService service.
+// All implementations must embed UnimplementedBeerStyleServiceServer
+// for forward compatibility.
+//
+// BeerStyleService manages the beer styles. Changes require the editor
+// role.
+type BeerStyleServiceServer interface {
+	ListBeerStyles(context.Context, *ListBeerStylesRequest) (*ListBeerStylesResponse, error)
+	GetBeerStyle(context.Context, *GetBeerStyleRequest) (*GetBeerStyleResponse, error)
+	CreateBeerStyle(context.Context, *CreateBeerStyleRequest) (*CreateBeerStyleResponse, error)
+	UpdateBeerStyle(context.Context, *UpdateBeerStyleRequest) (*UpdateBeerStyleResponse, error)
+	DeleteBeerStyle(context.Context, *DeleteBeerStyleRequest) (*DeleteBeerStyleResponse, error)
+	mustEmbedUnimplementedBeerStyleServiceServer()
+}
+
+// UnimplementedBeerStyleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBeerStyleServiceServer struct{}
+
+func (UnimplementedBeerStyleServiceServer) ListBeerStyles(context.Context, *ListBeerStylesRequest) (*ListBeerStylesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBeerStyles not implemented")
+}
+func (UnimplementedBeerStyleServiceServer) GetBeerStyle(context.Context, *GetBeerStyleRequest) (*GetBeerStyleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBeerStyle not implemented")
+}
+func (UnimplementedBeerStyleServiceServer) CreateBeerStyle(context.Context, *CreateBeerStyleRequest) (*CreateBeerStyleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBeerStyle not implemented")
+}
+func (UnimplementedBeerStyleServiceServer) UpdateBeerStyle(context.Context, *UpdateBeerStyleRequest) (*UpdateBeerStyleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBeerStyle not implemented")
+}
+func (UnimplementedBeerStyleServiceServer) DeleteBeerStyle(context.Context, *DeleteBeerStyleRequest) (*DeleteBeerStyleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBeerStyle not implemented")
+}
+func (UnimplementedBeerStyleServiceServer) mustEmbedUnimplementedBeerStyleServiceServer() {}
+func (UnimplementedBeerStyleServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeBeerStyleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BeerStyleServiceServer will
+// result in compilation errors.
+type UnsafeBeerStyleServiceServer interface {
+	mustEmbedUnimplementedBeerStyleServiceServer()
+}
+
+func RegisterBeerStyleServiceServer(s grpc.ServiceRegistrar, srv BeerStyleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBeerStyleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BeerStyleService_ServiceDesc, srv)
+}
+
+func _BeerStyleService_ListBeerStyles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBeerStylesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerStyleServiceServer).ListBeerStyles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BeerStyleService_ListBeerStyles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerStyleServiceServer).ListBeerStyles(ctx, req.(*ListBeerStylesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerStyleService_GetBeerStyle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBeerStyleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerStyleServiceServer).GetBeerStyle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BeerStyleService_GetBeerStyle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerStyleServiceServer).GetBeerStyle(ctx, req.(*GetBeerStyleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerStyleService_CreateBeerStyle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBeerStyleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerStyleServiceServer).CreateBeerStyle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BeerStyleService_CreateBeerStyle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerStyleServiceServer).CreateBeerStyle(ctx, req.(*CreateBeerStyleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerStyleService_UpdateBeerStyle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBeerStyleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerStyleServiceServer).UpdateBeerStyle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BeerStyleService_UpdateBeerStyle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerStyleServiceServer).UpdateBeerStyle(ctx, req.(*UpdateBeerStyleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerStyleService_DeleteBeerStyle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBeerStyleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerStyleServiceServer).DeleteBeerStyle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BeerStyleService_DeleteBeerStyle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerStyleServiceServer).DeleteBeerStyle(ctx, req.(*DeleteBeerStyleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BeerStyleService_ServiceDesc is the grpc.ServiceDesc for BeerStyleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BeerStyleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "beer.v1.BeerStyleService",
+	HandlerType: (*BeerStyleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBeerStyles",
+			Handler:    _BeerStyleService_ListBeerStyles_Handler,
+		},
+		{
+			MethodName: "GetBeerStyle",
+			Handler:    _BeerStyleService_GetBeerStyle_Handler,
+		},
+		{
+			MethodName: "CreateBeerStyle",
+			Handler:    _BeerStyleService_CreateBeerStyle_Handler,
+		},
+		{
+			MethodName: "UpdateBeerStyle",
+			Handler:    _BeerStyleService_UpdateBeerStyle_Handler,
+		},
+		{
+			MethodName: "DeleteBeerStyle",
+			Handler:    _BeerStyleService_DeleteBeerStyle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "beer/v1/beer.proto",
+}
+
+const (
+	RecommendationService_Recommend_FullMethodName = "/beer.v1.RecommendationService/Recommend"
+)
+
+// RecommendationServiceClient is the client API for RecommendationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RecommendationService picks a beer style for a temperature and a playlist
+// for it.
+type RecommendationServiceClient interface {
+	Recommend(ctx context.Context, in *RecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error)
+}
+
+type recommendationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRecommendationServiceClient(cc grpc.ClientConnInterface) RecommendationServiceClient {
+	return &recommendationServiceClient{cc}
+}
+
+func (c *recommendationServiceClient) Recommend(ctx context.Context, in *RecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendResponse)
+	err := c.cc.Invoke(ctx, RecommendationService_Recommend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RecommendationServiceServer is the server API for RecommendationService service.
+// All implementations must embed UnimplementedRecommendationServiceServer
+// for forward compatibility.
+//
+// RecommendationService picks a beer style for a temperature and a playlist
+// for it.
+type RecommendationServiceServer interface {
+	Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error)
+	mustEmbedUnimplementedRecommendationServiceServer()
+}
+
+// UnimplementedRecommendationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRecommendationServiceServer struct{}
+
+func (UnimplementedRecommendationServiceServer) Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Recommend not implemented")
+}
+func (UnimplementedRecommendationServiceServer) mustEmbedUnimplementedRecommendationServiceServer() {}
+func (UnimplementedRecommendationServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeRecommendationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RecommendationServiceServer will
+// result in compilation errors.
+type UnsafeRecommendationServiceServer interface {
+	mustEmbedUnimplementedRecommendationServiceServer()
+}
+
+func RegisterRecommendationServiceServer(s grpc.ServiceRegistrar, srv RecommendationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRecommendationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RecommendationService_ServiceDesc, srv)
+}
+
+func _RecommendationService_Recommend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecommendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecommendationServiceServer).Recommend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecommendationService_Recommend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecommendationServiceServer).Recommend(ctx, req.(*RecommendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RecommendationService_ServiceDesc is the grpc.ServiceDesc for RecommendationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RecommendationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "beer.v1.RecommendationService",
+	HandlerType: (*RecommendationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Recommend",
+			Handler:    _RecommendationService_Recommend_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "beer/v1/beer.proto",
+}
@@ -0,0 +1,8 @@
+// Package api holds the protobuf definitions of the gRPC API. The Go stubs
+// next to each .proto are generated with buf, protoc-gen-go v1.36.6 and
+// protoc-gen-go-grpc v1.5.1:
+//
+//	go generate ./api
+package api
+
+//go:generate buf generate
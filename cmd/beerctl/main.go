@@ -0,0 +1,28 @@
+// Command beerctl manages the beer styles and inspects the
+// recommendations, through the API or straight against the database.
+//
+//	beerctl styles list
+//	beerctl -o json recommend --temp 46 --unit F
+//	beerctl --database-url postgres://... styles delete <uuid>
+package main
+
+import (
+	"backend-test/internal/beerctl"
+	"backend-test/internal/logging"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// The database backend logs through slog; only problems are of interest
+	// on a terminal.
+	slog.SetDefault(logging.New(os.Stderr, "warn", "text"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := beerctl.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,197 @@
+// Package applemusic implements music.Provider using the Apple Music API
+// catalog endpoints, authenticated with a developer token.
+package applemusic
+
+import (
+	"backend-test/external/music"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL    = "https://api.music.apple.com"
+	DefaultStorefront = "us"
+)
+
+// artworkSize is the edge length requested from artwork URL templates.
+const artworkSize = "600"
+
+type Provider struct {
+	developerToken string
+	storefront     string
+	baseURL        string
+	httpClient     *http.Client
+}
+
+func NewProvider(developerToken string, storefront string, baseURL string, httpClient *http.Client) *Provider {
+	if storefront == "" {
+		storefront = DefaultStorefront
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{
+		developerToken: developerToken,
+		storefront:     storefront,
+		baseURL:        baseURL,
+		httpClient:     httpClient,
+	}
+}
+
+func (p *Provider) Name() string {
+	return music.ProviderAppleMusic
+}
+
+type artwork struct {
+	URL string `json:"url"`
+}
+
+type playlistResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name string `json:"name"`
+	} `json:"attributes"`
+	Relationships struct {
+		Tracks struct {
+			Data []songResource `json:"data"`
+		} `json:"tracks"`
+	} `json:"relationships"`
+}
+
+type songResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name             string  `json:"name"`
+		ArtistName       string  `json:"artistName"`
+		AlbumName        string  `json:"albumName"`
+		DurationInMillis int     `json:"durationInMillis"`
+		ContentRating    string  `json:"contentRating"`
+		ISRC             string  `json:"isrc"`
+		URL              string  `json:"url"`
+		Artwork          artwork `json:"artwork"`
+		Previews         []struct {
+			URL string `json:"url"`
+		} `json:"previews"`
+	} `json:"attributes"`
+}
+
+type searchResponse struct {
+	Results struct {
+		Playlists struct {
+			Data []playlistResource `json:"data"`
+		} `json:"playlists"`
+	} `json:"results"`
+}
+
+type playlistResponse struct {
+	Data []playlistResource `json:"data"`
+}
+
+func (p *Provider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	params := url.Values{}
+	params.Set("term", query)
+	params.Set("types", "playlists")
+	params.Set("limit", strconv.Itoa(limit))
+
+	var response searchResponse
+	if err := p.get(ctx, "/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	hits := response.Results.Playlists.Data
+	if len(hits) == 0 {
+		return nil, fmt.Errorf("apple_music: %w", music.ErrPlaylistNotFound)
+	}
+
+	summaries := make([]music.PlaylistSummary, 0, len(hits))
+	for _, hit := range hits {
+		summaries = append(summaries, music.PlaylistSummary{ID: hit.ID, Name: hit.Attributes.Name})
+	}
+	return summaries, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	var response playlistResponse
+	if err := p.get(ctx, "/playlists/"+url.PathEscape(playlistID)+"?include=tracks", &response); err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("apple_music: %w", music.ErrPlaylistNotFound)
+	}
+
+	playlist := response.Data[0]
+	tracks := make([]music.Track, 0, len(playlist.Relationships.Tracks.Data))
+	for _, song := range playlist.Relationships.Tracks.Data {
+		attributes := song.Attributes
+		if attributes.Name == "" {
+			continue
+		}
+
+		var artists []string
+		if attributes.ArtistName != "" {
+			artists = []string{attributes.ArtistName}
+		}
+
+		previewURL := ""
+		if len(attributes.Previews) > 0 {
+			previewURL = attributes.Previews[0].URL
+		}
+
+		artworkURL := strings.NewReplacer("{w}", artworkSize, "{h}", artworkSize).Replace(attributes.Artwork.URL)
+
+		tracks = append(tracks, music.Track{
+			ID:          song.ID,
+			Name:        attributes.Name,
+			Artists:     artists,
+			Album:       attributes.AlbumName,
+			DurationMs:  attributes.DurationInMillis,
+			Explicit:    attributes.ContentRating == "explicit",
+			PreviewURL:  previewURL,
+			ISRC:        attributes.ISRC,
+			AlbumArtURL: artworkURL,
+			Link:        attributes.URL,
+		})
+	}
+
+	return &music.Playlist{
+		ID:       playlist.ID,
+		Name:     playlist.Attributes.Name,
+		Provider: music.ProviderAppleMusic,
+		Tracks:   tracks,
+	}, nil
+}
+
+func (p *Provider) get(ctx context.Context, path string, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/v1/catalog/%s%s", p.baseURL, url.PathEscape(p.storefront), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.developerToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("apple_music: %v: %w", err, music.ErrProviderUnavailable)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("apple_music: %w", music.ErrPlaylistNotFound)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("apple_music: status %d: %w", resp.StatusCode, music.ErrProviderUnavailable)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("apple_music: unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
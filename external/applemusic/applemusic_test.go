@@ -0,0 +1,68 @@
+package applemusic
+
+import (
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"context"
+	"errors"
+	"testing"
+)
+
+func setupFakeProvider(t *testing.T, token string) *Provider {
+	server := musictest.NewAppleMusicServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	return NewProvider(token, "br", server.URL, server.Client())
+}
+
+func TestProvider_SearchPlaylists(t *testing.T) {
+	provider := setupFakeProvider(t, "developer-token")
+
+	hits, err := provider.SearchPlaylists(context.Background(), "stout", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != "1002" {
+		t.Errorf("Expected single hit for playlist 1002, got %+v", hits)
+	}
+}
+
+func TestProvider_GetPlaylist(t *testing.T) {
+	provider := setupFakeProvider(t, "developer-token")
+
+	playlist, err := provider.GetPlaylist(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if playlist.Provider != music.ProviderAppleMusic || len(playlist.Tracks) != 3 {
+		t.Fatalf("Expected 3 Apple Music tracks, got %+v", playlist)
+	}
+
+	track := playlist.Tracks[1]
+	if track.Link != "https://music.apple.com/br/song/2002" {
+		t.Errorf("Expected Apple Music link, got '%s'", track.Link)
+	}
+	if !track.Explicit || track.AlbumArtURL != "https://is1-ssl.mzstatic.com/2002/600x600bb.jpg" {
+		t.Errorf("Expected explicit flag and sized artwork, got %+v", track)
+	}
+}
+
+func TestProvider_GetPlaylist_NotFound(t *testing.T) {
+	provider := setupFakeProvider(t, "developer-token")
+
+	_, err := provider.GetPlaylist(context.Background(), "pl.missing")
+	if !errors.Is(err, music.ErrPlaylistNotFound) {
+		t.Errorf("Expected ErrPlaylistNotFound, got %v", err)
+	}
+}
+
+func TestProvider_Unauthorized(t *testing.T) {
+	provider := setupFakeProvider(t, "")
+
+	_, err := provider.SearchPlaylists(context.Background(), "stout", 3)
+	if !errors.Is(err, music.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable without developer token, got %v", err)
+	}
+}
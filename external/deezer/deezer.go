@@ -0,0 +1,182 @@
+// Package deezer implements music.Provider on top of the public Deezer API,
+// which needs no credentials for catalog reads.
+package deezer
+
+import (
+	"backend-test/external/music"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.deezer.com"
+
+// dataNotFoundCode is the Deezer error code for unknown IDs. Deezer reports
+// errors in the body with HTTP 200.
+const dataNotFoundCode = 800
+
+type Provider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewProvider(baseURL string, httpClient *http.Client) *Provider {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{baseURL: baseURL, httpClient: httpClient}
+}
+
+func (p *Provider) Name() string {
+	return music.ProviderDeezer
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+type searchResponse struct {
+	Data []struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	} `json:"data"`
+	Error *apiError `json:"error"`
+}
+
+type playlistResponse struct {
+	ID     int64  `json:"id"`
+	Title  string `json:"title"`
+	Tracks struct {
+		Data []struct {
+			ID             int64  `json:"id"`
+			Title          string `json:"title"`
+			Link           string `json:"link"`
+			Duration       int    `json:"duration"`
+			ExplicitLyrics bool   `json:"explicit_lyrics"`
+			Preview        string `json:"preview"`
+			ISRC           string `json:"isrc"`
+			Artist         struct {
+				Name string `json:"name"`
+			} `json:"artist"`
+			Album struct {
+				Title   string `json:"title"`
+				CoverXL string `json:"cover_xl"`
+			} `json:"album"`
+		} `json:"data"`
+	} `json:"tracks"`
+	Error *apiError `json:"error"`
+}
+
+func (p *Provider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var response searchResponse
+	if err := p.get(ctx, "/search/playlist?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	if err := checkError(response.Error); err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("deezer: %w", music.ErrPlaylistNotFound)
+	}
+
+	summaries := make([]music.PlaylistSummary, 0, len(response.Data))
+	for _, hit := range response.Data {
+		summaries = append(summaries, music.PlaylistSummary{ID: strconv.FormatInt(hit.ID, 10), Name: hit.Title})
+	}
+	return summaries, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	var response playlistResponse
+	if err := p.get(ctx, "/playlist/"+url.PathEscape(playlistID), &response); err != nil {
+		return nil, err
+	}
+	if err := checkError(response.Error); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]music.Track, 0, len(response.Tracks.Data))
+	for _, track := range response.Tracks.Data {
+		if track.Title == "" {
+			continue
+		}
+
+		var artists []string
+		if track.Artist.Name != "" {
+			artists = []string{track.Artist.Name}
+		}
+
+		link := track.Link
+		if link == "" {
+			link = fmt.Sprintf("https://www.deezer.com/track/%d", track.ID)
+		}
+
+		tracks = append(tracks, music.Track{
+			ID:          strconv.FormatInt(track.ID, 10),
+			Name:        track.Title,
+			Artists:     artists,
+			Album:       track.Album.Title,
+			DurationMs:  track.Duration * 1000,
+			Explicit:    track.ExplicitLyrics,
+			PreviewURL:  track.Preview,
+			ISRC:        track.ISRC,
+			AlbumArtURL: track.Album.CoverXL,
+			Link:        link,
+		})
+	}
+
+	return &music.Playlist{
+		ID:       strconv.FormatInt(response.ID, 10),
+		Name:     response.Title,
+		Provider: music.ProviderDeezer,
+		Tracks:   tracks,
+	}, nil
+}
+
+func (p *Provider) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("deezer: %v: %w", err, music.ErrProviderUnavailable)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("deezer: %w", music.ErrPlaylistNotFound)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("deezer: status %d: %w", resp.StatusCode, music.ErrProviderUnavailable)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("deezer: unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func checkError(apiErr *apiError) error {
+	if apiErr == nil {
+		return nil
+	}
+	if apiErr.Code == dataNotFoundCode {
+		return fmt.Errorf("deezer: %s: %w", apiErr.Message, music.ErrPlaylistNotFound)
+	}
+	return fmt.Errorf("deezer: %s (code %d)", apiErr.Message, apiErr.Code)
+}
@@ -0,0 +1,59 @@
+package deezer
+
+import (
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"context"
+	"errors"
+	"testing"
+)
+
+func setupFakeProvider(t *testing.T) *Provider {
+	server := musictest.NewDeezerServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	return NewProvider(server.URL, server.Client())
+}
+
+func TestProvider_SearchPlaylists(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	hits, err := provider.SearchPlaylists(context.Background(), "stout", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != "1002" || hits[0].Name != "Stout Nights" {
+		t.Errorf("Expected single hit for playlist 1002, got %+v", hits)
+	}
+}
+
+func TestProvider_GetPlaylist(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	playlist, err := provider.GetPlaylist(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if playlist.Provider != music.ProviderDeezer || len(playlist.Tracks) != 3 {
+		t.Fatalf("Expected 3 Deezer tracks, got %+v", playlist)
+	}
+
+	track := playlist.Tracks[0]
+	if track.Link != "https://www.deezer.com/track/2001" {
+		t.Errorf("Expected Deezer link, got '%s'", track.Link)
+	}
+	if track.DurationMs != 215000 || track.Artists[0] != "The Brewers" {
+		t.Errorf("Expected track details to be mapped, got %+v", track)
+	}
+}
+
+func TestProvider_GetPlaylist_NotFound(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	_, err := provider.GetPlaylist(context.Background(), "999")
+	if !errors.Is(err, music.ErrPlaylistNotFound) {
+		t.Errorf("Expected ErrPlaylistNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package music defines the provider-neutral playlist model and the registry
+// of music providers (Spotify, Deezer, YouTube Music, Apple Music) used by
+// the recommendation service.
+package music
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	ProviderSpotify      = "spotify"
+	ProviderDeezer       = "deezer"
+	ProviderYouTubeMusic = "youtube_music"
+	ProviderAppleMusic   = "apple_music"
+)
+
+var (
+	// ErrPlaylistNotFound is returned when a search has no hits or a
+	// playlist ID does not exist on the provider.
+	ErrPlaylistNotFound = errors.New("playlist not found")
+	// ErrProviderUnavailable is returned when a provider is not configured
+	// or cannot currently be reached.
+	ErrProviderUnavailable = errors.New("music provider unavailable")
+)
+
+// SupportedProviders lists every provider name accepted in requests.
+func SupportedProviders() []string {
+	return []string{ProviderSpotify, ProviderDeezer, ProviderYouTubeMusic, ProviderAppleMusic}
+}
+
+// IsSupported reports whether name is one of SupportedProviders.
+func IsSupported(name string) bool {
+	for _, provider := range SupportedProviders() {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+type PlaylistSummary struct {
+	ID   string
+	Name string
+}
+
+type Playlist struct {
+	ID       string
+	Name     string
+	Provider string
+	Tracks   []Track
+}
+
+type Track struct {
+	ID          string
+	Name        string
+	Artists     []string
+	Album       string
+	DurationMs  int
+	Explicit    bool
+	Popularity  int
+	PreviewURL  string
+	ISRC        string
+	AlbumArtURL string
+	// Link is the provider-specific URL to open the track.
+	Link string
+}
+
+// Provider searches and fetches playlists from a music service.
+type Provider interface {
+	Name() string
+	SearchPlaylists(ctx context.Context, query string, limit int) ([]PlaylistSummary, error)
+	GetPlaylist(ctx context.Context, playlistID string) (*Playlist, error)
+}
+
+// Registry holds the configured providers keyed by name.
+type Registry struct {
+	providers       map[string]Provider
+	defaultProvider string
+}
+
+func NewRegistry(defaultProvider string, providers ...Provider) *Registry {
+	registry := &Registry{
+		providers:       make(map[string]Provider, len(providers)),
+		defaultProvider: defaultProvider,
+	}
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+	return registry
+}
+
+func (r *Registry) Register(provider Provider) {
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider registered under name, or the default provider
+// when name is empty.
+func (r *Registry) Get(name string) (Provider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = r.defaultProvider
+	}
+
+	if !IsSupported(name) {
+		return nil, fmt.Errorf("unsupported music provider '%s'", name)
+	}
+
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%s service unavailable: %w", name, ErrProviderUnavailable)
+	}
+	return provider, nil
+}
+
+func (r *Registry) DefaultProvider() string {
+	return r.defaultProvider
+}
+
+// Names returns the registered provider names in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ping checks that the provider is reachable by running a minimal search. A
+// search without results still proves the provider is answering.
+func Ping(ctx context.Context, provider Provider) error {
+	_, err := provider.SearchPlaylists(ctx, "beer", 1)
+	if err != nil && !errors.Is(err, ErrPlaylistNotFound) {
+		return err
+	}
+	return nil
+}
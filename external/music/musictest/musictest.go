@@ -0,0 +1,326 @@
+// Package musictest provides HTTP-level fakes of the Spotify, Deezer,
+// YouTube Data and Apple Music APIs serving a fixed catalog, so providers and
+// the services built on them can be tested without network access.
+package musictest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+type Track struct {
+	ID         string
+	Name       string
+	Artist     string
+	Album      string
+	DurationMs int
+	Explicit   bool
+	ISRC       string
+	PreviewURL string
+}
+
+type Playlist struct {
+	ID     string
+	Name   string
+	Tracks []Track
+}
+
+// Catalog is the content served by the fakes. Searches match playlists whose
+// name contains the query, case-insensitively.
+type Catalog struct {
+	Playlists []Playlist
+}
+
+func (c Catalog) search(query string, limit int) []Playlist {
+	query = strings.ToLower(query)
+	hits := make([]Playlist, 0)
+	for _, playlist := range c.Playlists {
+		if strings.Contains(strings.ToLower(playlist.Name), query) {
+			hits = append(hits, playlist)
+		}
+		if limit > 0 && len(hits) == limit {
+			break
+		}
+	}
+	return hits
+}
+
+func (c Catalog) find(id string) (Playlist, bool) {
+	for _, playlist := range c.Playlists {
+		if playlist.ID == id {
+			return playlist, true
+		}
+	}
+	return Playlist{}, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func limitParam(r *http.Request, name string) int {
+	limit, _ := strconv.Atoi(r.URL.Query().Get(name))
+	return limit
+}
+
+// NewSpotifyServer fakes the Spotify accounts token endpoint at /api/token
+// and the Web API under /v1/.
+func NewSpotifyServer(catalog Catalog) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/token", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": "fake-token",
+			"token_type":   "bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		items := make([]map[string]interface{}, 0)
+		for _, playlist := range catalog.search(r.URL.Query().Get("q"), limitParam(r, "limit")) {
+			items = append(items, map[string]interface{}{"id": playlist.ID, "name": playlist.Name})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"playlists": map[string]interface{}{"items": items, "total": len(items)},
+		})
+	})
+
+	mux.HandleFunc("/v1/playlists/", func(w http.ResponseWriter, r *http.Request) {
+		playlist, ok := catalog.find(strings.TrimPrefix(r.URL.Path, "/v1/playlists/"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{
+				"error": map[string]interface{}{"status": http.StatusNotFound, "message": "Resource not found"},
+			})
+			return
+		}
+
+		items := make([]map[string]interface{}, 0, len(playlist.Tracks))
+		for _, track := range playlist.Tracks {
+			items = append(items, map[string]interface{}{
+				"track": map[string]interface{}{
+					"id":           track.ID,
+					"name":         track.Name,
+					"artists":      []map[string]string{{"name": track.Artist}},
+					"album":        map[string]interface{}{"name": track.Album, "images": []map[string]string{{"url": "https://i.scdn.co/image/" + track.ID}}},
+					"duration_ms":  track.DurationMs,
+					"explicit":     track.Explicit,
+					"preview_url":  track.PreviewURL,
+					"external_ids": map[string]string{"isrc": track.ISRC},
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":     playlist.ID,
+			"name":   playlist.Name,
+			"tracks": map[string]interface{}{"items": items, "total": len(items)},
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// NewDeezerServer fakes the public Deezer API, including its habit of
+// reporting unknown IDs with HTTP 200 and an error body.
+func NewDeezerServer(catalog Catalog) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/search/playlist", func(w http.ResponseWriter, r *http.Request) {
+		data := make([]map[string]interface{}, 0)
+		for _, playlist := range catalog.search(r.URL.Query().Get("q"), limitParam(r, "limit")) {
+			id, _ := strconv.ParseInt(playlist.ID, 10, 64)
+			data = append(data, map[string]interface{}{"id": id, "title": playlist.Name})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "total": len(data)})
+	})
+
+	mux.HandleFunc("/playlist/", func(w http.ResponseWriter, r *http.Request) {
+		playlist, ok := catalog.find(strings.TrimPrefix(r.URL.Path, "/playlist/"))
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"error": map[string]interface{}{"type": "DataException", "message": "no data", "code": 800},
+			})
+			return
+		}
+
+		data := make([]map[string]interface{}, 0, len(playlist.Tracks))
+		for _, track := range playlist.Tracks {
+			id, _ := strconv.ParseInt(track.ID, 10, 64)
+			data = append(data, map[string]interface{}{
+				"id":              id,
+				"title":           track.Name,
+				"link":            "https://www.deezer.com/track/" + track.ID,
+				"duration":        track.DurationMs / 1000,
+				"explicit_lyrics": track.Explicit,
+				"preview":         track.PreviewURL,
+				"isrc":            track.ISRC,
+				"artist":          map[string]string{"name": track.Artist},
+				"album":           map[string]string{"title": track.Album, "cover_xl": "https://e-cdns-images.dzcdn.net/" + track.ID},
+			})
+		}
+		id, _ := strconv.ParseInt(playlist.ID, 10, 64)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":     id,
+			"title":  playlist.Name,
+			"tracks": map[string]interface{}{"data": data},
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// NewYouTubeMusicServer fakes the YouTube Data API v3 search, playlists and
+// playlistItems endpoints. Requests without a key are rejected with 403.
+func NewYouTubeMusicServer(catalog Catalog) *httptest.Server {
+	mux := http.NewServeMux()
+
+	requireKey := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("key") == "" {
+				writeJSON(w, http.StatusForbidden, map[string]interface{}{"error": map[string]interface{}{"code": 403}})
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	mux.HandleFunc("/search", requireKey(func(w http.ResponseWriter, r *http.Request) {
+		items := make([]map[string]interface{}, 0)
+		for _, playlist := range catalog.search(r.URL.Query().Get("q"), limitParam(r, "maxResults")) {
+			items = append(items, map[string]interface{}{
+				"id":      map[string]string{"kind": "youtube#playlist", "playlistId": playlist.ID},
+				"snippet": map[string]string{"title": playlist.Name},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	}))
+
+	mux.HandleFunc("/playlists", requireKey(func(w http.ResponseWriter, r *http.Request) {
+		items := make([]map[string]interface{}, 0)
+		if playlist, ok := catalog.find(r.URL.Query().Get("id")); ok {
+			items = append(items, map[string]interface{}{
+				"id":      playlist.ID,
+				"snippet": map[string]string{"title": playlist.Name},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	}))
+
+	mux.HandleFunc("/playlistItems", requireKey(func(w http.ResponseWriter, r *http.Request) {
+		playlist, ok := catalog.find(r.URL.Query().Get("playlistId"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]interface{}{"code": 404}})
+			return
+		}
+
+		items := make([]map[string]interface{}, 0, len(playlist.Tracks))
+		for _, track := range playlist.Tracks {
+			items = append(items, map[string]interface{}{
+				"snippet": map[string]interface{}{
+					"title":                  track.Name,
+					"videoOwnerChannelTitle": track.Artist + " - Topic",
+					"thumbnails":             map[string]interface{}{"high": map[string]string{"url": "https://i.ytimg.com/vi/" + track.ID + "/hqdefault.jpg"}},
+				},
+				"contentDetails": map[string]string{"videoId": track.ID},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	}))
+
+	return httptest.NewServer(mux)
+}
+
+// NewAppleMusicServer fakes the Apple Music catalog API for any storefront.
+// Requests without a bearer token are rejected with 401.
+func NewAppleMusicServer(catalog Catalog) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"errors": []map[string]string{{"status": "401"}}})
+			return
+		}
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/catalog/"), "/")
+		switch {
+		case len(parts) == 2 && parts[1] == "search":
+			data := make([]map[string]interface{}, 0)
+			for _, playlist := range catalog.search(r.URL.Query().Get("term"), limitParam(r, "limit")) {
+				data = append(data, map[string]interface{}{
+					"id":         playlist.ID,
+					"type":       "playlists",
+					"attributes": map[string]string{"name": playlist.Name},
+				})
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"results": map[string]interface{}{"playlists": map[string]interface{}{"data": data}},
+			})
+		case len(parts) == 3 && parts[1] == "playlists":
+			playlist, ok := catalog.find(parts[2])
+			if !ok {
+				writeJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []map[string]string{{"status": "404"}}})
+				return
+			}
+
+			songs := make([]map[string]interface{}, 0, len(playlist.Tracks))
+			for _, track := range playlist.Tracks {
+				contentRating := ""
+				if track.Explicit {
+					contentRating = "explicit"
+				}
+				songs = append(songs, map[string]interface{}{
+					"id":   track.ID,
+					"type": "songs",
+					"attributes": map[string]interface{}{
+						"name":             track.Name,
+						"artistName":       track.Artist,
+						"albumName":        track.Album,
+						"durationInMillis": track.DurationMs,
+						"contentRating":    contentRating,
+						"isrc":             track.ISRC,
+						"url":              "https://music.apple.com/" + parts[0] + "/song/" + track.ID,
+						"artwork":          map[string]string{"url": "https://is1-ssl.mzstatic.com/" + track.ID + "/{w}x{h}bb.jpg"},
+						"previews":         []map[string]string{{"url": track.PreviewURL}},
+					},
+				})
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{{
+					"id":            playlist.ID,
+					"type":          "playlists",
+					"attributes":    map[string]string{"name": playlist.Name},
+					"relationships": map[string]interface{}{"tracks": map[string]interface{}{"data": songs}},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// SampleCatalog is a small catalog with numeric IDs, valid for every fake.
+func SampleCatalog() Catalog {
+	return Catalog{
+		Playlists: []Playlist{
+			{
+				ID:   "1001",
+				Name: "IPA Session",
+				Tracks: []Track{
+					{ID: "2001", Name: "Hop Anthem", Artist: "The Brewers", Album: "Bitter", DurationMs: 215000, ISRC: "USAAA0000001", PreviewURL: "https://preview.example/2001.mp3"},
+					{ID: "2002", Name: "West Coast", Artist: "The Brewers", Album: "Bitter", DurationMs: 198000, Explicit: true, ISRC: "USAAA0000002"},
+					{ID: "2003", Name: "Citra Dreams", Artist: "Lupulin", Album: "Dry Hop", DurationMs: 243000, ISRC: "USAAA0000003"},
+				},
+			},
+			{
+				ID:   "1002",
+				Name: "Stout Nights",
+				Tracks: []Track{
+					{ID: "2004", Name: "Roasted", Artist: "Dark Malt", Album: "Porter House", DurationMs: 301000, ISRC: "USAAA0000004"},
+				},
+			},
+		},
+	}
+}
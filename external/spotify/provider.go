@@ -0,0 +1,106 @@
+package spotify
+
+import (
+	"backend-test/external/music"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Provider adapts SpotifyService to music.Provider. The service is resolved
+// on every call so token refreshes handled by the caller are picked up.
+type Provider struct {
+	service func(ctx context.Context) *SpotifyService
+}
+
+func NewProvider(service func(ctx context.Context) *SpotifyService) *Provider {
+	return &Provider{service: service}
+}
+
+func (p *Provider) Name() string {
+	return music.ProviderSpotify
+}
+
+func (p *Provider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	service := p.service(ctx)
+	if service == nil {
+		return nil, fmt.Errorf("spotify service unavailable: %w", music.ErrProviderUnavailable)
+	}
+
+	hits, err := service.SearchPlaylists(ctx, query, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	summaries := make([]music.PlaylistSummary, 0, len(hits))
+	for _, hit := range hits {
+		summaries = append(summaries, music.PlaylistSummary{ID: string(hit.ID), Name: hit.Name})
+	}
+	return summaries, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	service := p.service(ctx)
+	if service == nil {
+		return nil, fmt.Errorf("spotify service unavailable: %w", music.ErrProviderUnavailable)
+	}
+
+	playlist, err := service.GetPlaylist(ctx, spotify.ID(playlistID))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return convertPlaylist(playlist), nil
+}
+
+func convertPlaylist(playlist *spotify.FullPlaylist) *music.Playlist {
+	tracks := make([]music.Track, 0, len(playlist.Tracks.Tracks))
+	for _, item := range playlist.Tracks.Tracks {
+		track := item.Track
+		if track.Name == "" {
+			continue
+		}
+
+		artists := make([]string, 0, len(track.Artists))
+		for _, artist := range track.Artists {
+			artists = append(artists, artist.Name)
+		}
+
+		albumArtURL := ""
+		if len(track.Album.Images) > 0 {
+			albumArtURL = track.Album.Images[0].URL
+		}
+
+		tracks = append(tracks, music.Track{
+			ID:          string(track.ID),
+			Name:        track.Name,
+			Artists:     artists,
+			Album:       track.Album.Name,
+			DurationMs:  int(track.Duration),
+			Explicit:    track.Explicit,
+			Popularity:  int(track.Popularity),
+			PreviewURL:  track.PreviewURL,
+			ISRC:        track.ExternalIDs["isrc"],
+			AlbumArtURL: albumArtURL,
+			Link:        fmt.Sprintf("https://open.spotify.com/track/%s", track.ID),
+		})
+	}
+
+	return &music.Playlist{
+		ID:       string(playlist.ID),
+		Name:     playlist.Name,
+		Provider: music.ProviderSpotify,
+		Tracks:   tracks,
+	}
+}
+
+func mapError(err error) error {
+	var spotifyErr spotify.Error
+	if errors.As(err, &spotifyErr) && (spotifyErr.Status == http.StatusNotFound || spotifyErr.Status == http.StatusBadRequest) {
+		return fmt.Errorf("spotify: %v: %w", err, music.ErrPlaylistNotFound)
+	}
+	return fmt.Errorf("spotify: %w", err)
+}
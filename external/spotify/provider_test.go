@@ -0,0 +1,86 @@
+package spotify
+
+import (
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"context"
+	"errors"
+	"testing"
+)
+
+func setupFakeProvider(t *testing.T) *Provider {
+	server := musictest.NewSpotifyServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	service, err := NewSpotifyService(context.Background(), "client-id", "client-secret",
+		WithTokenURL(server.URL+"/api/token"),
+		WithAPIBaseURL(server.URL+"/v1/"),
+		WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create Spotify service: %v", err)
+	}
+
+	return NewProvider(func(ctx context.Context) *SpotifyService { return service })
+}
+
+func TestProvider_SearchPlaylists(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	hits, err := provider.SearchPlaylists(context.Background(), "ipa", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != "1001" {
+		t.Errorf("Expected single hit for playlist 1001, got %+v", hits)
+	}
+}
+
+func TestProvider_SearchPlaylists_NoHits(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	_, err := provider.SearchPlaylists(context.Background(), "lambic", 3)
+	if !errors.Is(err, music.ErrPlaylistNotFound) {
+		t.Errorf("Expected ErrPlaylistNotFound, got %v", err)
+	}
+}
+
+func TestProvider_GetPlaylist(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	playlist, err := provider.GetPlaylist(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if playlist.Provider != music.ProviderSpotify || len(playlist.Tracks) != 3 {
+		t.Fatalf("Expected 3 Spotify tracks, got %+v", playlist)
+	}
+
+	track := playlist.Tracks[1]
+	if track.Link != "https://open.spotify.com/track/2002" {
+		t.Errorf("Expected Spotify link, got '%s'", track.Link)
+	}
+	if !track.Explicit || track.ISRC != "USAAA0000002" || track.DurationMs != 198000 {
+		t.Errorf("Expected track details to be mapped, got %+v", track)
+	}
+}
+
+func TestProvider_GetPlaylist_NotFound(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	_, err := provider.GetPlaylist(context.Background(), "missing")
+	if !errors.Is(err, music.ErrPlaylistNotFound) {
+		t.Errorf("Expected ErrPlaylistNotFound, got %v", err)
+	}
+}
+
+func TestProvider_Unavailable(t *testing.T) {
+	provider := NewProvider(func(ctx context.Context) *SpotifyService { return nil })
+
+	_, err := provider.SearchPlaylists(context.Background(), "ipa", 3)
+	if !errors.Is(err, music.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
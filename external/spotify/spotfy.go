@@ -1,45 +1,131 @@
 package spotify
 
 import (
+	"backend-test/external/music"
 	"context"
-	"fmt"
+	"net/http"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 type SpotifyService struct {
 	client *spotify.Client
+	token  *oauth2.Token
 }
 
-func NewSpotifyService(clientID, clientSecret string) (*SpotifyService, error) {
+type options struct {
+	tokenURL   string
+	apiBaseURL string
+	httpClient *http.Client
+}
+
+// Option customizes the endpoints and HTTP client used by SpotifyService,
+// which lets tests point it at a fake server.
+type Option func(*options)
+
+func WithTokenURL(url string) Option {
+	return func(o *options) { o.tokenURL = url }
+}
+
+// WithAPIBaseURL sets the Web API base URL. It must end with a slash.
+func WithAPIBaseURL(url string) Option {
+	return func(o *options) { o.apiBaseURL = url }
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.httpClient = client }
+}
+
+// NewSpotifyService fetches a client credentials token and builds the Web API
+// client. ctx scopes the token request; later API calls use their own ctx.
+func NewSpotifyService(ctx context.Context, clientID, clientSecret string, opts ...Option) (*SpotifyService, error) {
+	o := options{tokenURL: spotifyauth.TokenURL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	config := &clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
-		TokenURL:     spotifyauth.TokenURL,
+		TokenURL:     o.tokenURL,
 	}
-	token, err := config.Token(context.Background())
+	token, err := config.Token(o.context(ctx))
 	if err != nil {
 		return nil, err
 	}
-	httpClient := spotifyauth.New().Client(context.Background(), token)
-	client := spotify.New(httpClient)
-	return &SpotifyService{client: client}, nil
+	return newSpotifyService(token, o), nil
+}
+
+// NewSpotifyServiceWithToken builds the Web API client on a token fetched
+// earlier, such as one shared through a cache, without requesting a new one.
+// The token is not refreshed: once it expires a new service is needed.
+func NewSpotifyServiceWithToken(token *oauth2.Token, opts ...Option) *SpotifyService {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newSpotifyService(token, o)
+}
+
+func newSpotifyService(token *oauth2.Token, o options) *SpotifyService {
+	httpClient := oauth2.NewClient(o.context(context.Background()), oauth2.StaticTokenSource(token))
+
+	var clientOpts []spotify.ClientOption
+	if o.apiBaseURL != "" {
+		clientOpts = append(clientOpts, spotify.WithBaseURL(o.apiBaseURL))
+	}
+	client := spotify.New(httpClient, clientOpts...)
+	return &SpotifyService{client: client, token: token}
+}
+
+// context returns the context the oauth2 package takes its HTTP client from.
+func (o options) context(ctx context.Context) context.Context {
+	ctx = context.WithoutCancel(ctx)
+	if o.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, o.httpClient)
+	}
+	return ctx
+}
+
+// Token returns the access token the service authenticates with.
+func (s *SpotifyService) Token() *oauth2.Token {
+	return s.token
 }
 
 func (s *SpotifyService) SearchPlaylistByName(name string) (*spotify.FullPlaylist, error) {
-	results, err := s.client.Search(context.Background(), name, spotify.SearchTypePlaylist)
+	playlists, err := s.SearchPlaylists(context.Background(), name, 1)
 	if err != nil {
 		return nil, err
 	}
-	if results.Playlists == nil || len(results.Playlists.Playlists) == 0 {
-		return nil, fmt.Errorf("playlist not found")
-	}
-	playlistID := results.Playlists.Playlists[0].ID
-	fullPlaylist, err := s.client.GetPlaylist(context.Background(), playlistID)
+	return s.GetPlaylist(context.Background(), playlists[0].ID)
+}
+
+// SearchPlaylists returns up to limit playlist hits for the query, in the
+// order ranked by Spotify. Empty entries returned by the API are skipped.
+func (s *SpotifyService) SearchPlaylists(ctx context.Context, query string, limit int) ([]spotify.SimplePlaylist, error) {
+	results, err := s.client.Search(ctx, query, spotify.SearchTypePlaylist, spotify.Limit(limit))
 	if err != nil {
 		return nil, err
 	}
-	return fullPlaylist, nil
+	if results.Playlists == nil {
+		return nil, music.ErrPlaylistNotFound
+	}
+
+	playlists := make([]spotify.SimplePlaylist, 0, len(results.Playlists.Playlists))
+	for _, playlist := range results.Playlists.Playlists {
+		if playlist.ID != "" {
+			playlists = append(playlists, playlist)
+		}
+	}
+	if len(playlists) == 0 {
+		return nil, music.ErrPlaylistNotFound
+	}
+	return playlists, nil
+}
+
+func (s *SpotifyService) GetPlaylist(ctx context.Context, playlistID spotify.ID) (*spotify.FullPlaylist, error) {
+	return s.client.GetPlaylist(ctx, playlistID)
 }
@@ -0,0 +1,182 @@
+// Package youtubemusic implements music.Provider using the YouTube Data API
+// v3, linking tracks to music.youtube.com.
+package youtubemusic
+
+import (
+	"backend-test/external/music"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// maxPlaylistItems is the page size limit of the playlistItems endpoint.
+const maxPlaylistItems = 50
+
+type Provider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewProvider(apiKey string, baseURL string, httpClient *http.Client) *Provider {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{apiKey: apiKey, baseURL: baseURL, httpClient: httpClient}
+}
+
+func (p *Provider) Name() string {
+	return music.ProviderYouTubeMusic
+}
+
+type thumbnails struct {
+	High struct {
+		URL string `json:"url"`
+	} `json:"high"`
+}
+
+type searchResponse struct {
+	Items []struct {
+		ID struct {
+			PlaylistID string `json:"playlistId"`
+		} `json:"id"`
+		Snippet struct {
+			Title string `json:"title"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+type playlistsResponse struct {
+	Items []struct {
+		ID      string `json:"id"`
+		Snippet struct {
+			Title string `json:"title"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+type playlistItemsResponse struct {
+	Items []struct {
+		Snippet struct {
+			Title                  string     `json:"title"`
+			VideoOwnerChannelTitle string     `json:"videoOwnerChannelTitle"`
+			Thumbnails             thumbnails `json:"thumbnails"`
+		} `json:"snippet"`
+		ContentDetails struct {
+			VideoID string `json:"videoId"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+func (p *Provider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("type", "playlist")
+	params.Set("q", query)
+	params.Set("maxResults", strconv.Itoa(limit))
+
+	var response searchResponse
+	if err := p.get(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]music.PlaylistSummary, 0, len(response.Items))
+	for _, item := range response.Items {
+		if item.ID.PlaylistID != "" {
+			summaries = append(summaries, music.PlaylistSummary{ID: item.ID.PlaylistID, Name: item.Snippet.Title})
+		}
+	}
+	if len(summaries) == 0 {
+		return nil, fmt.Errorf("youtube_music: %w", music.ErrPlaylistNotFound)
+	}
+	return summaries, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("id", playlistID)
+
+	var playlists playlistsResponse
+	if err := p.get(ctx, "/playlists", params, &playlists); err != nil {
+		return nil, err
+	}
+	if len(playlists.Items) == 0 {
+		return nil, fmt.Errorf("youtube_music: %w", music.ErrPlaylistNotFound)
+	}
+
+	params = url.Values{}
+	params.Set("part", "snippet,contentDetails")
+	params.Set("playlistId", playlistID)
+	params.Set("maxResults", strconv.Itoa(maxPlaylistItems))
+
+	var items playlistItemsResponse
+	if err := p.get(ctx, "/playlistItems", params, &items); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]music.Track, 0, len(items.Items))
+	for _, item := range items.Items {
+		videoID := item.ContentDetails.VideoID
+		title := item.Snippet.Title
+		if videoID == "" || title == "" || title == "Deleted video" || title == "Private video" {
+			continue
+		}
+
+		var artists []string
+		if artist := strings.TrimSuffix(item.Snippet.VideoOwnerChannelTitle, " - Topic"); artist != "" {
+			artists = []string{artist}
+		}
+
+		tracks = append(tracks, music.Track{
+			ID:          videoID,
+			Name:        title,
+			Artists:     artists,
+			AlbumArtURL: item.Snippet.Thumbnails.High.URL,
+			Link:        "https://music.youtube.com/watch?v=" + url.QueryEscape(videoID),
+		})
+	}
+
+	return &music.Playlist{
+		ID:       playlists.Items[0].ID,
+		Name:     playlists.Items[0].Snippet.Title,
+		Provider: music.ProviderYouTubeMusic,
+		Tracks:   tracks,
+	}, nil
+}
+
+func (p *Provider) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	params.Set("key", p.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("youtube_music: %v: %w", err, music.ErrProviderUnavailable)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("youtube_music: %w", music.ErrPlaylistNotFound)
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("youtube_music: status %d: %w", resp.StatusCode, music.ErrProviderUnavailable)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("youtube_music: unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
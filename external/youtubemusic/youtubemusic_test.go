@@ -0,0 +1,68 @@
+package youtubemusic
+
+import (
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"context"
+	"errors"
+	"testing"
+)
+
+func setupFakeProvider(t *testing.T, apiKey string) *Provider {
+	server := musictest.NewYouTubeMusicServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	return NewProvider(apiKey, server.URL, server.Client())
+}
+
+func TestProvider_SearchPlaylists(t *testing.T) {
+	provider := setupFakeProvider(t, "api-key")
+
+	hits, err := provider.SearchPlaylists(context.Background(), "IPA", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != "1001" {
+		t.Errorf("Expected single hit for playlist 1001, got %+v", hits)
+	}
+}
+
+func TestProvider_GetPlaylist(t *testing.T) {
+	provider := setupFakeProvider(t, "api-key")
+
+	playlist, err := provider.GetPlaylist(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if playlist.Provider != music.ProviderYouTubeMusic || playlist.Name != "IPA Session" || len(playlist.Tracks) != 3 {
+		t.Fatalf("Expected 3 YouTube Music tracks, got %+v", playlist)
+	}
+
+	track := playlist.Tracks[2]
+	if track.Link != "https://music.youtube.com/watch?v=2003" {
+		t.Errorf("Expected YouTube Music link, got '%s'", track.Link)
+	}
+	if track.Artists[0] != "Lupulin" {
+		t.Errorf("Expected ' - Topic' suffix to be trimmed, got '%s'", track.Artists[0])
+	}
+}
+
+func TestProvider_GetPlaylist_NotFound(t *testing.T) {
+	provider := setupFakeProvider(t, "api-key")
+
+	_, err := provider.GetPlaylist(context.Background(), "PLmissing")
+	if !errors.Is(err, music.ErrPlaylistNotFound) {
+		t.Errorf("Expected ErrPlaylistNotFound, got %v", err)
+	}
+}
+
+func TestProvider_MissingAPIKey(t *testing.T) {
+	provider := setupFakeProvider(t, "")
+
+	_, err := provider.SearchPlaylists(context.Background(), "IPA", 3)
+	if !errors.Is(err, music.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+}
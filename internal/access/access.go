@@ -0,0 +1,117 @@
+// Package access applies the role and the rate limit of each group of
+// operations for the APIs that do not route through Gin middlewares: gRPC
+// and GraphQL. The REST routes get the same rules from handler.NewAccess.
+package access
+
+import (
+	"backend-test/internal/auth"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/ratelimit"
+	"context"
+	"errors"
+)
+
+// Rule guards a group of operations. An empty Role lets anonymous callers
+// in; a zero Limit leaves the group unlimited.
+type Rule struct {
+	Role   domain.Role
+	Policy string
+	Limit  ratelimit.Limit
+}
+
+// Rules are the rules of reads, writes and recommendations.
+type Rules struct {
+	Read      Rule
+	Write     Rule
+	Recommend Rule
+}
+
+// NewRules applies the roles and rate limits of the REST routes: the editor
+// role for beer style changes, and the reader role for reads and
+// recommendations unless the configuration keeps them public.
+func NewRules(authCfg config.AuthConfig, rateLimitCfg config.RateLimitConfig) Rules {
+	rules := Rules{
+		Read:      Rule{Policy: "read", Limit: perMinute(rateLimitCfg.Read)},
+		Write:     Rule{Role: domain.RoleEditor, Policy: "write", Limit: perMinute(rateLimitCfg.Write)},
+		Recommend: Rule{Policy: "recommendation", Limit: perMinute(rateLimitCfg.Recommendation)},
+	}
+	if !authCfg.PublicReads {
+		rules.Read.Role = domain.RoleReader
+	}
+	if !authCfg.PublicRecommendations {
+		rules.Recommend.Role = domain.RoleReader
+	}
+	return rules
+}
+
+func perMinute(rule config.RateLimitRule) ratelimit.Limit {
+	return ratelimit.PerMinute(rule.RequestsPerMinute, rule.Burst)
+}
+
+// ErrAuthenticationRequired rejects anonymous callers of a rule with a role.
+var ErrAuthenticationRequired = errors.New("authentication required")
+
+// RoleError rejects callers below the role of a rule.
+type RoleError struct {
+	Role domain.Role
+}
+
+func (e *RoleError) Error() string {
+	return "the " + string(e.Role) + " role is required"
+}
+
+// LimitError rejects callers that used up their rate limit.
+type LimitError struct {
+	Policy string
+	Result ratelimit.Result
+}
+
+func (e *LimitError) Error() string {
+	return ratelimit.Detail(e.Policy, e.Result)
+}
+
+// Quota is the state of the bucket charged by Check, for the RateLimit
+// headers.
+type Quota struct {
+	Limit  ratelimit.Limit
+	Result ratelimit.Result
+}
+
+// Checker enforces rules. Its limiter is nil when rate limiting is
+// disabled.
+type Checker struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewChecker(limiter *ratelimit.Limiter) *Checker {
+	return &Checker{limiter: limiter}
+}
+
+// Check charges the rate limit of rule to the caller of ctx, keyed by its
+// principal or by clientIP when anonymous, and then checks its role, in the
+// order of the REST middlewares. The quota is nil when no limit applied or
+// the store failed; it is also returned with a LimitError.
+func (c *Checker) Check(ctx context.Context, rule Rule, clientIP string) (*Quota, error) {
+	var quota *Quota
+	if c != nil && c.limiter != nil && rule.Limit.Burst > 0 {
+		result, ok := c.limiter.Take(ctx, rule.Policy, ratelimit.ClientKey(ctx, clientIP), rule.Limit)
+		if ok {
+			quota = &Quota{Limit: rule.Limit, Result: result}
+			if !result.Allowed {
+				return quota, &LimitError{Policy: rule.Policy, Result: result}
+			}
+		}
+	}
+
+	if rule.Role != "" {
+		principal, ok := auth.PrincipalFrom(ctx)
+		if !ok {
+			return quota, ErrAuthenticationRequired
+		}
+		if !principal.Role.Allows(rule.Role) {
+			return quota, &RoleError{Role: rule.Role}
+		}
+	}
+	return quota, nil
+}
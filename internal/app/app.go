@@ -0,0 +1,322 @@
+// Package app wires the API: it builds the storage, cache, music providers,
+// services and controllers from the configuration and registers the routes.
+// Tests replace any dependency through the With* options.
+package app
+
+import (
+	"backend-test/external/music"
+	"backend-test/internal/access"
+	"backend-test/internal/auth"
+	"backend-test/internal/cache"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/graph"
+	"backend-test/internal/http/controller"
+	"backend-test/internal/http/handler"
+	"backend-test/internal/http/openapi"
+	"backend-test/internal/http/router"
+	"backend-test/internal/http/server"
+	"backend-test/internal/metrics"
+	"backend-test/internal/ratelimit"
+	"backend-test/internal/rpc"
+	"backend-test/internal/service"
+	postgres "backend-test/internal/storage/database"
+	"backend-test/internal/storage/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// App is the wired API. Router serves every route and Readiness is shared
+// with the HTTP server so /readyz fails once shutdown starts. GRPC serves
+// the same services over gRPC; it is nil when disabled.
+type App struct {
+	Config    *config.Config
+	Logger    *slog.Logger
+	Router    *gin.Engine
+	GRPC      *grpc.Server
+	Readiness *server.Readiness
+
+	closers []closer
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+type options struct {
+	logger                    *slog.Logger
+	beerRepository            repository.BeerRepositoryInterface
+	playlistMappingRepository repository.PlaylistMappingRepositoryInterface
+	apiKeyRepository          repository.APIKeyRepositoryInterface
+	historyRepository         repository.RecommendationHistoryRepositoryInterface
+	musicProviders            *music.Registry
+	cache                     cache.Cache
+	now                       func() time.Time
+}
+
+// Option replaces a dependency built by New.
+type Option func(*options)
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithBeerRepository replaces the Postgres beer style repository. The
+// database is not opened when every repository is replaced.
+func WithBeerRepository(beerRepository repository.BeerRepositoryInterface) Option {
+	return func(o *options) { o.beerRepository = beerRepository }
+}
+
+func WithPlaylistMappingRepository(playlistMappingRepository repository.PlaylistMappingRepositoryInterface) Option {
+	return func(o *options) { o.playlistMappingRepository = playlistMappingRepository }
+}
+
+func WithAPIKeyRepository(apiKeyRepository repository.APIKeyRepositoryInterface) Option {
+	return func(o *options) { o.apiKeyRepository = apiKeyRepository }
+}
+
+func WithRecommendationHistoryRepository(historyRepository repository.RecommendationHistoryRepositoryInterface) Option {
+	return func(o *options) { o.historyRepository = historyRepository }
+}
+
+// WithMusicProviders replaces the registry built from the music config.
+func WithMusicProviders(musicProviders *music.Registry) Option {
+	return func(o *options) { o.musicProviders = musicProviders }
+}
+
+// WithCache replaces the Redis cache. The given cache is closed by Close.
+func WithCache(c cache.Cache) Option {
+	return func(o *options) { o.cache = c }
+}
+
+// WithClock replaces time.Now for token expiry and recommendation seeds.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// New validates cfg and builds the App. Nothing is listening yet; serve
+// Router and call Close once it stops.
+func New(cfg *config.Config, opts ...Option) (*App, error) {
+	if cfg == nil {
+		return nil, errors.New("app: nil configuration")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	o := options{logger: slog.Default(), now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	app := &App{
+		Config:    cfg,
+		Logger:    o.logger,
+		Readiness: server.NewReadiness(),
+	}
+
+	var jwtVerifier *auth.JWTVerifier
+	if cfg.Auth.JWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.Auth.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		jwtVerifier = auth.NewJWTVerifier(keys, auth.JWTOptions{
+			Issuer:    cfg.Auth.Issuer,
+			Audience:  cfg.Auth.Audience,
+			RoleClaim: cfg.Auth.RoleClaim,
+			Leeway:    30 * time.Second,
+			Now:       o.now,
+		})
+	}
+
+	spec := handler.Spec()
+	if cfg.GraphQL.Enabled {
+		handler.DocumentGraphQL(spec)
+	}
+	validator, err := openapi.NewValidator(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var healthChecks []service.HealthCheck
+
+	beerRepo, playlistMappingRepo, apiKeyRepo, historyRepo := o.beerRepository, o.playlistMappingRepository, o.apiKeyRepository, o.historyRepository
+	if beerRepo == nil || playlistMappingRepo == nil || apiKeyRepo == nil || historyRepo == nil {
+		db := postgres.New(cfg.Database)
+		app.onClose("database pool", db.GracefulShutdown)
+		unregister := metrics.RegisterDBPool(db.PoolStat)
+		app.onClose("database pool metrics", func() error {
+			unregister()
+			return nil
+		})
+
+		if beerRepo == nil {
+			beerRepo = repository.NewInstrumentedBeerRepository(repository.NewBeerRepository(db), o.logger)
+		}
+		if playlistMappingRepo == nil {
+			playlistMappingRepo = repository.NewInstrumentedPlaylistMappingRepository(repository.NewPlaylistMappingRepository(db), o.logger)
+		}
+		if apiKeyRepo == nil {
+			apiKeyRepo = repository.NewInstrumentedAPIKeyRepository(repository.NewAPIKeyRepository(db), o.logger)
+		}
+		if historyRepo == nil {
+			historyRepo = repository.NewInstrumentedRecommendationHistoryRepository(repository.NewRecommendationHistoryRepository(db), o.logger)
+		}
+
+		healthChecks = append(healthChecks, service.HealthCheck{
+			Name:     "postgres",
+			Critical: cfg.Health.IsCritical("postgres"),
+			Timeout:  cfg.Health.CheckTimeout("postgres"),
+			Ping:     db.Ping,
+			Details:  db.Stats,
+		})
+	}
+
+	sharedCache := o.cache
+	if sharedCache == nil {
+		sharedCache = cache.NewRedis(config.NewRedisClient(cfg.Redis))
+	}
+	app.onClose("cache", sharedCache.Close)
+	healthChecks = append(healthChecks, service.HealthCheck{
+		Name:     "redis",
+		Critical: cfg.Health.IsCritical("redis"),
+		Timeout:  cfg.Health.CheckTimeout("redis"),
+		Ping:     sharedCache.Ping,
+	})
+
+	musicProviders := o.musicProviders
+	if musicProviders == nil {
+		spotifyManager := config.NewSpotifyManager(context.Background(), cfg.Spotify, sharedCache, o.now)
+		musicProviders = config.NewMusicRegistry(cfg.Music, spotifyManager)
+	}
+	healthChecks = append(healthChecks, musicProviderCheck(cfg.Health, musicProviders, o.now))
+
+	beerService := service.NewBeerService(beerRepo)
+	validationService := service.NewValidationService(beerService)
+	updateService := service.NewUpdateService()
+	playlistMappingService := service.NewPlaylistMappingService(playlistMappingRepo, musicProviders, o.logger)
+	historyService := service.NewRecommendationHistoryService(historyRepo, beerService, cfg.History, clientID, o.now, o.logger)
+	historyService.Start()
+	app.onClose("recommendation history", historyService.Close)
+	recommendationService := service.NewRecommendationService(beerService, playlistMappingService, musicProviders, historyService, cfg.Recommendation, o.now, o.logger)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, cfg.Auth.BootstrapAPIKey, o.logger)
+	healthService := service.NewHealthService(healthChecks...)
+
+	authenticator := auth.NewAuthenticator(apiKeyService, jwtVerifier, o.logger)
+	limiter := newLimiter(cfg.RateLimit, sharedCache, o)
+
+	httpAccess := handler.NewAccess(authenticator, limiter, cfg.Auth, cfg.RateLimit)
+
+	app.Router = router.NewRouter(cfg, o.logger)
+	handler.HandleProbes(app.Router, controller.NewHealthController(healthService, app.Readiness, o.logger))
+	handler.HandleMetrics(app.Router)
+	handler.HandleRequests(app.Router, handler.Controllers{
+		Beer:            controller.NewBeerController(beerService, validationService, updateService, o.logger),
+		Recommendation:  controller.NewRecommendationController(recommendationService, validationService, o.logger),
+		PlaylistMapping: controller.NewPlaylistMappingController(playlistMappingService, beerService, validationService, o.logger),
+		APIKey:          controller.NewAPIKeyController(apiKeyService, validationService, o.logger),
+		Analytics:       controller.NewAnalyticsController(historyService, o.logger),
+		Feedback:        controller.NewFeedbackController(historyService, validationService, o.logger),
+	}, httpAccess, handler.NewCaching(cfg.HTTPCache, cfg.Auth.PublicReads), validator.Middleware())
+	if cfg.GraphQL.Enabled {
+		graphServer, err := graph.NewServer(graph.Services{
+			Beer:            beerService,
+			Validation:      validationService,
+			Update:          updateService,
+			Recommendation:  recommendationService,
+			PlaylistMapping: playlistMappingService,
+		}, graph.Access{Checker: access.NewChecker(limiter), Rules: access.NewRules(cfg.Auth, cfg.RateLimit)}, cfg.GraphQL, o.logger)
+		if err != nil {
+			app.Close()
+			return nil, err
+		}
+		handler.HandleGraphQL(app.Router, graphServer, httpAccess)
+	}
+	if err := handler.HandleDocs(app.Router, spec); err != nil {
+		app.Close()
+		return nil, err
+	}
+
+	if cfg.GRPC.Enabled {
+		app.GRPC = rpc.NewServer(rpc.Servers{
+			BeerStyle:      rpc.NewBeerStyleServer(beerService, validationService, updateService, o.logger),
+			Recommendation: rpc.NewRecommendationServer(recommendationService, validationService, o.logger),
+		}, rpc.NewAccess(authenticator, limiter, cfg.Auth, cfg.RateLimit), cfg.GRPC.Reflection, o.logger)
+	}
+
+	return app, nil
+}
+
+// newLimiter returns the rate limiter, or nil when rate limiting is off. The
+// Redis store needs the Redis cache; with any other cache the buckets stay
+// in memory.
+func newLimiter(cfg config.RateLimitConfig, sharedCache cache.Cache, o options) *ratelimit.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	var store ratelimit.Store = ratelimit.NewMemory()
+	if cfg.Store == "redis" {
+		if redisCache, ok := sharedCache.(*cache.Redis); ok {
+			store = ratelimit.NewRedis(redisCache.Client())
+		} else {
+			o.logger.Warn("rate limit store redis needs the Redis cache, keeping buckets in memory")
+		}
+	}
+	return ratelimit.NewLimiter(store, o.now, o.logger)
+}
+
+// clientID names the caller of a request in the recommendation history.
+func clientID(ctx context.Context) string {
+	principal, _ := auth.PrincipalFrom(ctx)
+	return principal.Subject
+}
+
+// musicProviderCheck pings the default music provider. The ping is a real
+// search, so its outcome is reused for cfg.MusicProviderCacheTTL.
+func musicProviderCheck(cfg config.HealthConfig, musicProviders *music.Registry, now func() time.Time) service.HealthCheck {
+	return service.HealthCheck{
+		Name:     "music_provider",
+		Critical: cfg.IsCritical("music_provider"),
+		Timeout:  cfg.CheckTimeout("music_provider"),
+		Ping: service.CachedPing(func(ctx context.Context) error {
+			provider, err := musicProviders.Get("")
+			if err != nil {
+				return err
+			}
+			return music.Ping(ctx, provider)
+		}, cfg.MusicProviderCacheTTL, now),
+		Details: func() map[string]interface{} {
+			return map[string]interface{}{
+				"provider": musicProviders.DefaultProvider(),
+			}
+		},
+	}
+}
+
+func (a *App) onClose(name string, close func() error) {
+	a.closers = append(a.closers, closer{name: name, close: close})
+}
+
+// Close releases the resources opened by New in reverse order and reports
+// every failure. It is safe to call more than once.
+func (a *App) Close() error {
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		c := a.closers[i]
+		a.Logger.Info("closing resource", "resource", c.name)
+		if err := c.close(); err != nil {
+			a.Logger.Error("failed to close resource", "resource", c.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
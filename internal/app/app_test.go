@@ -0,0 +1,550 @@
+package app
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/external/deezer"
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"backend-test/internal/cache"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/http/handler"
+	"backend-test/internal/http/openapi"
+	"backend-test/internal/logging"
+	"backend-test/internal/storage/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type memoryBeerRepository struct {
+	repository.BeerRepositoryInterface
+	styles []domain.BeerStyle
+}
+
+func (m *memoryBeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return m.styles, nil
+}
+
+func (m *memoryBeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	for _, style := range m.styles {
+		if style.UUID == beerUUID {
+			return style, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	beerStyle.UUID = fmt.Sprintf("c8a1f1e2-0000-4000-8000-%012d", len(m.styles)+1)
+	m.styles = append(m.styles, beerStyle)
+	return beerStyle, nil
+}
+
+type memoryPlaylistMappingRepository struct {
+	repository.PlaylistMappingRepositoryInterface
+}
+
+func (m *memoryPlaylistMappingRepository) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	return nil, nil
+}
+
+func (m *memoryPlaylistMappingRepository) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) ([]domain.BeerStylePlaylist, error) {
+	return nil, nil
+}
+
+type memoryAPIKeyRepository struct {
+	keys []domain.APIKey
+}
+
+func (m *memoryAPIKeyRepository) CreateAPIKey(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	apiKey.UUID = fmt.Sprintf("0b7c2d4e-0000-4000-8000-%012d", len(m.keys)+1)
+	m.keys = append(m.keys, apiKey)
+	return apiKey, nil
+}
+
+func (m *memoryAPIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	for _, apiKey := range m.keys {
+		if apiKey.KeyHash == keyHash && apiKey.RevokedAt == nil {
+			return apiKey, nil
+		}
+	}
+	return domain.APIKey{}, sql.ErrNoRows
+}
+
+func (m *memoryAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return m.keys, nil
+}
+
+func (m *memoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	for i := range m.keys {
+		if m.keys[i].UUID == keyUUID && m.keys[i].RevokedAt == nil {
+			now := time.Now()
+			m.keys[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+type memoryHistoryRepository struct {
+	mu       sync.Mutex
+	records  []domain.RecommendationRecord
+	feedback []domain.RecommendationFeedback
+}
+
+func (m *memoryHistoryRepository) SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *memoryHistoryRepository) CountByBeerStyle(ctx context.Context, from, to time.Time) ([]domain.BeerStyleCount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var counts []domain.BeerStyleCount
+	for _, record := range m.records {
+		counts = append(counts, domain.BeerStyleCount{BeerStyle: record.BeerStyle, Count: 1, AvgLatencyMs: float64(record.LatencyMs)})
+	}
+	return counts, nil
+}
+
+func (m *memoryHistoryRepository) CountByTemperature(ctx context.Context, from, to time.Time, bucketSize float64) ([]domain.TemperatureBucketCount, error) {
+	return nil, nil
+}
+
+func (m *memoryHistoryRepository) CountByDay(ctx context.Context, from, to time.Time) ([]domain.DayCount, error) {
+	return nil, nil
+}
+
+func (m *memoryHistoryRepository) PurgeRecommendations(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryHistoryRepository) GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, record := range m.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return domain.RecommendationRecord{}, sql.ErrNoRows
+}
+
+func (m *memoryHistoryRepository) SaveFeedback(ctx context.Context, feedback domain.RecommendationFeedback) (domain.RecommendationFeedback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.feedback = append(m.feedback, feedback)
+	return feedback, nil
+}
+
+func (m *memoryHistoryRepository) CountFeedback(ctx context.Context, temperature, bucketSize float64) ([]domain.FeedbackTally, error) {
+	return nil, nil
+}
+
+func (m *memoryHistoryRepository) ListRatedRecommendations(ctx context.Context, from, to time.Time) ([]domain.RatedRecommendation, error) {
+	return nil, nil
+}
+
+func (m *memoryHistoryRepository) stored() []domain.RecommendationRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]domain.RecommendationRecord(nil), m.records...)
+}
+
+const bootstrapKey = "bootstrap-admin-key-with-32-characters"
+
+func setupApp(t *testing.T, extra ...Option) *App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	deezerServer := musictest.NewDeezerServer(musictest.SampleCatalog())
+	t.Cleanup(deezerServer.Close)
+
+	cfg := config.Default()
+	cfg.Database.URL = "postgres://unused@localhost:5432/beerdb"
+	cfg.Music.DefaultProvider = music.ProviderDeezer
+	cfg.Auth.BootstrapAPIKey = bootstrapKey
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	options := append([]Option{
+		WithLogger(logging.Discard()),
+		WithBeerRepository(&memoryBeerRepository{styles: []domain.BeerStyle{
+			{UUID: "c8a1f1e2-0000-4000-8000-000000000001", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale"},
+		}}),
+		WithPlaylistMappingRepository(&memoryPlaylistMappingRepository{}),
+		WithAPIKeyRepository(&memoryAPIKeyRepository{}),
+		WithRecommendationHistoryRepository(&memoryHistoryRepository{}),
+		WithMusicProviders(music.NewRegistry(music.ProviderDeezer, deezer.NewProvider(deezerServer.URL, deezerServer.Client()))),
+		WithCache(cache.NewMemory(func() time.Time { return now })),
+		WithClock(func() time.Time { return now }),
+	}, extra...)
+
+	application, err := New(cfg, options...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { application.Close() })
+	return application
+}
+
+func serve(application *App, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNew_ServesWithFakes(t *testing.T) {
+	application := setupApp(t)
+
+	w := serve(application, http.MethodGet, "/api/beer-styles/list", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"IPA"`) {
+		t.Fatalf("expected the fake beer styles, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Errorf("expected the listing to be cacheable for a minute, got %q", w.Header().Get("Cache-Control"))
+	}
+	if w := serve(application, http.MethodGet, "/api/beer-styles/list", "", "If-None-Match", w.Header().Get("ETag")); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected 304 for the current ETag, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(application, http.MethodPost, "/api/recommendations/suggest", `{"temperature": 8}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a recommendation, got %d %s", w.Code, w.Body.String())
+	}
+	var recommendation struct {
+		BeerStyle string `json:"beerStyle"`
+		Playlist  struct {
+			Name     string `json:"name"`
+			Provider string `json:"provider"`
+		} `json:"playlist"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &recommendation); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if recommendation.Playlist.Name != "IPA Session" || recommendation.Playlist.Provider != music.ProviderDeezer {
+		t.Errorf("expected IPA Session from Deezer, got %s", w.Body.String())
+	}
+}
+
+func TestNew_RecordsRecommendationHistory(t *testing.T) {
+	history := &memoryHistoryRepository{}
+	application := setupApp(t, WithRecommendationHistoryRepository(history))
+
+	w := serve(application, http.MethodPost, "/api/recommendations/suggest", `{"temperature": 8}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a recommendation, got %d %s", w.Code, w.Body.String())
+	}
+	var recommendation struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &recommendation); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	// Close writes the records still buffered.
+	application.Close()
+	records := history.stored()
+	if len(records) != 1 || records[0].ID != recommendation.ID || recommendation.ID == "" {
+		t.Fatalf("expected the recommendation %q to be stored, got %+v", recommendation.ID, records)
+	}
+	if records[0].BeerStyle != "IPA" || records[0].PlaylistID != "1001" || records[0].Provider != music.ProviderDeezer || records[0].ClientID != "bootstrap" {
+		t.Errorf("unexpected record: %+v", records[0])
+	}
+
+	if w := serve(application, http.MethodGet, "/api/analytics/recommendations", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected the analytics to require a key, got %d", w.Code)
+	}
+	w = serve(application, http.MethodGet, "/api/analytics/recommendations?bucket_size=2", "", "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":1`) || !strings.Contains(w.Body.String(), `"bucket_size":2`) {
+		t.Errorf("expected the analytics of the stored record, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := serve(application, http.MethodPost, "/api/recommendations/"+recommendation.ID+"/feedback", `{"vote": "up"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected the feedback to require a key, got %d", w.Code)
+	}
+	w = serve(application, http.MethodPost, "/api/recommendations/"+recommendation.ID+"/feedback", `{"vote": "down", "preferred_style": "ipa"}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusOK || len(history.feedback) != 1 || history.feedback[0].PreferredStyle != "IPA" {
+		t.Errorf("expected the feedback to be stored with the catalog name, got %d %s %+v", w.Code, w.Body.String(), history.feedback)
+	}
+	w = serve(application, http.MethodPost, "/api/recommendations/"+recommendation.ID+"/feedback", `{"vote": "up", "preferred_style": "Mead"}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected an unknown style to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+	w = serve(application, http.MethodPost, "/api/recommendations/c8a1f1e2-0000-4000-8000-00000000ffff/feedback", `{"vote": "up"}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown recommendation to be not found, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNew_ServesGRPC(t *testing.T) {
+	application := setupApp(t)
+
+	listener := bufconn.Listen(1 << 20)
+	go application.GRPC.Serve(listener)
+	t.Cleanup(application.GRPC.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	ctx := context.Background()
+
+	recommendation, err := beerv1.NewRecommendationServiceClient(conn).Recommend(ctx, &beerv1.RecommendRequest{Temperature: 8})
+	if err != nil {
+		t.Fatalf("expected a recommendation, got %v", err)
+	}
+	if recommendation.Playlist.GetName() != "IPA Session" || recommendation.Playlist.GetProvider() != music.ProviderDeezer {
+		t.Errorf("expected IPA Session from Deezer, got %v", recommendation)
+	}
+
+	beerStyles := beerv1.NewBeerStyleServiceClient(conn)
+	request := &beerv1.CreateBeerStyleRequest{Name: "Dubbel", TempMin: 8, TempMax: 12}
+	if _, err := beerStyles.CreateBeerStyle(ctx, request); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected writes to require credentials, got %v", err)
+	}
+	if _, err := beerStyles.CreateBeerStyle(metadata.AppendToOutgoingContext(ctx, "x-api-key", bootstrapKey), request); err != nil {
+		t.Fatalf("expected the bootstrap key to create, got %v", err)
+	}
+
+	w := serve(application, http.MethodGet, "/api/beer-styles/list", "")
+	if !strings.Contains(w.Body.String(), `"Dubbel"`) {
+		t.Errorf("expected the style created over gRPC to be listed over REST, got %s", w.Body.String())
+	}
+}
+
+func TestNew_ServesGraphQL(t *testing.T) {
+	application := setupApp(t)
+
+	w := serve(application, http.MethodPost, "/graphql", `{"query": "{ beerStyles { total items { name playlists { uuid } } } recommend(temperature: 8) { playlist { name provider } } }"}`)
+	expected := `{"data":{"beerStyles":{"items":[{"name":"IPA","playlists":[]}],"total":1},"recommend":{"playlist":{"name":"IPA Session","provider":"deezer"}}}}`
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Fatalf("expected the styles and a recommendation, got %d %s", w.Code, w.Body.String())
+	}
+
+	mutation := `{"query": "mutation { createBeerStyle(input: {name: \"Dubbel\", tempMin: 8, tempMax: 12}) { name } }"}`
+	w = serve(application, http.MethodPost, "/graphql", mutation)
+	if !strings.Contains(w.Body.String(), `"code":"UNAUTHENTICATED"`) {
+		t.Errorf("expected mutations to require credentials, got %s", w.Body.String())
+	}
+	w = serve(application, http.MethodPost, "/graphql", mutation, "X-API-Key", bootstrapKey)
+	if w.Body.String() != `{"data":{"createBeerStyle":{"name":"Dubbel"}}}` {
+		t.Errorf("expected the bootstrap key to create, got %s", w.Body.String())
+	}
+	if w := serve(application, http.MethodPost, "/graphql", `{"query": "{ beerStyles { total } }"}`, "X-API-Key", "bk_unknown"); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unknown key, got %d", w.Code)
+	}
+}
+
+func TestNew_CompressesNegotiatedListings(t *testing.T) {
+	application := setupApp(t)
+
+	w := serve(application, http.MethodGet, "/api/beer-styles/list", "", "Accept", "text/csv", "Accept-Encoding", "gzip")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("expected the listing as CSV, got %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected a listing under the threshold to stay uncompressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasPrefix(w.Body.String(), "uuid,name,") {
+		t.Errorf("unexpected CSV: %s", w.Body.String())
+	}
+
+	styles := make([]domain.BeerStyle, 50)
+	for i := range styles {
+		styles[i] = domain.BeerStyle{UUID: fmt.Sprintf("c8a1f1e2-0000-4000-8000-%012d", i), Name: fmt.Sprintf("Style %d", i), Category: "Ale"}
+	}
+	application = setupApp(t, WithBeerRepository(&memoryBeerRepository{styles: styles}))
+
+	w = serve(application, http.MethodGet, "/api/beer-styles/list", "", "Accept-Encoding", "br")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "br" || !strings.HasPrefix(w.Header().Get("ETag"), "W/") {
+		t.Errorf("expected a brotli listing with a weak ETag, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestNew_ReadinessSkipsPostgresWithFakeRepositories(t *testing.T) {
+	application := setupApp(t)
+
+	w := serve(application, http.MethodGet, "/readyz", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected ready, got %d %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "postgres") || !strings.Contains(w.Body.String(), "music_provider") {
+		t.Errorf("unexpected checks: %s", w.Body.String())
+	}
+
+	application.Readiness.SetReady(false)
+	if w := serve(application, http.MethodGet, "/readyz", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 once shutdown starts, got %d", w.Code)
+	}
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	if _, err := New(config.Default()); err == nil || !strings.Contains(err.Error(), "database.url") {
+		t.Errorf("expected a database.url error, got %v", err)
+	}
+}
+
+type closeRecorder struct {
+	cache.Cache
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestApp_CloseIsIdempotent(t *testing.T) {
+	recorder := &closeRecorder{Cache: cache.NewMemory(nil)}
+	application := setupApp(t, WithCache(recorder))
+
+	if err := application.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := application.Close(); err != nil {
+		t.Fatalf("expected no error on the second Close, got %v", err)
+	}
+	if recorder.closed != 1 {
+		t.Errorf("expected the cache to be closed once, got %d", recorder.closed)
+	}
+}
+
+func TestNew_WritesRequireAnEditor(t *testing.T) {
+	application := setupApp(t)
+	beerStyle := `{"name": "Gose", "temp_min": 4, "temp_max": 7}`
+
+	if w := serve(application, http.MethodPost, "/api/beer-styles/create", beerStyle); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d %s", w.Code, w.Body.String())
+	}
+
+	createKey := func(role string) string {
+		w := serve(application, http.MethodPost, "/api/auth/api-keys", `{"name": "ci", "role": "`+role+`"}`, "X-API-Key", bootstrapKey)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected the bootstrap key to create a key, got %d %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data domain.CreatedAPIKey `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		return response.Data.Key
+	}
+
+	readerKey := createKey("reader")
+	if w := serve(application, http.MethodPost, "/api/beer-styles/create", beerStyle, "X-API-Key", readerKey); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a reader, got %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(application, http.MethodGet, "/api/auth/api-keys", "", "X-API-Key", readerKey); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a reader listing keys, got %d", w.Code)
+	}
+
+	editorKey := createKey("editor")
+	if w := serve(application, http.MethodPost, "/api/beer-styles/create", beerStyle, "Authorization", "Bearer "+editorKey); w.Code != http.StatusCreated {
+		t.Errorf("expected an editor to create a beer style, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := serve(application, http.MethodPost, "/api/recommendations/suggest", `{"temperature": 8}`); w.Code != http.StatusOK {
+		t.Errorf("expected recommendations to stay public, got %d", w.Code)
+	}
+	if w := serve(application, http.MethodGet, "/api/beer-styles/list", "", "X-API-Key", "bk_unknown"); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unknown key even on a public route, got %d", w.Code)
+	}
+}
+
+func TestSpec_DocumentsEveryRoute(t *testing.T) {
+	application := setupApp(t)
+	spec := handler.Spec()
+	handler.DocumentGraphQL(spec)
+
+	registered := map[string]bool{}
+	for _, route := range application.Router.Routes() {
+		registered[route.Method+" "+openapi.Path(route.Path)] = true
+		if spec.Operation(route.Method, route.Path) == nil {
+			t.Errorf("%s %s is served but missing from the OpenAPI document", route.Method, route.Path)
+		}
+	}
+	for _, operation := range spec.Operations() {
+		if !registered[operation] {
+			t.Errorf("%s is documented but not served", operation)
+		}
+	}
+
+	w := serve(application, http.MethodGet, "/openapi.json", "")
+	var document struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatalf("invalid document: %v", err)
+	}
+	if document.OpenAPI != "3.1.0" || document.Paths["/api/beer-styles/edit/{beerUUID}"]["put"] == nil {
+		t.Errorf("unexpected document: %s", w.Body.String())
+	}
+
+	w = serve(application, http.MethodGet, "/docs", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Security-Policy"), "https://cdn.redoc.ly") {
+		t.Errorf("expected the docs page with its content policy, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestNew_ValidatesRequestBodiesAgainstTheSpec(t *testing.T) {
+	application := setupApp(t)
+
+	w := serve(application, http.MethodPost, "/api/beer-styles/create", `{"name": "Dubbel", "temp_min": "cold"}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if strings.Join(response.Errors, "\n") != "/temp_min: expected number, but got string\nmissing properties: 'temp_max'" {
+		t.Errorf("unexpected mismatches: %v", response.Errors)
+	}
+
+	if w := serve(application, http.MethodPost, "/api/beer-styles/create", `{"name": 1}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected authorization to come before validation, got %d", w.Code)
+	}
+	if w := serve(application, http.MethodPost, "/api/recommendations/suggest", `{"temperature": 8, "shuffle": "yes"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a mistyped preference to be rejected, got %d", w.Code)
+	}
+
+	w = serve(application, http.MethodPost, "/api/beer-styles/create", `{"name": "Dubbel", "temp_min": 8, "temp_max": 12}`, "X-API-Key", bootstrapKey)
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected the controller to read the validated body, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/service"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// writeJWKS writes a JWKS with an RSA key "rsa-1" and an EC key "ec-1".
+func writeJWKS(t *testing.T) (path string, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y)},
+		},
+	})
+
+	path = filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, document, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, rsaKey, ecKey
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func setupVerifier(t *testing.T) (*JWTVerifier, *rsa.PrivateKey, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	path, rsaKey, ecKey := writeJWKS(t)
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return NewJWTVerifier(keys, JWTOptions{
+		Issuer:   "https://issuer.example",
+		Audience: "beer-api",
+		Now:      func() time.Time { return testNow },
+	}), rsaKey, ecKey
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  "user-42",
+		"iss":  "https://issuer.example",
+		"aud":  "beer-api",
+		"exp":  testNow.Add(time.Hour).Unix(),
+		"role": []interface{}{"reader", "editor", "unknown"},
+	}
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	verifier, rsaKey, ecKey := setupVerifier(t)
+
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims()))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if principal.Subject != "user-42" || principal.Role != domain.RoleEditor || principal.Method != "jwt" {
+		t.Errorf("unexpected principal: %+v", principal)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims())); err != nil {
+		t.Errorf("expected the EC key to verify, got %v", err)
+	}
+}
+
+func TestJWTVerifier_Rejects(t *testing.T) {
+	verifier, rsaKey, ecKey := setupVerifier(t)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := map[string]string{
+		"expired":         sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("exp", testNow.Add(-time.Hour).Unix())),
+		"no expiry":       sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("exp", nil)),
+		"wrong issuer":    sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("iss", "https://evil.example")),
+		"wrong audience":  sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("aud", "other-api")),
+		"no role":         sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("role", "superuser")),
+		"no subject":      sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with("sub", nil)),
+		"unknown kid":     sign(t, jwt.SigningMethodRS256, "rsa-2", rsaKey, validClaims()),
+		"wrong key":       sign(t, jwt.SigningMethodRS256, "rsa-1", otherKey, validClaims()),
+		"key type swap":   sign(t, jwt.SigningMethodES256, "rsa-1", ecKey, validClaims()),
+		"symmetric (HS)":  sign(t, jwt.SigningMethodHS256, "rsa-1", []byte("secret"), validClaims()),
+		"not a JWT token": "not-a-token",
+	}
+
+	for name, token := range tests {
+		if _, err := verifier.Verify(token); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	for name, document := range map[string]string{
+		"not JSON":      "{",
+		"empty":         `{"keys": []}`,
+		"only enc keys": `{"keys": [{"kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"}]}`,
+		"bad curve":     `{"keys": [{"kty": "EC", "crv": "P-192", "x": "AQ", "y": "AQ"}]}`,
+		"symmetric key": `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`,
+	} {
+		if _, err := ParseJWKS([]byte(document)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+type stubAPIKeys struct {
+	keys map[string]domain.Principal
+	err  error
+}
+
+func (s stubAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error) {
+	if s.err != nil {
+		return domain.Principal{}, s.err
+	}
+	principal, ok := s.keys[key]
+	if !ok {
+		return domain.Principal{}, service.ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+func setupRouter(t *testing.T, apiKeys APIKeyAuthenticator) (*gin.Engine, *rsa.PrivateKey) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	verifier, rsaKey, _ := setupVerifier(t)
+	authenticator := NewAuthenticator(apiKeys, verifier, logging.Discard())
+
+	router := gin.New()
+	router.Use(authenticator.Middleware())
+	router.GET("/public", Public(), func(c *gin.Context) {
+		principal, _ := PrincipalFrom(c.Request.Context())
+		c.JSON(http.StatusOK, principal)
+	})
+	router.POST("/write", Require(domain.RoleEditor), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router, rsaKey
+}
+
+func request(router *gin.Engine, method, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_Roles(t *testing.T) {
+	router, rsaKey := setupRouter(t, stubAPIKeys{keys: map[string]domain.Principal{
+		"bk_reader": {Subject: "api_key:1", Role: domain.RoleReader, Method: "api_key"},
+		"bk_admin":  {Subject: "api_key:2", Role: domain.RoleAdmin, Method: "api_key"},
+	}})
+	editorToken := sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		headers  []string
+		expected int
+	}{
+		{"anonymous read", http.MethodGet, "/public", nil, http.StatusOK},
+		{"anonymous write", http.MethodPost, "/write", nil, http.StatusUnauthorized},
+		{"reader write", http.MethodPost, "/write", []string{APIKeyHeader, "bk_reader"}, http.StatusForbidden},
+		{"admin write", http.MethodPost, "/write", []string{"Authorization", "Bearer bk_admin"}, http.StatusNoContent},
+		{"editor JWT write", http.MethodPost, "/write", []string{"Authorization", "Bearer " + editorToken}, http.StatusNoContent},
+		{"unknown key on public route", http.MethodGet, "/public", []string{APIKeyHeader, "bk_unknown"}, http.StatusUnauthorized},
+		{"invalid JWT on public route", http.MethodGet, "/public", []string{"Authorization", "Bearer not-a-token"}, http.StatusUnauthorized},
+		{"basic auth is ignored", http.MethodPost, "/write", []string{"Authorization", "Basic dXNlcjpwYXNz"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		w := request(router, tt.method, tt.path, tt.headers...)
+		if w.Code != tt.expected {
+			t.Errorf("%s: expected %d, got %d %s", tt.name, tt.expected, w.Code, w.Body.String())
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected a WWW-Authenticate header", tt.name)
+		}
+	}
+}
+
+func TestMiddleware_APIKeyBackendFailure(t *testing.T) {
+	router, _ := setupRouter(t, stubAPIKeys{err: context.DeadlineExceeded})
+
+	if w := request(router, http.MethodPost, "/write", APIKeyHeader, "bk_any"); w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 when keys cannot be checked, got %d", w.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTOptions are the claims checked by a JWTVerifier besides the signature
+// and the expiry.
+type JWTOptions struct {
+	// Issuer and Audience are checked when set.
+	Issuer   string
+	Audience string
+	// RoleClaim names the claim holding the role, as a string or a list of
+	// strings. The highest known role wins.
+	RoleClaim string
+	// Leeway tolerates clock skew on exp and nbf.
+	Leeway time.Duration
+	// Now replaces time.Now, for tests.
+	Now func() time.Time
+}
+
+// JWTVerifier validates bearer tokens signed with RS256/384/512 or
+// ES256/384/512 by a key of the configured JWKS.
+type JWTVerifier struct {
+	keys    *KeySet
+	options JWTOptions
+	parser  *jwt.Parser
+}
+
+func NewJWTVerifier(keys *KeySet, options JWTOptions) *JWTVerifier {
+	if options.RoleClaim == "" {
+		options.RoleClaim = "role"
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(options.Leeway),
+		jwt.WithTimeFunc(options.Now),
+	}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
+
+	return &JWTVerifier{keys: keys, options: options, parser: jwt.NewParser(parserOptions...)}
+}
+
+// Verify checks the token and returns its subject and role.
+func (v *JWTVerifier) Verify(token string) (domain.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.keys.Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return domain.Principal{}, errors.New("token has no subject")
+	}
+
+	role, ok := highestRole(claims[v.options.RoleClaim])
+	if !ok {
+		return domain.Principal{}, fmt.Errorf("token has no valid %q claim", v.options.RoleClaim)
+	}
+
+	return domain.Principal{Subject: subject, Role: role, Method: "jwt"}, nil
+}
+
+func highestRole(claim interface{}) (domain.Role, bool) {
+	var values []interface{}
+	switch claim := claim.(type) {
+	case string:
+		values = []interface{}{claim}
+	case []interface{}:
+		values = claim
+	}
+
+	var best domain.Role
+	for _, value := range values {
+		role := domain.Role(fmt.Sprint(value))
+		if role.Valid() && (best == "" || role.Allows(best)) {
+			best = role
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,170 @@
+// Package auth authenticates API callers with API keys or JWTs and enforces
+// the minimum role of each route.
+package auth
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries an API key. Keys are also accepted as bearer tokens.
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyTokenPrefix tells API keys sent as bearer tokens apart from JWTs.
+const apiKeyTokenPrefix = "bk_"
+
+// APIKeyAuthenticator resolves API keys. It returns service.ErrInvalidAPIKey
+// for unknown or revoked keys; any other error is reported as a server
+// failure.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller authenticated by Middleware, if any.
+func PrincipalFrom(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(domain.Principal)
+	return principal, ok
+}
+
+// Authenticator resolves the credentials of a request. jwtVerifier is nil
+// when no JWKS is configured, in which case JWTs are rejected.
+type Authenticator struct {
+	apiKeys     APIKeyAuthenticator
+	jwtVerifier *JWTVerifier
+	logger      *slog.Logger
+}
+
+func NewAuthenticator(apiKeys APIKeyAuthenticator, jwtVerifier *JWTVerifier, logger *slog.Logger) *Authenticator {
+	return &Authenticator{apiKeys: apiKeys, jwtVerifier: jwtVerifier, logger: logger.With("component", "auth")}
+}
+
+// ErrInvalidCredentials is returned by Authenticate for credentials that
+// are unknown, revoked, expired or badly signed.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Authenticate resolves a credential found by Credentials. It wraps
+// ErrInvalidCredentials when the caller should be rejected; any other error
+// is a server failure.
+func (a *Authenticator) Authenticate(ctx context.Context, method, credential string) (domain.Principal, error) {
+	var principal domain.Principal
+	var err error
+	switch {
+	case method == "api_key":
+		principal, err = a.apiKeys.AuthenticateAPIKey(ctx, credential)
+	case a.jwtVerifier == nil:
+		err = errors.New("JWT authentication is not configured")
+	default:
+		principal, err = a.jwtVerifier.Verify(credential)
+	}
+
+	if err != nil {
+		if method == "api_key" && !errors.Is(err, service.ErrInvalidAPIKey) {
+			a.logger.ErrorContext(ctx, "authentication failed", "method", method, "err", err)
+			return domain.Principal{}, err
+		}
+
+		a.logger.WarnContext(ctx, "invalid credentials", "method", method, "err", err)
+		return domain.Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	return principal, nil
+}
+
+// Middleware authenticates the request when it carries credentials and
+// stores the caller in the request context. Requests without credentials
+// continue anonymously; Require decides whether the route allows that.
+// Invalid credentials are always rejected with 401.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		method, credential := Credentials(c.GetHeader(APIKeyHeader), c.GetHeader("Authorization"))
+		if credential == "" {
+			c.Next()
+			return
+		}
+
+		principal, err := a.Authenticate(ctx, method, credential)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidCredentials) {
+				abort(c, http.StatusInternalServerError, "failed to authenticate")
+				return
+			}
+
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			abort(c, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
+
+// Require rejects anonymous callers with 401 and callers below role with
+// 403.
+func Require(role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFrom(c.Request.Context())
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			abort(c, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		if !principal.Role.Allows(role) {
+			abort(c, http.StatusForbidden, "the "+string(role)+" role is required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Public lets every caller through. It stands in for Require on routes left
+// open by the configuration.
+func Public() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+	}
+}
+
+// Credentials returns the API key, or the bearer token of the
+// Authorization value, with the method it authenticates with. Both are empty
+// when neither carries a credential.
+func Credentials(apiKey, authorization string) (method string, credential string) {
+	if key := strings.TrimSpace(apiKey); key != "" {
+		return "api_key", key
+	}
+
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", ""
+	}
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, apiKeyTokenPrefix) {
+		return "api_key", token
+	}
+	return "jwt", token
+}
+
+func abort(c *gin.Context, status int, message string) {
+	body := gin.H{"message": message}
+	if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.AbortWithStatusJSON(status, body)
+}
@@ -0,0 +1,201 @@
+package beerctl
+
+import (
+	"backend-test/external/music"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	postgres "backend-test/internal/storage/database"
+	"backend-test/internal/storage/repository"
+	"backend-test/pkg/client"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Backend runs the commands, against the API or the database.
+type Backend interface {
+	ListBeerStyles(ctx context.Context) ([]domain.BeerStyle, error)
+	GetBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	UpdateBeerStyle(ctx context.Context, beerUUID string, changes domain.BeerStyleUpdateRequest) (domain.BeerStyle, error)
+	DeleteBeerStyle(ctx context.Context, beerUUID string) error
+	Recommend(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error)
+	Explain(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error)
+	Health(ctx context.Context) (*domain.HealthReport, error)
+	EvaluateRankings(ctx context.Context, from, to time.Time, weights []float64, bucketSize float64) ([]domain.RankingEvaluation, error)
+	Close() error
+}
+
+// ErrNeedsAPI is returned by the database backend for the commands that
+// need the music providers of the API.
+var ErrNeedsAPI = errors.New("not available with --database-url: run it against the API")
+
+// ErrNeedsDatabase is returned by the API backend for the commands that
+// read the recommendation history, which the API does not expose.
+var ErrNeedsDatabase = errors.New("only available with --database-url")
+
+// Connect returns the backend chosen by cfg.
+func Connect(cfg Config) (Backend, error) {
+	if cfg.DatabaseURL != "" {
+		return newDatabaseBackend(cfg.DatabaseURL), nil
+	}
+
+	opts := []client.Option{client.WithUserAgent("beerctl")}
+	if cfg.APIKey != "" {
+		opts = append(opts, client.WithAPIKey(cfg.APIKey))
+	}
+	if cfg.Token != "" {
+		opts = append(opts, client.WithBearerToken(cfg.Token))
+	}
+	c, err := client.New(cfg.APIURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return remoteBackend{c}, nil
+}
+
+type remoteBackend struct {
+	*client.Client
+}
+
+func (remoteBackend) EvaluateRankings(context.Context, time.Time, time.Time, []float64, float64) ([]domain.RankingEvaluation, error) {
+	return nil, fmt.Errorf("evaluate is %w", ErrNeedsDatabase)
+}
+
+func (remoteBackend) Close() error { return nil }
+
+// databaseBackend changes the beer styles without the API, for when it is
+// down. It applies the validations of the API, but skips its
+// authentication, rate limits and caches.
+type databaseBackend struct {
+	db             *postgres.DB
+	beer           *service.BeerService
+	validation     *service.ValidationService
+	update         *service.UpdateService
+	recommendation *service.RecommendationService
+	history        *service.RecommendationHistoryService
+}
+
+// newDatabaseBackend explains recommendations with the default provider
+// and fallback chain, having no music provider to call.
+func newDatabaseBackend(url string) *databaseBackend {
+	db := postgres.New(config.DatabaseConfig{URL: url, MaxConnections: 2})
+	beerService := service.NewBeerService(repository.NewBeerRepository(db))
+
+	defaults := config.Default()
+	providers := music.NewRegistry(defaults.Music.DefaultProvider)
+	playlistMappings := service.NewPlaylistMappingService(repository.NewPlaylistMappingRepository(db), providers, slog.Default())
+	return &databaseBackend{
+		db:             db,
+		beer:           beerService,
+		validation:     service.NewValidationService(beerService),
+		update:         service.NewUpdateService(),
+		recommendation: service.NewRecommendationService(beerService, playlistMappings, providers, nil, defaults.Recommendation, nil, slog.Default()),
+		// Never started: the history is only read.
+		history: service.NewRecommendationHistoryService(repository.NewRecommendationHistoryRepository(db), beerService, config.HistoryConfig{}, nil, nil, slog.Default()),
+	}
+}
+
+func (b *databaseBackend) ListBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	beerStyles, err := b.beer.ListAllBeerStyles(ctx)
+	if err != nil && !b.validation.IsNoRowsError(err) {
+		return nil, err
+	}
+	return beerStyles, nil
+}
+
+func (b *databaseBackend) GetBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	if err := b.validation.ValidateUUID(beerUUID); err != nil {
+		return domain.BeerStyle{}, err
+	}
+	beerStyle, err := b.beer.GetBeerStyleByUUID(ctx, beerUUID)
+	if b.validation.IsNoRowsError(err) {
+		return domain.BeerStyle{}, fmt.Errorf("beer style %s not found", beerUUID)
+	}
+	return beerStyle, err
+}
+
+func (b *databaseBackend) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	if beerStyle.Name == "" {
+		return domain.BeerStyle{}, errors.New("name is required")
+	}
+	if err := b.validation.ValidateUniqueNameForCreate(ctx, beerStyle.Name); err != nil {
+		return domain.BeerStyle{}, err
+	}
+	if err := b.validate(beerStyle); err != nil {
+		return domain.BeerStyle{}, err
+	}
+	return b.beer.CreateBeerStyle(ctx, beerStyle)
+}
+
+func (b *databaseBackend) UpdateBeerStyle(ctx context.Context, beerUUID string, changes domain.BeerStyleUpdateRequest) (domain.BeerStyle, error) {
+	beerStyle, err := b.GetBeerStyle(ctx, beerUUID)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
+	if changes.Name != nil && *changes.Name != beerStyle.Name {
+		if err := b.validation.ValidateUniqueNameForUpdate(ctx, *changes.Name, beerStyle.UUID); err != nil {
+			return domain.BeerStyle{}, err
+		}
+	}
+	if !b.update.ApplyBeerStyleUpdates(&beerStyle, changes) {
+		return beerStyle, nil
+	}
+	if err := b.validate(beerStyle); err != nil {
+		return domain.BeerStyle{}, err
+	}
+	return b.beer.UpdateBeerStyle(ctx, beerStyle)
+}
+
+func (b *databaseBackend) validate(beerStyle domain.BeerStyle) error {
+	if err := b.validation.ValidateTemperatureRange(beerStyle); err != nil {
+		return err
+	}
+	return b.validation.ValidateABV(beerStyle.ABV)
+}
+
+func (b *databaseBackend) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	if _, err := b.GetBeerStyle(ctx, beerUUID); err != nil {
+		return err
+	}
+	return b.beer.DeleteBeerStyle(ctx, beerUUID)
+}
+
+func (b *databaseBackend) Recommend(context.Context, domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
+	return nil, fmt.Errorf("recommend is %w", ErrNeedsAPI)
+}
+
+func (b *databaseBackend) Explain(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	if resolvePlaylist {
+		return nil, fmt.Errorf("resolving the playlist is %w", ErrNeedsAPI)
+	}
+	if err := b.validation.ValidateTemperatureInput(request.Temperature); err != nil {
+		return nil, err
+	}
+	if err := b.validation.ValidateRecommendationPreferences(request); err != nil {
+		return nil, err
+	}
+	return b.recommendation.ExplainRecommendation(ctx, request, false)
+}
+
+// Health reports the database only, the one dependency of this backend.
+func (b *databaseBackend) Health(ctx context.Context) (*domain.HealthReport, error) {
+	check := domain.HealthCheckResult{Name: "database", Status: domain.HealthStatusUp, Critical: true}
+	start := time.Now()
+	if err := b.db.Ping(ctx); err != nil {
+		check.Status, check.Error = domain.HealthStatusDown, err.Error()
+	}
+	check.LatencyMs = time.Since(start).Milliseconds()
+	return &domain.HealthReport{Status: check.Status, Checks: []domain.HealthCheckResult{check}}, nil
+}
+
+func (b *databaseBackend) EvaluateRankings(ctx context.Context, from, to time.Time, weights []float64, bucketSize float64) ([]domain.RankingEvaluation, error) {
+	return b.history.EvaluateRankings(ctx, from, to, weights, bucketSize)
+}
+
+func (b *databaseBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,179 @@
+// Package beerctl is the command-line tool to manage the beer styles and
+// inspect the recommendations, through the API or, for break-glass
+// operations, straight against the database.
+package beerctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `Usage: beerctl [flags] <command> [arguments]
+
+Commands:
+  styles list                       list the beer styles
+  styles get <uuid>                 show a beer style
+  styles create --name ... [flags]  create a beer style
+  styles update <uuid> [flags]      change the fields given as flags
+  styles delete <uuid> [--yes]      delete a beer style
+  styles import <file|-> [--dry-run]
+                                    create or update styles by name from JSON or YAML
+  styles export [--file path]       write every style as JSON or YAML
+  recommend --temp 8.5 [--unit C]   recommend a beer style and playlist
+  explain --temp 8.5 [--unit C]     rank every style for a temperature
+  health                            report the readiness of the API or database
+  evaluate [--from date] [--to date] [--weights 1,2]
+                                    compare the rankings against the recorded feedback
+                                    (needs --database-url)
+
+Flags:
+`
+
+// errUsage reports a command line that was rejected, after its message
+// was printed.
+var errUsage = errors.New("invalid usage")
+
+// errHelp reports that the help of a command was asked for and printed.
+var errHelp = errors.New("help requested")
+
+type cli struct {
+	in      io.Reader
+	out     io.Writer
+	errOut  io.Writer
+	getenv  func(string) string
+	connect func(Config) (Backend, error)
+
+	output  string
+	backend Backend
+}
+
+// Run runs the command line args, without the program name, and returns
+// the exit code: 0 on success, 1 when the command fails and 2 when args
+// are invalid.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{in: stdin, out: stdout, errOut: stderr, getenv: os.Getenv, connect: Connect}
+	return c.run(ctx, args)
+}
+
+func (c *cli) run(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("beerctl", flag.ContinueOnError)
+	flags.SetOutput(c.errOut)
+	flags.Usage = func() {
+		fmt.Fprint(c.errOut, usage)
+		flags.PrintDefaults()
+	}
+
+	var global globalFlags
+	flags.StringVar(&global.config, "config", "", "profiles file (default $BEERCTL_CONFIG or ~/.config/beerctl/config.yaml)")
+	flags.StringVar(&global.profile, "profile", "", "profile to use (default $BEERCTL_PROFILE or the current one of the file)")
+	flags.StringVar(&global.APIURL, "api", "", "API base URL (default $BEERCTL_API_URL or "+DefaultAPIURL+")")
+	flags.StringVar(&global.APIKey, "api-key", "", "API key (default $BEERCTL_API_KEY)")
+	flags.StringVar(&global.Token, "token", "", "JWT bearer token (default $BEERCTL_TOKEN)")
+	flags.StringVar(&global.DatabaseURL, "database-url", "", "work on the database instead of the API (default $BEERCTL_DATABASE_URL)")
+	flags.StringVar(&global.Output, "output", "", "output format: table, json or yaml (default $BEERCTL_OUTPUT or table)")
+	flags.StringVar(&global.Output, "o", "", "shorthand for -output")
+	timeout := flags.Duration("timeout", 30*time.Second, "time limit of the command")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	command, ok := c.commands()[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(c.errOut, "beerctl: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	cfg, err := resolveConfig(global, c.getenv)
+	if err != nil {
+		fmt.Fprintln(c.errOut, "beerctl:", err)
+		return 2
+	}
+	c.output = cfg.Output
+	if c.backend, err = c.connect(cfg); err != nil {
+		fmt.Fprintln(c.errOut, "beerctl:", err)
+		return 1
+	}
+	defer c.backend.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	switch err := command(ctx, flags.Args()[1:]); {
+	case errors.Is(err, errHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintln(c.errOut, "beerctl:", err)
+		return 1
+	}
+	return 0
+}
+
+type command func(ctx context.Context, args []string) error
+
+func (c *cli) commands() map[string]command {
+	return map[string]command{
+		"styles":    c.styles,
+		"recommend": c.recommend,
+		"explain":   c.explain,
+		"health":    c.health,
+		"evaluate":  c.evaluate,
+	}
+}
+
+// newFlagSet returns the flag set of a command, printing its errors.
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("beerctl "+name, flag.ContinueOnError)
+	flags.SetOutput(c.errOut)
+	return flags
+}
+
+// parse parses args with flags before, between or after the positional
+// arguments, and checks that there are exactly positional of them.
+func parse(flags *flag.FlagSet, args []string, positional int) ([]string, error) {
+	var values []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, errHelp
+			}
+			return nil, errUsage
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		values = append(values, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(values) != positional {
+		fmt.Fprintf(flags.Output(), "%s: expected %d argument(s), got %d\n", flags.Name(), positional, len(values))
+		return nil, errUsage
+	}
+	return values, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,435 @@
+package beerctl
+
+import (
+	"backend-test/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type fakeBackend struct {
+	styles  []domain.BeerStyle
+	updates []domain.BeerStyleUpdateRequest
+	request domain.TemperatureRequest
+	resolve bool
+	health  domain.HealthStatus
+	weights []float64
+	closed  bool
+}
+
+func (f *fakeBackend) ListBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return append([]domain.BeerStyle(nil), f.styles...), nil
+}
+
+func (f *fakeBackend) GetBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	for _, beerStyle := range f.styles {
+		if beerStyle.UUID == beerUUID {
+			return beerStyle, nil
+		}
+	}
+	return domain.BeerStyle{}, errors.New("beer style not found")
+}
+
+func (f *fakeBackend) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	beerStyle.UUID = fmt.Sprintf("uuid-%d", len(f.styles)+1)
+	f.styles = append(f.styles, beerStyle)
+	return beerStyle, nil
+}
+
+func (f *fakeBackend) UpdateBeerStyle(ctx context.Context, beerUUID string, changes domain.BeerStyleUpdateRequest) (domain.BeerStyle, error) {
+	f.updates = append(f.updates, changes)
+	for i := range f.styles {
+		if f.styles[i].UUID == beerUUID {
+			if changes.Name != nil {
+				f.styles[i].Name = *changes.Name
+			}
+			if changes.TempMax != nil {
+				f.styles[i].TempMax = *changes.TempMax
+			}
+			return f.styles[i], nil
+		}
+	}
+	return domain.BeerStyle{}, errors.New("beer style not found")
+}
+
+func (f *fakeBackend) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	for i := range f.styles {
+		if f.styles[i].UUID == beerUUID {
+			f.styles = append(f.styles[:i], f.styles[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("beer style not found")
+}
+
+func (f *fakeBackend) Recommend(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
+	f.request = request
+	return &domain.RecommendationResponse{
+		BeerStyle: "IPA",
+		Playlist:  domain.PlaylistInfo{Name: "IPA Rock", Tracks: []domain.TrackInfo{{Name: "Song", Artist: "Band"}}},
+	}, nil
+}
+
+func (f *fakeBackend) Explain(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	f.request, f.resolve = request, resolvePlaylist
+	return &domain.RecommendationExplanation{
+		Temperature: request.Temperature,
+		BeerStyle:   "Pilsner",
+		Candidates:  []domain.CandidateExplanation{{Name: "Pilsner", Midpoint: 4, Distance: 1, Rank: 1}},
+		Excluded:    []domain.ExcludedBeerStyle{{Name: "IPA", Constraint: "exclude_styles", Reason: "listed in exclude_styles"}},
+		Playlist: &domain.PlaylistSearchExplanation{
+			Provider: "deezer",
+			Attempts: []domain.PlaylistAttempt{{BeerStyle: "Pilsner", Step: "style_name", Query: "Pilsner", Outcome: domain.AttemptPlanned}},
+		},
+	}, nil
+}
+
+func (f *fakeBackend) Health(ctx context.Context) (*domain.HealthReport, error) {
+	return &domain.HealthReport{Status: f.health, Checks: []domain.HealthCheckResult{{Name: "database", Status: f.health, Critical: true}}}, nil
+}
+
+func (f *fakeBackend) EvaluateRankings(ctx context.Context, from, to time.Time, weights []float64, bucketSize float64) ([]domain.RankingEvaluation, error) {
+	f.weights = weights
+	evaluations := []domain.RankingEvaluation{{Strategy: "distance", Evaluated: 4, Hits: 1, Accuracy: 0.25}}
+	for _, weight := range weights {
+		evaluations = append(evaluations, domain.RankingEvaluation{Strategy: fmt.Sprintf("feedback (weight %g)", weight), Evaluated: 4, Hits: 3, Accuracy: 0.75})
+	}
+	return evaluations, nil
+}
+
+func (f *fakeBackend) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		health: domain.HealthStatusUp,
+		styles: []domain.BeerStyle{
+			{UUID: "uuid-1", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale"},
+			{UUID: "uuid-2", Name: "Pilsner", TempMin: 2, TempMax: 6, Category: "Lager"},
+		},
+	}
+}
+
+func emptyProfiles(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+type result struct {
+	code           int
+	stdout, stderr string
+	cfg            Config
+}
+
+// runCLI runs args against backend with the given environment and stdin.
+func runCLI(t *testing.T, backend *fakeBackend, env map[string]string, stdin string, args ...string) result {
+	t.Helper()
+	if env == nil {
+		env = map[string]string{}
+	}
+	if _, ok := env["BEERCTL_CONFIG"]; !ok {
+		env["BEERCTL_CONFIG"] = emptyProfiles(t)
+	}
+
+	var stdout, stderr bytes.Buffer
+	var res result
+	c := &cli{
+		in:     strings.NewReader(stdin),
+		out:    &stdout,
+		errOut: &stderr,
+		getenv: func(key string) string { return env[key] },
+		connect: func(cfg Config) (Backend, error) {
+			res.cfg = cfg
+			return backend, nil
+		},
+	}
+	res.code = c.run(context.Background(), args)
+	res.stdout, res.stderr = stdout.String(), stderr.String()
+	return res
+}
+
+func TestResolveConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	profiles := `
+current: staging
+profiles:
+  staging:
+    api_url: https://staging.example.com
+    api_key: staging-key
+    output: yaml
+  breakglass:
+    database_url: postgres://admin@db/beerdb
+`
+	if err := os.WriteFile(path, []byte(profiles), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"BEERCTL_CONFIG": path}
+	getenv := func(key string) string { return env[key] }
+
+	cfg, err := resolveConfig(globalFlags{}, getenv)
+	if err != nil || cfg.APIURL != "https://staging.example.com" || cfg.APIKey != "staging-key" || cfg.Output != OutputYAML {
+		t.Errorf("expected the current profile, got %+v %v", cfg, err)
+	}
+
+	env["BEERCTL_API_KEY"] = "env-key"
+	cfg, _ = resolveConfig(globalFlags{Config: Config{Output: OutputJSON}}, getenv)
+	if cfg.APIKey != "env-key" || cfg.Output != OutputJSON {
+		t.Errorf("expected the environment and flags to win, got %+v", cfg)
+	}
+
+	cfg, _ = resolveConfig(globalFlags{Config: Config{DatabaseURL: "postgres://local/beerdb"}}, getenv)
+	if cfg.DatabaseURL != "postgres://local/beerdb" || cfg.APIURL != "" {
+		t.Errorf("expected the database flag to replace the API of the profile, got %+v", cfg)
+	}
+
+	env["BEERCTL_PROFILE"] = "breakglass"
+	cfg, _ = resolveConfig(globalFlags{}, getenv)
+	if cfg.DatabaseURL != "postgres://admin@db/beerdb" || cfg.APIURL != "" {
+		t.Errorf("expected the profile of BEERCTL_PROFILE, got %+v", cfg)
+	}
+
+	if _, err := resolveConfig(globalFlags{profile: "prod"}, getenv); err == nil {
+		t.Error("expected an unknown profile to be rejected")
+	}
+	if _, err := resolveConfig(globalFlags{Config: Config{APIURL: "http://a", DatabaseURL: "postgres://b"}}, getenv); err == nil {
+		t.Error("expected an API and a database at once to be rejected")
+	}
+	if _, err := resolveConfig(globalFlags{Config: Config{Output: "xml"}}, getenv); err == nil {
+		t.Error("expected an unknown output to be rejected")
+	}
+}
+
+func TestResolveConfig_Defaults(t *testing.T) {
+	cfg, err := resolveConfig(globalFlags{config: emptyProfiles(t)}, func(string) string { return "" })
+	if err != nil || cfg.APIURL != DefaultAPIURL || cfg.DatabaseURL != "" || cfg.Output != OutputTable {
+		t.Errorf("expected the local API as a table, got %+v %v", cfg, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "config.yaml")
+	if _, err := resolveConfig(globalFlags{config: missing}, func(string) string { return "" }); err == nil {
+		t.Error("expected a missing --config file to be rejected")
+	}
+}
+
+func TestToCelsius(t *testing.T) {
+	for _, test := range []struct {
+		temperature float64
+		unit        string
+		want        float64
+	}{
+		{8.5, "C", 8.5},
+		{46.4, "f", 8},
+		{281.65, "K", 8.5},
+	} {
+		got, err := toCelsius(test.temperature, test.unit)
+		if err != nil || got < test.want-1e-9 || got > test.want+1e-9 {
+			t.Errorf("toCelsius(%v, %s) = %v %v, want %v", test.temperature, test.unit, got, err, test.want)
+		}
+	}
+	if _, err := toCelsius(1, "R"); err == nil {
+		t.Error("expected an unknown unit to be rejected")
+	}
+}
+
+func TestRun_Styles(t *testing.T) {
+	backend := newFakeBackend()
+
+	res := runCLI(t, backend, nil, "", "styles", "create", "--name", "Dubbel", "--temp-min", "8", "--temp-max", "12", "--abv", "7", "--aliases", "Abbey Dubbel, Belgian Dubbel")
+	if res.code != 0 || len(backend.styles) != 3 {
+		t.Fatalf("expected the style to be created, got %+v", res)
+	}
+	if created := backend.styles[2]; created.ABV == nil || *created.ABV != 7 || len(created.Aliases) != 2 {
+		t.Errorf("unexpected created style %+v", created)
+	}
+
+	res = runCLI(t, backend, nil, "", "styles", "update", "uuid-1", "--temp-max", "11")
+	if res.code != 0 || len(backend.updates) != 1 {
+		t.Fatalf("expected the style to be updated, got %+v", res)
+	}
+	if changes := backend.updates[0]; changes.TempMax == nil || *changes.TempMax != 11 || changes.Name != nil || changes.TempMin != nil {
+		t.Errorf("expected only temp_max to change, got %+v", changes)
+	}
+	if res := runCLI(t, backend, nil, "", "styles", "update", "uuid-1"); res.code != 2 {
+		t.Errorf("expected an update without fields to be a usage error, got %+v", res)
+	}
+
+	res = runCLI(t, backend, nil, "n\n", "styles", "delete", "uuid-2")
+	if res.code != 1 || len(backend.styles) != 3 || !strings.Contains(res.stderr, `Delete beer style "Pilsner"`) {
+		t.Errorf("expected the deletion to be cancelled, got %+v", res)
+	}
+	if res := runCLI(t, backend, nil, "yes\n", "styles", "delete", "uuid-2"); res.code != 0 || len(backend.styles) != 2 {
+		t.Errorf("expected the confirmed deletion, got %+v", res)
+	}
+	if res := runCLI(t, backend, nil, "", "styles", "delete", "--yes", "uuid-1"); res.code != 0 || len(backend.styles) != 1 {
+		t.Errorf("expected the deletion without a prompt, got %+v", res)
+	}
+	if !backend.closed {
+		t.Error("expected the backend to be closed")
+	}
+}
+
+func TestRun_OutputFormats(t *testing.T) {
+	backend := newFakeBackend()
+
+	res := runCLI(t, backend, nil, "", "styles", "list")
+	if res.code != 0 || !strings.HasPrefix(res.stdout, "UUID") || !strings.Contains(res.stdout, "Pilsner") {
+		t.Errorf("expected a table, got %q", res.stdout)
+	}
+
+	res = runCLI(t, backend, nil, "", "-o", "json", "styles", "get", "uuid-1")
+	var beerStyle domain.BeerStyle
+	if err := json.Unmarshal([]byte(res.stdout), &beerStyle); err != nil || beerStyle.Name != "IPA" {
+		t.Errorf("expected the style as JSON, got %q %v", res.stdout, err)
+	}
+
+	res = runCLI(t, backend, map[string]string{"BEERCTL_OUTPUT": "yaml"}, "", "styles", "list")
+	var listed []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(res.stdout), &listed); err != nil || len(listed) != 2 || listed[0]["temp_min"] != 7 {
+		t.Errorf("expected the styles as YAML with the API field names, got %q %v", res.stdout, err)
+	}
+}
+
+func TestRun_Import(t *testing.T) {
+	backend := newFakeBackend()
+	file := `
+- name: IPA
+  temp_min: 7
+  temp_max: 11
+  category: Ale
+- name: Pilsner
+  temp_min: 2
+  temp_max: 6
+  category: Lager
+- name: Stout
+  temp_min: 8
+  temp_max: 12
+  category: Ale
+`
+
+	res := runCLI(t, backend, nil, file, "-o", "json", "styles", "import", "--dry-run", "-")
+	var results []importResult
+	if err := json.Unmarshal([]byte(res.stdout), &results); err != nil || len(results) != 3 {
+		t.Fatalf("expected three results, got %q %v", res.stdout, err)
+	}
+	if results[0].Action != actionUpdate || results[0].Changed[0] != "TempMax" || results[1].Action != actionUnchanged || results[2].Action != actionCreate {
+		t.Errorf("unexpected plan %+v", results)
+	}
+	if len(backend.styles) != 2 || len(backend.updates) != 0 {
+		t.Errorf("expected a dry run to change nothing, got %+v", backend)
+	}
+
+	if res := runCLI(t, backend, nil, file, "styles", "import", "-"); res.code != 0 {
+		t.Fatalf("expected the import to succeed, got %+v", res)
+	}
+	if len(backend.styles) != 3 || len(backend.updates) != 1 || backend.styles[0].TempMax != 11 {
+		t.Errorf("expected Stout created and IPA updated, got %+v", backend)
+	}
+
+	exported := runCLI(t, backend, nil, "", "styles", "export")
+	if res := runCLI(t, backend, nil, exported.stdout, "-o", "json", "styles", "import", "-"); !strings.Contains(res.stdout, actionUnchanged) || strings.Contains(res.stdout, actionCreate) {
+		t.Errorf("expected an export to import unchanged, got %q", res.stdout)
+	}
+}
+
+func TestRun_RecommendAndExplain(t *testing.T) {
+	backend := newFakeBackend()
+
+	res := runCLI(t, backend, nil, "", "recommend", "--temp", "46.4", "--unit", "F", "--exclude", "Stout")
+	if res.code != 0 || !strings.Contains(res.stdout, "IPA Rock") {
+		t.Fatalf("expected the recommendation, got %+v", res)
+	}
+	if backend.request.Temperature < 7.99 || backend.request.Temperature > 8.01 || backend.request.ExcludeStyles[0] != "Stout" {
+		t.Errorf("expected 8°C without Stout, got %+v", backend.request)
+	}
+	if res := runCLI(t, backend, nil, "", "recommend"); res.code != 2 || !strings.Contains(res.stderr, "--temp is required") {
+		t.Errorf("expected --temp to be required, got %+v", res)
+	}
+
+	res = runCLI(t, backend, nil, "", "explain", "--temp", "5", "--exclude", "IPA", "--max-abv", "6")
+	if res.code != 0 || backend.resolve || backend.request.MaxABV == nil || *backend.request.MaxABV != 6 {
+		t.Fatalf("expected a planned explanation with max_abv, got %+v %+v", res, backend.request)
+	}
+	for _, want := range []string{"Pilsner", "listed in exclude_styles", "style_name", "planned"} {
+		if !strings.Contains(res.stdout, want) {
+			t.Errorf("expected %q in the table, got %q", want, res.stdout)
+		}
+	}
+
+	res = runCLI(t, backend, nil, "", "-o", "json", "explain", "--temp", "5", "--resolve-playlist")
+	var explanation domain.RecommendationExplanation
+	if err := json.Unmarshal([]byte(res.stdout), &explanation); err != nil || !backend.resolve || explanation.Candidates[0].Rank != 1 {
+		t.Errorf("expected the explanation as JSON, got %q %v", res.stdout, err)
+	}
+}
+
+func TestRun_Health(t *testing.T) {
+	backend := newFakeBackend()
+	if res := runCLI(t, backend, nil, "", "health"); res.code != 0 || !strings.Contains(res.stdout, "database") {
+		t.Errorf("expected a healthy report, got %+v", res)
+	}
+
+	backend.health = domain.HealthStatusDown
+	if res := runCLI(t, backend, nil, "", "health"); res.code != 1 {
+		t.Errorf("expected a down report to fail, got %+v", res)
+	}
+}
+
+func TestRun_Evaluate(t *testing.T) {
+	backend := newFakeBackend()
+
+	res := runCLI(t, backend, nil, "", "evaluate", "--from", "2024-03-01", "--to", "2024-03-08", "--weights", "1, 2.5")
+	if res.code != 0 || len(backend.weights) != 2 || backend.weights[1] != 2.5 {
+		t.Fatalf("expected two feedback weights, got %+v %v", res, backend.weights)
+	}
+	for _, want := range []string{"2024-03-01T00:00:00Z - 2024-03-08T00:00:00Z", "feedback (weight 2.5)", "0.750"} {
+		if !strings.Contains(res.stdout, want) {
+			t.Errorf("expected %q in the table, got %q", want, res.stdout)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"evaluate", "--from", "last week"},
+		{"evaluate", "--from", "2024-03-08", "--to", "2024-03-01"},
+		{"evaluate", "--weights", "1,heavy"},
+		{"evaluate", "--bucket-size", "0"},
+	} {
+		if res := runCLI(t, backend, nil, "", args...); res.code != 2 {
+			t.Errorf("expected %v to be a usage error, got %+v", args, res)
+		}
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	backend := newFakeBackend()
+	for _, args := range [][]string{
+		{},
+		{"brew"},
+		{"styles"},
+		{"styles", "get"},
+		{"--output", "xml", "health"},
+	} {
+		if res := runCLI(t, backend, nil, "", args...); res.code != 2 {
+			t.Errorf("expected %v to be a usage error, got %+v", args, res)
+		}
+	}
+
+	res := runCLI(t, backend, map[string]string{"BEERCTL_DATABASE_URL": "postgres://local/beerdb"}, "", "health")
+	if res.code != 0 || res.cfg.DatabaseURL != "postgres://local/beerdb" || res.cfg.APIURL != "" {
+		t.Errorf("expected the database backend, got %+v", res)
+	}
+}
@@ -0,0 +1,145 @@
+package beerctl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAPIURL is used when no flag, environment variable or profile
+// chooses an API or a database.
+const DefaultAPIURL = "http://localhost:1112"
+
+// Output formats.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// Config tells beerctl where to send the commands: the API at APIURL, or
+// straight to the database at DatabaseURL for break-glass operations.
+type Config struct {
+	APIURL      string `yaml:"api_url"`
+	APIKey      string `yaml:"api_key"`
+	Token       string `yaml:"token"`
+	DatabaseURL string `yaml:"database_url"`
+	Output      string `yaml:"output"`
+}
+
+// profileFile is the file of named configurations, by default
+// $XDG_CONFIG_HOME/beerctl/config.yaml:
+//
+//	current: prod
+//	profiles:
+//	  prod:
+//	    api_url: https://beer.example.com
+//	    api_key: ...
+type profileFile struct {
+	Current  string            `yaml:"current"`
+	Profiles map[string]Config `yaml:"profiles"`
+}
+
+// globalFlags are the flags given before the command.
+type globalFlags struct {
+	config  string
+	profile string
+	Config
+}
+
+// resolveConfig merges the flags, the environment and the profile, in this
+// order of precedence. The first of them to name an API or a database
+// chooses the backend, so a --database-url flag overrides a profile that
+// points to the API.
+func resolveConfig(flags globalFlags, getenv func(string) string) (Config, error) {
+	env := Config{
+		APIURL:      getenv("BEERCTL_API_URL"),
+		APIKey:      getenv("BEERCTL_API_KEY"),
+		Token:       getenv("BEERCTL_TOKEN"),
+		DatabaseURL: getenv("BEERCTL_DATABASE_URL"),
+		Output:      getenv("BEERCTL_OUTPUT"),
+	}
+	profile, err := loadProfile(flags.config, flags.profile, getenv)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	for _, source := range []Config{flags.Config, env, profile} {
+		if cfg.APIURL == "" && cfg.DatabaseURL == "" {
+			if source.APIURL != "" && source.DatabaseURL != "" {
+				return Config{}, errors.New("set either an API URL or a database URL, not both")
+			}
+			cfg.APIURL, cfg.DatabaseURL = source.APIURL, source.DatabaseURL
+		}
+		cfg.APIKey = firstNonEmpty(cfg.APIKey, source.APIKey)
+		cfg.Token = firstNonEmpty(cfg.Token, source.Token)
+		cfg.Output = firstNonEmpty(cfg.Output, source.Output)
+	}
+
+	if cfg.APIURL == "" && cfg.DatabaseURL == "" {
+		cfg.APIURL = DefaultAPIURL
+	}
+	if cfg.Output == "" {
+		cfg.Output = OutputTable
+	}
+	switch cfg.Output {
+	case OutputTable, OutputJSON, OutputYAML:
+	default:
+		return Config{}, fmt.Errorf("output must be table, json or yaml, got %q", cfg.Output)
+	}
+	return cfg, nil
+}
+
+// loadProfile reads the profile named by the flag, BEERCTL_PROFILE or the
+// current entry of the file. A missing default file is no profile at all;
+// a missing file or profile that was asked for is an error.
+func loadProfile(path, name string, getenv func(string) string) (Config, error) {
+	explicitPath := firstNonEmpty(path, getenv("BEERCTL_CONFIG"))
+	path = explicitPath
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return Config{}, nil
+		}
+		path = filepath.Join(dir, "beerctl", "config.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && explicitPath == "" {
+		if name = firstNonEmpty(name, getenv("BEERCTL_PROFILE")); name != "" {
+			return Config{}, fmt.Errorf("profile %q not found: %s does not exist", name, path)
+		}
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("read profiles: %w", err)
+	}
+
+	var file profileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Config{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	name = firstNonEmpty(name, getenv("BEERCTL_PROFILE"), file.Current)
+	if name == "" {
+		return Config{}, nil
+	}
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return Config{}, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	return profile, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
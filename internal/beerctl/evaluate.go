@@ -0,0 +1,95 @@
+package beerctl
+
+import (
+	"backend-test/internal/service"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// evaluate replays the feedback recorded between --from and --to against
+// the distance ranking and the feedback ranking with each of --weights, to
+// pick RECOMMENDATION_FEEDBACK_WEIGHT before enabling it.
+func (c *cli) evaluate(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("evaluate")
+	fromFlag := flags.String("from", "", "start of the period, a date (2006-01-02) or RFC 3339 time (default 30 days before --to)")
+	toFlag := flags.String("to", "", "end of the period, excluded (default now)")
+	weightsFlag := flags.String("weights", "0.5,1,2,4", "comma-separated feedback weights to compare, in degrees")
+	bucketSize := flags.Float64("bucket-size", service.DefaultTemperatureBucket, "width of the temperature buckets the feedback is aggregated by, in degrees")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	usageError := func(format string, args ...interface{}) error {
+		fmt.Fprintf(flags.Output(), "%s: %s\n", flags.Name(), fmt.Sprintf(format, args...))
+		return errUsage
+	}
+
+	to := time.Now()
+	if *toFlag != "" {
+		parsed, err := parseTime(*toFlag)
+		if err != nil {
+			return usageError("--to: %v", err)
+		}
+		to = parsed
+	}
+	from := to.Add(-service.DefaultAnalyticsWindow)
+	if *fromFlag != "" {
+		parsed, err := parseTime(*fromFlag)
+		if err != nil {
+			return usageError("--from: %v", err)
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		return usageError("--from must be before --to")
+	}
+
+	var weights []float64
+	for _, item := range splitList(*weightsFlag) {
+		weight, err := strconv.ParseFloat(item, 64)
+		if err != nil || weight <= 0 {
+			return usageError("--weights must be positive numbers, got %q", item)
+		}
+		weights = append(weights, weight)
+	}
+	if *bucketSize <= 0 {
+		return usageError("--bucket-size must be positive")
+	}
+
+	evaluations, err := c.backend.EvaluateRankings(ctx, from, to, weights, *bucketSize)
+	if err != nil {
+		return err
+	}
+
+	return render(c.out, c.output, evaluations, func() table {
+		rows := table{
+			{"PERIOD", from.UTC().Format(time.RFC3339) + " - " + to.UTC().Format(time.RFC3339)},
+			{""},
+			{"STRATEGY", "EVALUATED", "SKIPPED", "HITS", "ACCURACY", "MRR"},
+		}
+		for _, evaluation := range evaluations {
+			rows = append(rows, []string{
+				evaluation.Strategy,
+				strconv.Itoa(evaluation.Evaluated),
+				strconv.Itoa(evaluation.Skipped),
+				strconv.Itoa(evaluation.Hits),
+				strconv.FormatFloat(evaluation.Accuracy, 'f', 3, 64),
+				strconv.FormatFloat(evaluation.MeanReciprocalRank, 'f', 3, 64),
+			})
+		}
+		return rows
+	})
+}
+
+// parseTime reads a date, taken as midnight UTC, or an RFC 3339 time.
+func parseTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (2006-01-02) or an RFC 3339 time", value)
+}
@@ -0,0 +1,226 @@
+package beerctl
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// temperatureFlags are the temperature of recommend and explain, in any
+// unit; the API takes Celsius.
+type temperatureFlags struct {
+	temp float64
+	unit string
+}
+
+func (f *temperatureFlags) register(flags *flag.FlagSet) {
+	flags.Float64Var(&f.temp, "temp", 0, "temperature (required)")
+	flags.StringVar(&f.unit, "unit", "C", "unit of --temp: C, F or K")
+}
+
+// celsius checks that --temp was given and converts it.
+func (f *temperatureFlags) celsius(flags *flag.FlagSet) (float64, error) {
+	given := false
+	flags.Visit(func(fl *flag.Flag) { given = given || fl.Name == "temp" })
+	if !given {
+		fmt.Fprintf(flags.Output(), "%s: --temp is required\n", flags.Name())
+		return 0, errUsage
+	}
+	celsius, err := toCelsius(f.temp, f.unit)
+	if err != nil {
+		fmt.Fprintf(flags.Output(), "%s: %v\n", flags.Name(), err)
+		return 0, errUsage
+	}
+	return celsius, nil
+}
+
+func toCelsius(temperature float64, unit string) (float64, error) {
+	switch strings.ToUpper(unit) {
+	case "C":
+		return temperature, nil
+	case "F":
+		return (temperature - 32) * 5 / 9, nil
+	case "K":
+		return temperature - 273.15, nil
+	default:
+		return 0, fmt.Errorf("unit must be C, F or K, got %q", unit)
+	}
+}
+
+// preferenceFlags are the preferences shared by recommend and explain.
+type preferenceFlags struct {
+	include, exclude, categories, provider string
+	maxABV                                 float64
+}
+
+func (f *preferenceFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.include, "include", "", "comma-separated styles to choose from")
+	flags.StringVar(&f.exclude, "exclude", "", "comma-separated styles to leave out")
+	flags.StringVar(&f.categories, "categories", "", "comma-separated categories to choose from")
+	flags.Float64Var(&f.maxABV, "max-abv", 0, "maximum alcohol by volume, in %")
+	flags.StringVar(&f.provider, "provider", "", "music provider, instead of the default of the API")
+}
+
+func (f *preferenceFlags) request(flags *flag.FlagSet, celsius float64) domain.TemperatureRequest {
+	request := domain.TemperatureRequest{
+		Temperature:   celsius,
+		IncludeOnly:   splitList(f.include),
+		ExcludeStyles: splitList(f.exclude),
+		Categories:    splitList(f.categories),
+		Provider:      f.provider,
+	}
+	flags.Visit(func(fl *flag.Flag) {
+		if fl.Name == "max-abv" {
+			request.MaxABV = &f.maxABV
+		}
+	})
+	return request
+}
+
+func (c *cli) recommend(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("recommend")
+	var temperature temperatureFlags
+	var preferences preferenceFlags
+	temperature.register(flags)
+	preferences.register(flags)
+	trackLimit := flags.Int("track-limit", 0, "maximum number of tracks")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	celsius, err := temperature.celsius(flags)
+	if err != nil {
+		return err
+	}
+
+	request := preferences.request(flags, celsius)
+	request.TrackLimit = *trackLimit
+	recommendation, err := c.backend.Recommend(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return render(c.out, c.output, recommendation, func() table {
+		rows := table{
+			{"BEER STYLE", recommendation.BeerStyle},
+			{"PLAYLIST", recommendation.Playlist.Name},
+			{"PROVIDER", recommendation.Playlist.Provider},
+		}
+		if recommendation.Fallback != nil {
+			rows = append(rows, []string{"FALLBACK", recommendation.Fallback.Step})
+		}
+		rows = append(rows, []string{""}, []string{"#", "TRACK", "ARTIST", "LINK"})
+		for i, track := range recommendation.Playlist.Tracks {
+			rows = append(rows, []string{strconv.Itoa(i + 1), track.Name, track.Artist, track.Link})
+		}
+		return rows
+	})
+}
+
+// explain shows how the recommendation is reached: the ranking, the styles
+// left out and the playlist search, planned unless --resolve-playlist.
+func (c *cli) explain(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("explain")
+	var temperature temperatureFlags
+	var preferences preferenceFlags
+	temperature.register(flags)
+	preferences.register(flags)
+	resolvePlaylist := flags.Bool("resolve-playlist", false, "search the music provider instead of only planning the search")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	celsius, err := temperature.celsius(flags)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := c.backend.Explain(ctx, preferences.request(flags, celsius), *resolvePlaylist)
+	if err != nil {
+		return err
+	}
+
+	return render(c.out, c.output, explanation, func() table {
+		rows := table{{"BEER STYLE", explanation.BeerStyle}, {"RANKING", explanation.Ranking}}
+		if explanation.Error != "" {
+			rows = append(rows, []string{"ERROR", explanation.Error})
+		}
+
+		rows = append(rows, []string{""}, []string{"RANK", "NAME", "TEMP_MIN", "TEMP_MAX", "MIDPOINT", "DISTANCE", "ADJUSTED", "TIE_BREAK"})
+		for _, candidate := range explanation.Candidates {
+			adjusted := "-"
+			if candidate.AdjustedDistance != nil {
+				adjusted = strconv.FormatFloat(*candidate.AdjustedDistance, 'f', 2, 64)
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(candidate.Rank),
+				candidate.Name,
+				formatFloat(candidate.TempMin),
+				formatFloat(candidate.TempMax),
+				formatFloat(candidate.Midpoint),
+				strconv.FormatFloat(candidate.Distance, 'f', 2, 64),
+				adjusted,
+				candidate.TieBreak,
+			})
+		}
+
+		if len(explanation.Excluded) > 0 {
+			rows = append(rows, []string{""}, []string{"EXCLUDED", "CONSTRAINT", "REASON"})
+			for _, excluded := range explanation.Excluded {
+				rows = append(rows, []string{excluded.Name, excluded.Constraint, excluded.Reason})
+			}
+		}
+
+		if search := explanation.Playlist; search != nil {
+			rows = append(rows, []string{""}, []string{"PROVIDER", search.Provider})
+			if search.PlaylistID != "" {
+				rows = append(rows, []string{"PLAYLIST", search.PlaylistID})
+			}
+			if search.Error != "" {
+				rows = append(rows, []string{"ERROR", search.Error})
+			}
+			rows = append(rows, []string{""}, []string{"STYLE", "STEP", "QUERY/PLAYLIST", "OUTCOME", "RESULTS"})
+			for _, attempt := range search.Attempts {
+				target := attempt.Query
+				if target == "" {
+					target = attempt.PlaylistID
+				}
+				rows = append(rows, []string{attempt.BeerStyle, attempt.Step, target, attempt.Outcome, strings.Join(attempt.ResultIDs, ",")})
+			}
+		}
+		return rows
+	})
+}
+
+// health prints the readiness report and fails when the status is down,
+// so it can be used in scripts.
+func (c *cli) health(ctx context.Context, args []string) error {
+	if _, err := parse(c.newFlagSet("health"), args, 0); err != nil {
+		return err
+	}
+	report, err := c.backend.Health(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := render(c.out, c.output, report, func() table {
+		rows := table{{"STATUS", string(report.Status)}, {""}, {"CHECK", "STATUS", "CRITICAL", "LATENCY", "ERROR"}}
+		for _, check := range report.Checks {
+			rows = append(rows, []string{
+				check.Name,
+				string(check.Status),
+				strconv.FormatBool(check.Critical),
+				strconv.FormatInt(check.LatencyMs, 10) + "ms",
+				check.Error,
+			})
+		}
+		return rows
+	}); err != nil {
+		return err
+	}
+	if report.Status == domain.HealthStatusDown {
+		return fmt.Errorf("status is %s", report.Status)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package beerctl
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (c *cli) styles(ctx context.Context, args []string) error {
+	subcommands := map[string]command{
+		"list":   c.listStyles,
+		"get":    c.getStyle,
+		"create": c.createStyle,
+		"update": c.updateStyle,
+		"delete": c.deleteStyle,
+		"import": c.importStyles,
+		"export": c.exportStyles,
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(c.errOut, "beerctl styles: expected list, get, create, update, delete, import or export")
+		return errUsage
+	}
+	subcommand, ok := subcommands[args[0]]
+	if !ok {
+		fmt.Fprintf(c.errOut, "beerctl styles: unknown command %q\n", args[0])
+		return errUsage
+	}
+	return subcommand(ctx, args[1:])
+}
+
+func (c *cli) listStyles(ctx context.Context, args []string) error {
+	if _, err := parse(c.newFlagSet("styles list"), args, 0); err != nil {
+		return err
+	}
+	beerStyles, err := c.backend.ListBeerStyles(ctx)
+	if err != nil {
+		return err
+	}
+	return c.renderStyles(beerStyles)
+}
+
+func (c *cli) getStyle(ctx context.Context, args []string) error {
+	values, err := parse(c.newFlagSet("styles get"), args, 1)
+	if err != nil {
+		return err
+	}
+	beerStyle, err := c.backend.GetBeerStyle(ctx, values[0])
+	if err != nil {
+		return err
+	}
+	return c.renderStyle(beerStyle)
+}
+
+// styleFlags are the fields of a beer style given as flags.
+type styleFlags struct {
+	name, category, aliases string
+	tempMin, tempMax, abv   float64
+}
+
+func (f *styleFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.name, "name", "", "name of the style")
+	flags.Float64Var(&f.tempMin, "temp-min", 0, "minimum serving temperature, in °C")
+	flags.Float64Var(&f.tempMax, "temp-max", 0, "maximum serving temperature, in °C")
+	flags.StringVar(&f.category, "category", "", "category of the style")
+	flags.Float64Var(&f.abv, "abv", 0, "alcohol by volume, in %")
+	flags.StringVar(&f.aliases, "aliases", "", "comma-separated alternative names")
+}
+
+func (c *cli) createStyle(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("styles create")
+	var fields styleFlags
+	fields.register(flags)
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	beerStyle := domain.BeerStyle{
+		Name:     fields.name,
+		TempMin:  fields.tempMin,
+		TempMax:  fields.tempMax,
+		Category: fields.category,
+		Aliases:  splitList(fields.aliases),
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "abv" {
+			beerStyle.ABV = &fields.abv
+		}
+	})
+	if beerStyle.Name == "" {
+		fmt.Fprintln(c.errOut, "beerctl styles create: --name is required")
+		return errUsage
+	}
+
+	created, err := c.backend.CreateBeerStyle(ctx, beerStyle)
+	if err != nil {
+		return err
+	}
+	return c.renderStyle(created)
+}
+
+// updateStyle changes only the fields whose flags were given, so that
+// --category "" clears the category but an absent flag keeps it.
+func (c *cli) updateStyle(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("styles update")
+	var fields styleFlags
+	fields.register(flags)
+	values, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var changes domain.BeerStyleUpdateRequest
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			changes.Name = &fields.name
+		case "temp-min":
+			changes.TempMin = &fields.tempMin
+		case "temp-max":
+			changes.TempMax = &fields.tempMax
+		case "category":
+			changes.Category = &fields.category
+		case "abv":
+			changes.ABV = &fields.abv
+		case "aliases":
+			aliases := splitList(fields.aliases)
+			if aliases == nil {
+				aliases = []string{}
+			}
+			changes.Aliases = &aliases
+		}
+	})
+	if changes == (domain.BeerStyleUpdateRequest{}) {
+		fmt.Fprintln(c.errOut, "beerctl styles update: no field to change")
+		return errUsage
+	}
+
+	updated, err := c.backend.UpdateBeerStyle(ctx, values[0], changes)
+	if err != nil {
+		return err
+	}
+	return c.renderStyle(updated)
+}
+
+func (c *cli) deleteStyle(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("styles delete")
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	values, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	beerStyle, err := c.backend.GetBeerStyle(ctx, values[0])
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		fmt.Fprintf(c.errOut, "Delete beer style %q (%s)? [y/N] ", beerStyle.Name, beerStyle.UUID)
+		answer, _ := bufio.NewReader(c.in).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			return errors.New("deletion not confirmed")
+		}
+	}
+
+	if err := c.backend.DeleteBeerStyle(ctx, beerStyle.UUID); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.errOut, "Deleted beer style %q (%s)\n", beerStyle.Name, beerStyle.UUID)
+	return nil
+}
+
+// importResult is what importing one style did, or would do on a dry run.
+type importResult struct {
+	Name    string   `json:"name"`
+	Action  string   `json:"action"`
+	Changed []string `json:"changed,omitempty"`
+	UUID    string   `json:"uuid,omitempty"`
+}
+
+// Import actions.
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionUnchanged = "unchanged"
+)
+
+// importStyles creates the styles of the file whose name does not exist
+// and updates the others. Fields missing from the file, the ABV and the
+// aliases, are kept; UUIDs and timestamps are ignored, so the output of
+// export can be imported into another environment.
+func (c *cli) importStyles(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("styles import")
+	dryRun := flags.Bool("dry-run", false, "print what would change without changing it")
+	values, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.readFile(values[0])
+	if err != nil {
+		return err
+	}
+	imported, err := decodeStyles(data)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", values[0], err)
+	}
+
+	current, err := c.backend.ListBeerStyles(ctx)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]domain.BeerStyle, len(current))
+	for _, beerStyle := range current {
+		byName[beerStyle.Name] = beerStyle
+	}
+
+	update := service.NewUpdateService()
+	results := make([]importResult, 0, len(imported))
+	for i, beerStyle := range imported {
+		if beerStyle.Name == "" {
+			return fmt.Errorf("style %d of %s: name is required", i+1, values[0])
+		}
+		result := importResult{Name: beerStyle.Name, Action: actionCreate}
+
+		existing, exists := byName[beerStyle.Name]
+		changes := domain.BeerStyleUpdateRequest{
+			TempMin:  &beerStyle.TempMin,
+			TempMax:  &beerStyle.TempMax,
+			Category: &beerStyle.Category,
+			ABV:      beerStyle.ABV,
+		}
+		if beerStyle.Aliases != nil {
+			changes.Aliases = &beerStyle.Aliases
+		}
+		if exists {
+			result.UUID = existing.UUID
+			result.Action = actionUnchanged
+			if result.Changed = update.GetChangedFields(existing, changes); len(result.Changed) > 0 {
+				result.Action = actionUpdate
+			}
+		}
+
+		if !*dryRun {
+			switch result.Action {
+			case actionCreate:
+				created, err := c.backend.CreateBeerStyle(ctx, beerStyle)
+				if err != nil {
+					return fmt.Errorf("create %q: %w", beerStyle.Name, err)
+				}
+				result.UUID = created.UUID
+			case actionUpdate:
+				if _, err := c.backend.UpdateBeerStyle(ctx, existing.UUID, changes); err != nil {
+					return fmt.Errorf("update %q: %w", beerStyle.Name, err)
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	return render(c.out, c.output, results, func() table {
+		rows := table{{"NAME", "ACTION", "CHANGED", "UUID"}}
+		for _, result := range results {
+			rows = append(rows, []string{result.Name, result.Action, strings.Join(result.Changed, ","), result.UUID})
+		}
+		return rows
+	})
+}
+
+// exportStyles writes the styles as a list, in the format of --format,
+// of the extension of --file or else of --output, JSON for a table.
+func (c *cli) exportStyles(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("styles export")
+	path := flags.String("file", "", "file to write, instead of the standard output")
+	format := flags.String("format", "", "json or yaml")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	switch {
+	case *format != "":
+	case strings.HasSuffix(*path, ".yaml"), strings.HasSuffix(*path, ".yml"):
+		*format = OutputYAML
+	case strings.HasSuffix(*path, ".json"):
+		*format = OutputJSON
+	case c.output == OutputYAML:
+		*format = OutputYAML
+	default:
+		*format = OutputJSON
+	}
+	if *format != OutputJSON && *format != OutputYAML {
+		fmt.Fprintf(c.errOut, "beerctl styles export: format must be json or yaml, got %q\n", *format)
+		return errUsage
+	}
+
+	beerStyles, err := c.backend.ListBeerStyles(ctx)
+	if err != nil {
+		return err
+	}
+	if beerStyles == nil {
+		beerStyles = []domain.BeerStyle{}
+	}
+
+	if *path == "" {
+		return render(c.out, *format, beerStyles, nil)
+	}
+	file, err := os.Create(filepath.Clean(*path))
+	if err != nil {
+		return err
+	}
+	if err := render(file, *format, beerStyles, nil); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.errOut, "Exported %d beer style(s) to %s\n", len(beerStyles), *path)
+	return nil
+}
+
+// readFile reads path, or the standard input for "-".
+func (c *cli) readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(c.in)
+	}
+	return os.ReadFile(filepath.Clean(path))
+}
+
+// decodeStyles reads a list of styles, or the {"beerStyles": [...]} body of
+// the listing, from JSON or YAML. YAML is decoded first and converted to
+// JSON so the json tags of the domain apply to both.
+func decodeStyles(data []byte) ([]domain.BeerStyle, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if listing, ok := document.(map[string]interface{}); ok {
+		document = listing["beerStyles"]
+	}
+	if _, ok := document.([]interface{}); !ok {
+		return nil, errors.New("expected a list of beer styles")
+	}
+
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var beerStyles []domain.BeerStyle
+	if err := json.Unmarshal(converted, &beerStyles); err != nil {
+		return nil, err
+	}
+	return beerStyles, nil
+}
+
+func (c *cli) renderStyles(beerStyles []domain.BeerStyle) error {
+	if beerStyles == nil {
+		beerStyles = []domain.BeerStyle{}
+	}
+	return render(c.out, c.output, beerStyles, func() table {
+		rows := table{{"UUID", "NAME", "CATEGORY", "TEMP_MIN", "TEMP_MAX", "ABV", "ALIASES"}}
+		for _, beerStyle := range beerStyles {
+			rows = append(rows, []string{
+				beerStyle.UUID,
+				beerStyle.Name,
+				beerStyle.Category,
+				formatFloat(beerStyle.TempMin),
+				formatFloat(beerStyle.TempMax),
+				formatOptional(beerStyle.ABV),
+				strings.Join(beerStyle.Aliases, ","),
+			})
+		}
+		return rows
+	})
+}
+
+func (c *cli) renderStyle(beerStyle domain.BeerStyle) error {
+	return render(c.out, c.output, beerStyle, func() table {
+		return table{
+			{"UUID", beerStyle.UUID},
+			{"NAME", beerStyle.Name},
+			{"CATEGORY", beerStyle.Category},
+			{"TEMP_MIN", formatFloat(beerStyle.TempMin)},
+			{"TEMP_MAX", formatFloat(beerStyle.TempMax)},
+			{"ABV", formatOptional(beerStyle.ABV)},
+			{"ALIASES", strings.Join(beerStyle.Aliases, ",")},
+			{"CREATED_AT", beerStyle.CreatedAt.Format("2006-01-02 15:04:05")},
+			{"UPDATED_AT", beerStyle.UpdatedAt.Format("2006-01-02 15:04:05")},
+		}
+	})
+}
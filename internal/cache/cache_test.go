@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemory_ExpiresWithClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	memory := NewMemory(func() time.Time { return now })
+	ctx := context.Background()
+
+	if err := memory.Set(ctx, "token", "abc", time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	value, err := memory.Get(ctx, "token")
+	if err != nil || value != "abc" {
+		t.Fatalf("expected abc, got %q (%v)", value, err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := memory.Get(ctx, "token"); !errors.Is(err, ErrMiss) {
+		t.Errorf("expected ErrMiss after expiry, got %v", err)
+	}
+}
+
+func TestMemory_Miss(t *testing.T) {
+	memory := NewMemory(nil)
+
+	if _, err := memory.Get(context.Background(), "absent"); !errors.Is(err, ErrMiss) {
+		t.Errorf("expected ErrMiss, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"backend-test/external/music/musictest"
+	"backend-test/external/spotify"
+	"backend-test/internal/cache"
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTransport counts the Spotify token requests and records the
+// Authorization header of the API calls.
+type countingTransport struct {
+	base          http.RoundTripper
+	tokenRequests atomic.Int32
+	authorization atomic.Value
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/api/token" {
+		t.tokenRequests.Add(1)
+	} else {
+		t.authorization.Store(req.Header.Get("Authorization"))
+	}
+	return t.base.RoundTrip(req)
+}
+
+func setupSpotifyManager(t *testing.T, tokenCache cache.Cache, now func() time.Time) (*RedisSpotifyManager, *countingTransport) {
+	server := musictest.NewSpotifyServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	transport := &countingTransport{base: server.Client().Transport}
+	manager := NewSpotifyManager(context.Background(), SpotifyConfig{ClientID: "client-id", ClientSecret: "client-secret"}, tokenCache, now)
+	manager.options = []spotify.Option{
+		spotify.WithTokenURL(server.URL + "/api/token"),
+		spotify.WithAPIBaseURL(server.URL + "/v1/"),
+		spotify.WithHTTPClient(&http.Client{Transport: transport}),
+	}
+	return manager, transport
+}
+
+func search(t *testing.T, provider *spotify.Provider) {
+	t.Helper()
+	if _, err := provider.SearchPlaylists(context.Background(), "ipa", 3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestRedisSpotifyManager_ReusesToken(t *testing.T) {
+	manager, transport := setupSpotifyManager(t, nil, time.Now)
+	provider := spotify.NewProvider(manager.GetSpotifyService)
+
+	for i := 0; i < 3; i++ {
+		search(t, provider)
+	}
+
+	if got := transport.tokenRequests.Load(); got != 1 {
+		t.Errorf("Expected 1 token request for 3 searches, got %d", got)
+	}
+	if got := transport.authorization.Load(); got != "Bearer fake-token" {
+		t.Errorf("Expected the fetched token to be sent, got %v", got)
+	}
+}
+
+func TestRedisSpotifyManager_SharesCachedToken(t *testing.T) {
+	tokenCache := cache.NewMemory(time.Now)
+	first, firstTransport := setupSpotifyManager(t, tokenCache, time.Now)
+	search(t, spotify.NewProvider(first.GetSpotifyService))
+
+	second, secondTransport := setupSpotifyManager(t, tokenCache, time.Now)
+	search(t, spotify.NewProvider(second.GetSpotifyService))
+
+	if got := firstTransport.tokenRequests.Load() + secondTransport.tokenRequests.Load(); got != 1 {
+		t.Errorf("Expected the second instance to use the cached token (1 token request), got %d", got)
+	}
+	if got := secondTransport.authorization.Load(); got != "Bearer fake-token" {
+		t.Errorf("Expected the cached token to be sent, got %v", got)
+	}
+}
+
+func TestRedisSpotifyManager_RefreshesExpiringToken(t *testing.T) {
+	offset := time.Duration(0)
+	manager, transport := setupSpotifyManager(t, nil, func() time.Time { return time.Now().Add(offset) })
+	provider := spotify.NewProvider(manager.GetSpotifyService)
+
+	search(t, provider)
+	offset = time.Hour - tokenRefreshMargin/2
+	search(t, provider)
+
+	if got := transport.tokenRequests.Load(); got != 2 {
+		t.Errorf("Expected a new token once the first nears expiry (2 token requests), got %d", got)
+	}
+}
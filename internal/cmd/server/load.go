@@ -0,0 +1,307 @@
+package config
+
+import (
+	"backend-test/external/music"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from, in increasing precedence: the
+// defaults, the optional YAML or TOML file at path (CONFIG_FILE when path is
+// empty), the .env file of the working directory and the process
+// environment. Every invalid value is reported in a single joined error.
+func Load(path string) (*Config, error) {
+	dotenv, err := godotenv.Read()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	return load(path, func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := dotenv[key]
+		return value, ok
+	})
+}
+
+func load(path string, lookup func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path, _ = lookup("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	env := &envReader{lookup: lookup}
+	env.apply(cfg)
+
+	if err := errors.Join(append(env.errs, cfg.Validate())...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile reads a YAML or TOML file, chosen by extension, over cfg.
+// TOML documents are converted to YAML first so both formats accept the same
+// values, such as durations written as "15s". Unknown keys are rejected.
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".toml":
+		var document map[string]interface{}
+		if err := toml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(document); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s: use .yaml, .yml or .toml", path)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// envReader overlays environment variables on the configuration, collecting
+// the values that cannot be parsed.
+type envReader struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+func (e *envReader) apply(cfg *Config) {
+	e.int("PORT", &cfg.Server.Port)
+	e.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	e.duration("SHUTDOWN_READINESS_DELAY", &cfg.Server.ShutdownReadinessDelay)
+	e.list("TRUSTED_PROXIES", &cfg.Server.TrustedProxies)
+
+	e.bool("GRPC_ENABLED", &cfg.GRPC.Enabled)
+	e.int("GRPC_PORT", &cfg.GRPC.Port)
+	e.bool("GRPC_REFLECTION", &cfg.GRPC.Reflection)
+
+	e.bool("GRAPHQL_ENABLED", &cfg.GraphQL.Enabled)
+	e.int("GRAPHQL_MAX_DEPTH", &cfg.GraphQL.MaxDepth)
+	e.int("GRAPHQL_MAX_COMPLEXITY", &cfg.GraphQL.MaxComplexity)
+
+	e.string("DATABASE_URL", &cfg.Database.URL)
+	e.int("DB_MAX_CONNECTIONS", &cfg.Database.MaxConnections)
+
+	e.string("REDIS_URL", &cfg.Redis.Addr)
+	e.string("REDIS_PASSWORD", &cfg.Redis.Password)
+	e.int("REDIS_DB", &cfg.Redis.DB)
+	e.int("REDIS_POOL_SIZE", &cfg.Redis.PoolSize)
+	e.int("REDIS_MIN_IDLE_CONNS", &cfg.Redis.MinIdleConns)
+	e.int("REDIS_MAX_RETRIES", &cfg.Redis.MaxRetries)
+	e.duration("REDIS_DIAL_TIMEOUT", &cfg.Redis.DialTimeout)
+	e.duration("REDIS_READ_TIMEOUT", &cfg.Redis.ReadTimeout)
+	e.duration("REDIS_WRITE_TIMEOUT", &cfg.Redis.WriteTimeout)
+
+	e.string("SPOTIFY_CLIENT_ID", &cfg.Spotify.ClientID)
+	e.string("SPOTIFY_CLIENT_SECRET", &cfg.Spotify.ClientSecret)
+
+	e.string("DEFAULT_MUSIC_PROVIDER", &cfg.Music.DefaultProvider)
+	cfg.Music.DefaultProvider = strings.ToLower(cfg.Music.DefaultProvider)
+	e.bool("DEEZER_ENABLED", &cfg.Music.Deezer.Enabled)
+	e.string("DEEZER_API_URL", &cfg.Music.Deezer.APIURL)
+	e.string("YOUTUBE_API_KEY", &cfg.Music.YouTube.APIKey)
+	e.string("YOUTUBE_API_URL", &cfg.Music.YouTube.APIURL)
+	e.string("APPLE_MUSIC_DEVELOPER_TOKEN", &cfg.Music.AppleMusic.DeveloperToken)
+	e.string("APPLE_MUSIC_STOREFRONT", &cfg.Music.AppleMusic.Storefront)
+	e.string("APPLE_MUSIC_API_URL", &cfg.Music.AppleMusic.APIURL)
+
+	e.list("PLAYLIST_FALLBACK_CHAIN", &cfg.Recommendation.FallbackChain)
+	if cfg.Recommendation.DefaultPlaylistIDs == nil {
+		cfg.Recommendation.DefaultPlaylistIDs = map[string]string{}
+	}
+	// DEFAULT_PLAYLIST_ID applies to Spotify; other providers use
+	// DEFAULT_PLAYLIST_ID_<PROVIDER>, e.g. DEFAULT_PLAYLIST_ID_DEEZER.
+	e.mapEntry("DEFAULT_PLAYLIST_ID", cfg.Recommendation.DefaultPlaylistIDs, music.ProviderSpotify)
+	for _, provider := range music.SupportedProviders() {
+		e.mapEntry("DEFAULT_PLAYLIST_ID_"+strings.ToUpper(provider), cfg.Recommendation.DefaultPlaylistIDs, provider)
+	}
+	e.string("RECOMMENDATION_RANKING", &cfg.Recommendation.Ranking)
+	cfg.Recommendation.Ranking = strings.ToLower(strings.TrimSpace(cfg.Recommendation.Ranking))
+	e.float("RECOMMENDATION_FEEDBACK_WEIGHT", &cfg.Recommendation.FeedbackWeight)
+	e.float("RECOMMENDATION_FEEDBACK_BUCKET_SIZE", &cfg.Recommendation.FeedbackBucketSize)
+
+	e.bool("HISTORY_ENABLED", &cfg.History.Enabled)
+	e.int("HISTORY_BUFFER_SIZE", &cfg.History.BufferSize)
+	e.int("HISTORY_BATCH_SIZE", &cfg.History.BatchSize)
+	e.duration("HISTORY_FLUSH_INTERVAL", &cfg.History.FlushInterval)
+	e.duration("HISTORY_RETENTION", &cfg.History.Retention)
+	e.duration("HISTORY_PURGE_INTERVAL", &cfg.History.PurgeInterval)
+	e.duration("HISTORY_FEEDBACK_CACHE_TTL", &cfg.History.FeedbackCacheTTL)
+
+	e.duration("HEALTH_CHECK_TIMEOUT", &cfg.Health.Timeout)
+	if cfg.Health.Timeouts == nil {
+		cfg.Health.Timeouts = map[string]time.Duration{}
+	}
+	for _, check := range healthChecks {
+		timeout := cfg.Health.CheckTimeout(check)
+		if e.duration("HEALTH_CHECK_TIMEOUT_"+strings.ToUpper(check), &timeout) {
+			cfg.Health.Timeouts[check] = timeout
+		}
+	}
+	e.list("HEALTH_NON_CRITICAL", &cfg.Health.NonCritical)
+	e.duration("HEALTH_MUSIC_PROVIDER_CACHE_TTL", &cfg.Health.MusicProviderCacheTTL)
+
+	e.string("AUTH_JWKS_FILE", &cfg.Auth.JWKSFile)
+	e.string("AUTH_JWT_ISSUER", &cfg.Auth.Issuer)
+	e.string("AUTH_JWT_AUDIENCE", &cfg.Auth.Audience)
+	e.string("AUTH_JWT_ROLE_CLAIM", &cfg.Auth.RoleClaim)
+	e.string("AUTH_BOOTSTRAP_API_KEY", &cfg.Auth.BootstrapAPIKey)
+	e.bool("AUTH_PUBLIC_READS", &cfg.Auth.PublicReads)
+	e.bool("AUTH_PUBLIC_RECOMMENDATIONS", &cfg.Auth.PublicRecommendations)
+
+	e.bool("RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled)
+	e.string("RATE_LIMIT_STORE", &cfg.RateLimit.Store)
+	cfg.RateLimit.Store = strings.ToLower(cfg.RateLimit.Store)
+	e.int("RATE_LIMIT_READ_RPM", &cfg.RateLimit.Read.RequestsPerMinute)
+	e.int("RATE_LIMIT_READ_BURST", &cfg.RateLimit.Read.Burst)
+	e.int("RATE_LIMIT_WRITE_RPM", &cfg.RateLimit.Write.RequestsPerMinute)
+	e.int("RATE_LIMIT_WRITE_BURST", &cfg.RateLimit.Write.Burst)
+	e.int("RATE_LIMIT_RECOMMENDATION_RPM", &cfg.RateLimit.Recommendation.RequestsPerMinute)
+	e.int("RATE_LIMIT_RECOMMENDATION_BURST", &cfg.RateLimit.Recommendation.Burst)
+
+	e.string("CORS_PRESET", &cfg.CORS.Preset)
+	cfg.CORS.Preset = strings.ToLower(cfg.CORS.Preset)
+	e.list("CORS_ALLOW_ORIGINS", &cfg.CORS.AllowOrigins)
+	e.list("CORS_ALLOW_METHODS", &cfg.CORS.AllowMethods)
+	e.list("CORS_ALLOW_HEADERS", &cfg.CORS.AllowHeaders)
+	e.bool("CORS_ALLOW_CREDENTIALS", &cfg.CORS.AllowCredentials)
+	e.duration("CORS_MAX_AGE", &cfg.CORS.MaxAge)
+
+	e.duration("SECURITY_HSTS_MAX_AGE", &cfg.Security.HSTSMaxAge)
+	e.bool("SECURITY_HSTS_INCLUDE_SUBDOMAINS", &cfg.Security.HSTSIncludeSubdomains)
+	e.string("SECURITY_FRAME_OPTIONS", &cfg.Security.FrameOptions)
+	cfg.Security.FrameOptions = strings.ToUpper(cfg.Security.FrameOptions)
+	e.string("SECURITY_CONTENT_SECURITY_POLICY", &cfg.Security.ContentSecurityPolicy)
+	e.string("SECURITY_REFERRER_POLICY", &cfg.Security.ReferrerPolicy)
+
+	e.duration("HTTP_CACHE_BEER_STYLES_MAX_AGE", &cfg.HTTPCache.BeerStylesMaxAge)
+	e.duration("HTTP_CACHE_BEER_STYLE_MAX_AGE", &cfg.HTTPCache.BeerStyleMaxAge)
+
+	e.bool("COMPRESSION_ENABLED", &cfg.Compression.Enabled)
+	e.int("COMPRESSION_MIN_SIZE", &cfg.Compression.MinSize)
+
+	e.string("LOG_LEVEL", &cfg.Log.Level)
+	e.string("LOG_FORMAT", &cfg.Log.Format)
+
+	e.string("OTEL_TRACES_EXPORTER", &cfg.Tracing.Exporter)
+	e.string("OTEL_EXPORTER_OTLP_ENDPOINT", &cfg.Tracing.Endpoint)
+	e.string("OTEL_SERVICE_NAME", &cfg.Tracing.ServiceName)
+}
+
+// get returns the trimmed value of key. Empty variables count as unset so a
+// blank line in .env keeps the default.
+func (e *envReader) get(key string) (string, bool) {
+	value, ok := e.lookup(key)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if value, ok := e.get(key); ok {
+		*dst = value
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	value, ok := e.get(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+		return
+	}
+	*dst = parsed
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	value, ok := e.get(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not a number", key, value))
+		return
+	}
+	*dst = parsed
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	value, ok := e.get(key)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not a boolean", key, value))
+		return
+	}
+	*dst = parsed
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) bool {
+	value, ok := e.get(key)
+	if !ok {
+		return false
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not a duration (e.g. 15s)", key, value))
+		return false
+	}
+	*dst = parsed
+	return true
+}
+
+// list reads a comma separated list. Unlike the other values, a variable set
+// to an empty string clears the list.
+func (e *envReader) list(key string, dst *[]string) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (e *envReader) mapEntry(key string, dst map[string]string, entry string) {
+	if value, ok := e.get(key); ok {
+		dst[entry] = value
+	}
+}
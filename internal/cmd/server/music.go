@@ -0,0 +1,46 @@
+package config
+
+import (
+	"backend-test/external/applemusic"
+	"backend-test/external/deezer"
+	"backend-test/external/music"
+	"backend-test/external/spotify"
+	"backend-test/external/youtubemusic"
+	"backend-test/internal/metrics"
+	"backend-test/internal/tracing"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// instrumentProvider adds metrics and tracing spans to every provider call.
+func instrumentProvider(provider music.Provider) music.Provider {
+	return tracing.InstrumentProvider(metrics.InstrumentProvider(provider))
+}
+
+// NewMusicRegistry builds the provider registry. A provider is registered
+// only when its credentials are configured; Deezer needs none and can be
+// turned off with music.deezer.enabled. spotifyManager is nil when Spotify
+// is not configured.
+func NewMusicRegistry(cfg MusicConfig, spotifyManager *RedisSpotifyManager) *music.Registry {
+	registry := music.NewRegistry(cfg.DefaultProvider)
+
+	if spotifyManager != nil {
+		registry.Register(instrumentProvider(spotify.NewProvider(spotifyManager.GetSpotifyService)))
+	}
+
+	if cfg.Deezer.Enabled {
+		registry.Register(instrumentProvider(deezer.NewProvider(cfg.Deezer.APIURL, tracing.NewHTTPClient(10*time.Second))))
+	}
+
+	if cfg.YouTube.APIKey != "" {
+		registry.Register(instrumentProvider(youtubemusic.NewProvider(cfg.YouTube.APIKey, cfg.YouTube.APIURL, tracing.NewHTTPClient(10*time.Second))))
+	}
+
+	if cfg.AppleMusic.DeveloperToken != "" {
+		registry.Register(instrumentProvider(applemusic.NewProvider(cfg.AppleMusic.DeveloperToken, cfg.AppleMusic.Storefront, cfg.AppleMusic.APIURL, tracing.NewHTTPClient(10*time.Second))))
+	}
+
+	slog.Info("music providers enabled", "providers", strings.Join(registry.Names(), ","), "default", registry.DefaultProvider())
+	return registry
+}
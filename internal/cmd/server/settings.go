@@ -0,0 +1,498 @@
+package config
+
+import (
+	"backend-test/external/music"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Config is the whole application configuration. It is loaded once at
+// startup by Load and handed to the constructors that need it; no package
+// reads the environment on its own.
+type Config struct {
+	Server         ServerConfig         `yaml:"server"`
+	GRPC           GRPCConfig           `yaml:"grpc"`
+	GraphQL        GraphQLConfig        `yaml:"graphql"`
+	Database       DatabaseConfig       `yaml:"database"`
+	Redis          RedisConfig          `yaml:"redis"`
+	Spotify        SpotifyConfig        `yaml:"spotify"`
+	Music          MusicConfig          `yaml:"music"`
+	Recommendation RecommendationConfig `yaml:"recommendation"`
+	History        HistoryConfig        `yaml:"history"`
+	Health         HealthConfig         `yaml:"health"`
+	Auth           AuthConfig           `yaml:"auth"`
+	RateLimit      RateLimitConfig      `yaml:"rate_limit"`
+	CORS           CORSConfig           `yaml:"cors"`
+	Security       SecurityConfig       `yaml:"security"`
+	HTTPCache      HTTPCacheConfig      `yaml:"http_cache"`
+	Compression    CompressionConfig    `yaml:"compression"`
+	Log            LogConfig            `yaml:"log"`
+	Tracing        TracingConfig        `yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port"`
+	// ShutdownTimeout is how long in-flight requests have to complete once
+	// shutdown starts.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// ShutdownReadinessDelay is how long the instance reports not ready
+	// before it stops accepting connections.
+	ShutdownReadinessDelay time.Duration `yaml:"shutdown_readiness_delay"`
+	// TrustedProxies lists the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For header names the client. With none, the client is the
+	// peer address, so the header cannot be spoofed to dodge rate limits.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// GRPCConfig is the gRPC server running next to the HTTP one. It shares
+// the services, authentication and rate limits of the REST API.
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+	// Reflection lets tools such as grpcurl list the services without the
+	// proto files.
+	Reflection bool `yaml:"reflection"`
+}
+
+// GraphQLConfig is the /graphql endpoint. Queries deeper or costlier than
+// the limits are rejected before any resolver runs.
+type GraphQLConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	MaxDepth int  `yaml:"max_depth"`
+	// MaxComplexity bounds the estimated cost of a query: one per field,
+	// with list fields multiplied by the number of items they may return.
+	MaxComplexity int `yaml:"max_complexity"`
+}
+
+type DatabaseConfig struct {
+	URL            string `yaml:"url" secret:"password"`
+	MaxConnections int    `yaml:"max_connections"`
+}
+
+type RedisConfig struct {
+	Addr         string        `yaml:"addr"`
+	Password     string        `yaml:"password" secret:"true"`
+	DB           int           `yaml:"db"`
+	PoolSize     int           `yaml:"pool_size"`
+	MinIdleConns int           `yaml:"min_idle_conns"`
+	MaxRetries   int           `yaml:"max_retries"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+type SpotifyConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret" secret:"true"`
+}
+
+// Enabled reports whether Spotify credentials are configured.
+func (c SpotifyConfig) Enabled() bool {
+	return c.ClientID != "" && c.ClientSecret != ""
+}
+
+type MusicConfig struct {
+	// DefaultProvider is used when a request does not name one.
+	DefaultProvider string           `yaml:"default_provider"`
+	Deezer          DeezerConfig     `yaml:"deezer"`
+	YouTube         YouTubeConfig    `yaml:"youtube"`
+	AppleMusic      AppleMusicConfig `yaml:"apple_music"`
+}
+
+type DeezerConfig struct {
+	// Enabled defaults to true: Deezer needs no credentials.
+	Enabled bool   `yaml:"enabled"`
+	APIURL  string `yaml:"api_url"`
+}
+
+type YouTubeConfig struct {
+	APIKey string `yaml:"api_key" secret:"true"`
+	APIURL string `yaml:"api_url"`
+}
+
+type AppleMusicConfig struct {
+	DeveloperToken string `yaml:"developer_token" secret:"true"`
+	Storefront     string `yaml:"storefront"`
+	APIURL         string `yaml:"api_url"`
+}
+
+type RecommendationConfig struct {
+	// FallbackChain lists the steps tried when the beer style name search
+	// finds no playlist.
+	FallbackChain []string `yaml:"fallback_chain"`
+	// DefaultPlaylistIDs holds the curated last-resort playlist per provider.
+	DefaultPlaylistIDs map[string]string `yaml:"default_playlist_ids"`
+	// Ranking orders the candidate styles: "distance" by the distance
+	// between the temperature and the midpoint of each style range,
+	// "feedback" by that distance less FeedbackWeight degrees times the
+	// feedback score of the style, aggregated over the recommendations in
+	// the same temperature bucket of FeedbackBucketSize degrees.
+	Ranking            string  `yaml:"ranking"`
+	FeedbackWeight     float64 `yaml:"feedback_weight"`
+	FeedbackBucketSize float64 `yaml:"feedback_bucket_size"`
+}
+
+// HistoryConfig is the recommendation history behind the analytics. Served
+// recommendations are buffered in memory and written in batches, so a slow
+// database never delays a response; when the buffer is full, records are
+// dropped.
+type HistoryConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	BufferSize int  `yaml:"buffer_size"`
+	// BatchSize is the most records written in one insert. Batches are
+	// written when full and every FlushInterval.
+	BatchSize     int           `yaml:"batch_size"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	// Retention is how long records are kept; zero keeps them forever.
+	// Older records are purged every PurgeInterval.
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+	// FeedbackCacheTTL is how long the feedback scores of a temperature
+	// bucket are reused by the feedback ranking; zero queries them on every
+	// recommendation.
+	FeedbackCacheTTL time.Duration `yaml:"feedback_cache_ttl"`
+}
+
+type HealthConfig struct {
+	// Timeout applies to every readiness check without an entry in Timeouts.
+	Timeout  time.Duration            `yaml:"timeout"`
+	Timeouts map[string]time.Duration `yaml:"timeouts"`
+	// NonCritical dependencies only degrade the instance when down.
+	NonCritical []string `yaml:"non_critical"`
+	// MusicProviderCacheTTL is how long the outcome of the music provider
+	// check, a real search, is reused by later readiness probes.
+	MusicProviderCacheTTL time.Duration `yaml:"music_provider_cache_ttl"`
+}
+
+// CheckTimeout returns the timeout of the named readiness check.
+func (c HealthConfig) CheckTimeout(name string) time.Duration {
+	if timeout, ok := c.Timeouts[name]; ok {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// IsCritical reports whether the named dependency makes the instance not
+// ready when it is down.
+func (c HealthConfig) IsCritical(name string) bool {
+	for _, dependency := range c.NonCritical {
+		if strings.EqualFold(dependency, name) {
+			return false
+		}
+	}
+	return true
+}
+
+type AuthConfig struct {
+	// JWKSFile is a local JSON Web Key Set. Bearer JWTs are accepted only
+	// when it is set.
+	JWKSFile string `yaml:"jwks_file"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// RoleClaim names the JWT claim holding the role, as a string or a list.
+	RoleClaim string `yaml:"role_claim"`
+	// BootstrapAPIKey is an admin key accepted without being stored, used to
+	// create the first API keys.
+	BootstrapAPIKey string `yaml:"bootstrap_api_key" secret:"true"`
+	// PublicReads and PublicRecommendations keep those routes open to
+	// anonymous clients; otherwise they require the reader role.
+	PublicReads           bool `yaml:"public_reads"`
+	PublicRecommendations bool `yaml:"public_recommendations"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Store is "memory", per instance, or "redis", shared by every instance.
+	Store          string        `yaml:"store"`
+	Read           RateLimitRule `yaml:"read"`
+	Write          RateLimitRule `yaml:"write"`
+	Recommendation RateLimitRule `yaml:"recommendation"`
+}
+
+// RateLimitRule is a token bucket refilled at RequestsPerMinute that holds
+// up to Burst requests.
+type RateLimitRule struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	Burst             int `yaml:"burst"`
+}
+
+type CORSConfig struct {
+	// Preset fills every list left unset: "development" allows any origin,
+	// "production" only the origins listed in AllowOrigins.
+	Preset       string   `yaml:"preset"`
+	AllowOrigins []string `yaml:"allow_origins"`
+	AllowMethods []string `yaml:"allow_methods"`
+	AllowHeaders []string `yaml:"allow_headers"`
+	// AllowCredentials lets browsers send cookies, which a wildcard origin
+	// does not allow.
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// MaxAge is how long browsers cache a preflight response.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// corsPresets are the CORS settings of each environment.
+var corsPresets = map[string]CORSConfig{
+	"development": {
+		AllowOrigins: []string{"*"},
+		AllowMethods: []string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "If-None-Match", "If-Modified-Since"},
+		MaxAge:       12 * time.Hour,
+	},
+	"production": {
+		AllowOrigins: []string{},
+		AllowMethods: []string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "If-None-Match", "If-Modified-Since"},
+		MaxAge:       time.Hour,
+	},
+}
+
+// WithPreset returns the configuration with the lists and MaxAge left unset
+// taken from Preset. Lists explicitly set, even empty, are kept.
+func (c CORSConfig) WithPreset() CORSConfig {
+	preset, ok := corsPresets[strings.ToLower(c.Preset)]
+	if !ok {
+		return c
+	}
+	if c.AllowOrigins == nil {
+		c.AllowOrigins = preset.AllowOrigins
+	}
+	if c.AllowMethods == nil {
+		c.AllowMethods = preset.AllowMethods
+	}
+	if c.AllowHeaders == nil {
+		c.AllowHeaders = preset.AllowHeaders
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = preset.MaxAge
+	}
+	return c
+}
+
+// AllowsAnyOrigin reports whether every origin may call the API.
+func (c CORSConfig) AllowsAnyOrigin() bool {
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type SecurityConfig struct {
+	// HSTSMaxAge is sent in Strict-Transport-Security; zero omits the
+	// header.
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains"`
+	// FrameOptions is DENY or SAMEORIGIN; empty omits X-Frame-Options.
+	FrameOptions string `yaml:"frame_options"`
+	// ContentSecurityPolicy applies to every response. Pages that load
+	// assets override it on their own routes.
+	ContentSecurityPolicy string `yaml:"content_security_policy"`
+	ReferrerPolicy        string `yaml:"referrer_policy"`
+}
+
+// HTTPCacheConfig sets how long clients may reuse beer style responses
+// before revalidating them with their ETag. Zero requires revalidation on
+// every use.
+type HTTPCacheConfig struct {
+	BeerStylesMaxAge time.Duration `yaml:"beer_styles_max_age"`
+	BeerStyleMaxAge  time.Duration `yaml:"beer_style_max_age"`
+}
+
+type CompressionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MinSize is the body size in bytes from which responses are compressed.
+	MinSize int `yaml:"min_size"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+type TracingConfig struct {
+	// Exporter is "otlp" to export spans; empty or "none" disables tracing.
+	Exporter    string `yaml:"exporter"`
+	Endpoint    string `yaml:"endpoint"`
+	ServiceName string `yaml:"service_name"`
+}
+
+// healthChecks are the readiness checks whose timeout can be overridden.
+var healthChecks = []string{"postgres", "redis", "music_provider"}
+
+// Default returns the configuration used for every value no source sets.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            1111,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		GRPC: GRPCConfig{
+			Enabled:    true,
+			Port:       50051,
+			Reflection: true,
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:       true,
+			MaxDepth:      8,
+			MaxComplexity: 1000,
+		},
+		Database: DatabaseConfig{
+			MaxConnections: 10,
+		},
+		Redis: RedisConfig{
+			Addr:         "localhost:6379",
+			PoolSize:     5,
+			MinIdleConns: 1,
+			MaxRetries:   2,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+		},
+		Music: MusicConfig{
+			DefaultProvider: music.ProviderSpotify,
+			Deezer:          DeezerConfig{Enabled: true},
+		},
+		Recommendation: RecommendationConfig{
+			FallbackChain:      []string{"alias", "style_beer", "category", "next_style", "default_playlist"},
+			DefaultPlaylistIDs: map[string]string{},
+			Ranking:            "distance",
+			FeedbackWeight:     2,
+			FeedbackBucketSize: 5,
+		},
+		History: HistoryConfig{
+			Enabled:          true,
+			BufferSize:       1000,
+			BatchSize:        100,
+			FlushInterval:    2 * time.Second,
+			Retention:        90 * 24 * time.Hour,
+			PurgeInterval:    time.Hour,
+			FeedbackCacheTTL: time.Minute,
+		},
+		Health: HealthConfig{
+			Timeout:               2 * time.Second,
+			Timeouts:              map[string]time.Duration{},
+			NonCritical:           []string{"redis", "music_provider"},
+			MusicProviderCacheTTL: 30 * time.Second,
+		},
+		Auth: AuthConfig{
+			RoleClaim:             "role",
+			PublicReads:           true,
+			PublicRecommendations: true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			Store:          "memory",
+			Read:           RateLimitRule{RequestsPerMinute: 300, Burst: 60},
+			Write:          RateLimitRule{RequestsPerMinute: 60, Burst: 20},
+			Recommendation: RateLimitRule{RequestsPerMinute: 30, Burst: 10},
+		},
+		CORS: CORSConfig{
+			Preset: "development",
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge:            180 * 24 * time.Hour,
+			FrameOptions:          "DENY",
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+			ReferrerPolicy:        "no-referrer",
+		},
+		HTTPCache: HTTPCacheConfig{
+			BeerStylesMaxAge: time.Minute,
+			BeerStyleMaxAge:  5 * time.Minute,
+		},
+		Compression: CompressionConfig{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			ServiceName: "beer-recommendation-api",
+		},
+	}
+}
+
+const redacted = "[REDACTED]"
+
+// Secrets returns the configured credentials, so the logger can mask them
+// wherever they show up.
+func (c *Config) Secrets() []string {
+	var secrets []string
+	walkSecrets(reflect.ValueOf(c).Elem(), func(value, kind string) {
+		if kind == "password" {
+			value = urlPassword(value)
+		}
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	})
+	return secrets
+}
+
+// LogValue prints the configuration with every secret masked, so it can be
+// logged at boot.
+func (c *Config) LogValue() slog.Value {
+	return logValue(reflect.ValueOf(c).Elem())
+}
+
+func logValue(v reflect.Value) slog.Value {
+	attrs := make([]slog.Attr, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		value := v.Field(i)
+
+		switch {
+		case field.Tag.Get("secret") == "password":
+			attrs = append(attrs, slog.String(key, redactURL(value.String())))
+		case field.Tag.Get("secret") != "":
+			if value.String() != "" {
+				attrs = append(attrs, slog.String(key, redacted))
+			} else {
+				attrs = append(attrs, slog.String(key, ""))
+			}
+		case value.Kind() == reflect.Struct:
+			attrs = append(attrs, slog.Attr{Key: key, Value: logValue(value)})
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			attrs = append(attrs, slog.String(key, value.Interface().(time.Duration).String()))
+		default:
+			attrs = append(attrs, slog.Any(key, value.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func walkSecrets(v reflect.Value, visit func(value, kind string)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if kind := field.Tag.Get("secret"); kind != "" {
+			visit(v.Field(i).String(), kind)
+			continue
+		}
+		if v.Field(i).Kind() == reflect.Struct {
+			walkSecrets(v.Field(i), visit)
+		}
+	}
+}
+
+func urlPassword(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return ""
+	}
+	password, _ := parsed.User.Password()
+	return password
+}
+
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	return parsed.Redacted()
+}
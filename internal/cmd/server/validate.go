@@ -0,0 +1,148 @@
+package config
+
+import (
+	"backend-test/external/music"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Validate checks the whole configuration and reports every problem found,
+// joined in a single error.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	nonNegative := func(name string, value time.Duration) {
+		check(value >= 0, "%s must not be negative, got %s", name, value)
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535, got %d", c.Server.Port)
+	nonNegative("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	nonNegative("server.shutdown_readiness_delay", c.Server.ShutdownReadinessDelay)
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies: %q is not an IP or CIDR", proxy)
+	}
+
+	if c.GRPC.Enabled {
+		check(c.GRPC.Port > 0 && c.GRPC.Port <= 65535, "grpc.port must be between 1 and 65535, got %d", c.GRPC.Port)
+		check(c.GRPC.Port != c.Server.Port, "grpc.port must differ from server.port, both are %d", c.Server.Port)
+	}
+	if c.GraphQL.Enabled {
+		check(c.GraphQL.MaxDepth > 0, "graphql.max_depth must be positive, got %d", c.GraphQL.MaxDepth)
+		check(c.GraphQL.MaxComplexity > 0, "graphql.max_complexity must be positive, got %d", c.GraphQL.MaxComplexity)
+	}
+
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("database.url (DATABASE_URL) is required"))
+	} else if parsed, err := url.Parse(c.Database.URL); err != nil || (parsed.Scheme != "postgres" && parsed.Scheme != "postgresql") {
+		errs = append(errs, errors.New("database.url must be a postgres:// URL"))
+	}
+	check(c.Database.MaxConnections > 0, "database.max_connections must be positive, got %d", c.Database.MaxConnections)
+
+	check(c.Redis.Addr != "", "redis.addr (REDIS_URL) is required")
+	check(c.Redis.PoolSize > 0, "redis.pool_size must be positive, got %d", c.Redis.PoolSize)
+	check(c.Redis.MinIdleConns >= 0, "redis.min_idle_conns must not be negative, got %d", c.Redis.MinIdleConns)
+	nonNegative("redis.dial_timeout", c.Redis.DialTimeout)
+	nonNegative("redis.read_timeout", c.Redis.ReadTimeout)
+	nonNegative("redis.write_timeout", c.Redis.WriteTimeout)
+
+	check((c.Spotify.ClientID == "") == (c.Spotify.ClientSecret == ""), "spotify.client_id and spotify.client_secret must be set together")
+
+	check(music.IsSupported(c.Music.DefaultProvider), "music.default_provider must be one of %s, got %q", strings.Join(music.SupportedProviders(), ", "), c.Music.DefaultProvider)
+	for provider := range c.Recommendation.DefaultPlaylistIDs {
+		check(music.IsSupported(provider), "recommendation.default_playlist_ids: unknown provider %q", provider)
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Recommendation.Ranking)) {
+	case "distance", "feedback":
+	default:
+		errs = append(errs, fmt.Errorf("recommendation.ranking must be distance or feedback, got %q", c.Recommendation.Ranking))
+	}
+	check(c.Recommendation.FeedbackWeight >= 0, "recommendation.feedback_weight must not be negative, got %v", c.Recommendation.FeedbackWeight)
+	check(c.Recommendation.FeedbackBucketSize > 0, "recommendation.feedback_bucket_size must be positive, got %v", c.Recommendation.FeedbackBucketSize)
+
+	if c.History.Enabled {
+		check(c.History.BufferSize > 0, "history.buffer_size must be positive, got %d", c.History.BufferSize)
+		check(c.History.BatchSize > 0 && c.History.BatchSize <= 1000, "history.batch_size must be between 1 and 1000, got %d", c.History.BatchSize)
+		check(c.History.FlushInterval > 0, "history.flush_interval must be positive, got %s", c.History.FlushInterval)
+		nonNegative("history.retention", c.History.Retention)
+		check(c.History.Retention == 0 || c.History.PurgeInterval > 0, "history.purge_interval must be positive when history.retention is set, got %s", c.History.PurgeInterval)
+		nonNegative("history.feedback_cache_ttl", c.History.FeedbackCacheTTL)
+	}
+
+	check(c.Health.Timeout > 0, "health.timeout must be positive, got %s", c.Health.Timeout)
+	for name, timeout := range c.Health.Timeouts {
+		check(timeout > 0, "health.timeouts.%s must be positive, got %s", name, timeout)
+	}
+	nonNegative("health.music_provider_cache_ttl", c.Health.MusicProviderCacheTTL)
+
+	check(c.Auth.RoleClaim != "", "auth.role_claim must not be empty")
+	check(c.Auth.BootstrapAPIKey == "" || len(c.Auth.BootstrapAPIKey) >= 32, "auth.bootstrap_api_key must have at least 32 characters")
+
+	if c.RateLimit.Enabled {
+		check(c.RateLimit.Store == "memory" || c.RateLimit.Store == "redis", "rate_limit.store must be memory or redis, got %q", c.RateLimit.Store)
+		rules := []struct {
+			name string
+			rule RateLimitRule
+		}{{"read", c.RateLimit.Read}, {"write", c.RateLimit.Write}, {"recommendation", c.RateLimit.Recommendation}}
+		for _, r := range rules {
+			check(r.rule.RequestsPerMinute > 0, "rate_limit.%s.requests_per_minute must be positive, got %d", r.name, r.rule.RequestsPerMinute)
+			check(r.rule.Burst > 0, "rate_limit.%s.burst must be positive, got %d", r.name, r.rule.Burst)
+		}
+	}
+
+	_, knownPreset := corsPresets[strings.ToLower(c.CORS.Preset)]
+	check(knownPreset, "cors.preset must be development or production, got %q", c.CORS.Preset)
+	cors := c.CORS.WithPreset()
+	for _, origin := range cors.AllowOrigins {
+		check(origin == "*" || isOrigin(origin), "cors.allow_origins: %q must be * or scheme://host[:port]", origin)
+	}
+	check(!cors.AllowCredentials || !cors.AllowsAnyOrigin(), "cors.allow_credentials requires explicit cors.allow_origins, not *")
+	nonNegative("cors.max_age", cors.MaxAge)
+
+	nonNegative("security.hsts_max_age", c.Security.HSTSMaxAge)
+	switch c.Security.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		errs = append(errs, fmt.Errorf("security.frame_options must be DENY or SAMEORIGIN, got %q", c.Security.FrameOptions))
+	}
+
+	nonNegative("http_cache.beer_styles_max_age", c.HTTPCache.BeerStylesMaxAge)
+	nonNegative("http_cache.beer_style_max_age", c.HTTPCache.BeerStyleMaxAge)
+
+	check(c.Compression.MinSize >= 0, "compression.min_size must not be negative, got %d", c.Compression.MinSize)
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("log.format must be json or text, got %q", c.Log.Format))
+	}
+
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "", "none", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be otlp or none, got %q", c.Tracing.Exporter))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isOrigin reports whether origin is a bare http(s) origin, as browsers send
+// it in the Origin header.
+func isOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" &&
+		parsed.Path == "" && parsed.RawQuery == "" && parsed.User == nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Role grants access to a group of routes. Each role includes the
+// permissions of the roles below it: reader < editor < admin.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Allows reports whether r has at least the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[required]
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+	// Method is "api_key" or "jwt".
+	Method string `json:"method"`
+}
+
+// APIKey is a stored API key. Only the SHA-256 hash of the key is kept; the
+// prefix identifies the key in listings and logs.
+type APIKey struct {
+	UUID      string     `json:"uuid" ksql:"uuid"`
+	Name      string     `json:"name" ksql:"name"`
+	Prefix    string     `json:"prefix" ksql:"prefix"`
+	KeyHash   string     `json:"-" ksql:"key_hash"`
+	Role      string     `json:"role" ksql:"role"`
+	CreatedAt time.Time  `json:"created_at" ksql:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" ksql:"revoked_at"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+// CreatedAPIKey is returned once, when the key is created; the plain key
+// cannot be recovered afterwards.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
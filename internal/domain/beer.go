@@ -7,12 +7,18 @@ type BeerStyle struct {
 	Name      string    `json:"name" binding:"required" ksql:"name"`
 	TempMin   float64   `json:"temp_min" ksql:"temp_min"`
 	TempMax   float64   `json:"temp_max" ksql:"temp_max"`
+	Category  string    `json:"category" ksql:"category"`
+	ABV       *float64  `json:"abv,omitempty" ksql:"abv"`
+	Aliases   []string  `json:"aliases" ksql:"aliases"`
 	CreatedAt time.Time `json:"created_at" ksql:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" ksql:"updated_at"`
 }
 
 type BeerStyleUpdateRequest struct {
-	Name    *string  `json:"name,omitempty"`
-	TempMin *float64 `json:"temp_min,omitempty"`
-	TempMax *float64 `json:"temp_max,omitempty"`
+	Name     *string   `json:"name,omitempty"`
+	TempMin  *float64  `json:"temp_min,omitempty"`
+	TempMax  *float64  `json:"temp_max,omitempty"`
+	Category *string   `json:"category,omitempty"`
+	ABV      *float64  `json:"abv,omitempty"`
+	Aliases  *[]string `json:"aliases,omitempty"`
 }
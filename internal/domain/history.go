@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+// RecommendationRecord is a served recommendation kept in the history. ID is
+// returned with the recommendation so later requests can refer to it.
+type RecommendationRecord struct {
+	ID            string  `json:"id" ksql:"id"`
+	Temperature   float64 `json:"temperature" ksql:"temperature"`
+	BeerStyleUUID string  `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	BeerStyle     string  `json:"beer_style" ksql:"beer_style"`
+	PlaylistID    string  `json:"playlist_id" ksql:"playlist_id"`
+	Provider      string  `json:"provider" ksql:"provider"`
+	FallbackStep  string  `json:"fallback_step" ksql:"fallback_step"`
+	LatencyMs     int64   `json:"latency_ms" ksql:"latency_ms"`
+	// ClientID is the subject of the authenticated caller; empty for
+	// anonymous requests.
+	ClientID  string    `json:"client_id,omitempty" ksql:"client_id"`
+	CreatedAt time.Time `json:"created_at" ksql:"created_at"`
+}
+
+// AnalyticsQuery selects the history aggregated by
+// GET /api/analytics/recommendations: records created in [From, To), with
+// temperatures grouped in buckets of BucketSize degrees.
+type AnalyticsQuery struct {
+	From       time.Time
+	To         time.Time
+	BucketSize float64
+}
+
+// RecommendationAnalytics counts the recommendations of an AnalyticsQuery.
+// Styles are ordered by count, buckets by temperature and days by date.
+type RecommendationAnalytics struct {
+	From          time.Time                `json:"from"`
+	To            time.Time                `json:"to"`
+	BucketSize    float64                  `json:"bucket_size"`
+	Total         int64                    `json:"total"`
+	ByBeerStyle   []BeerStyleCount         `json:"by_beer_style"`
+	ByTemperature []TemperatureBucketCount `json:"by_temperature"`
+	ByDay         []DayCount               `json:"by_day"`
+}
+
+type BeerStyleCount struct {
+	BeerStyle    string  `json:"beer_style" ksql:"beer_style"`
+	Count        int64   `json:"count" ksql:"count"`
+	AvgLatencyMs float64 `json:"avg_latency_ms" ksql:"avg_latency_ms"`
+}
+
+// TemperatureBucketCount counts the requests with a temperature in
+// [Min, Max).
+type TemperatureBucketCount struct {
+	Min   float64 `json:"min" ksql:"bucket_min"`
+	Max   float64 `json:"max"`
+	Count int64   `json:"count" ksql:"count"`
+}
+
+// DayCount counts the recommendations of a UTC day, formatted YYYY-MM-DD.
+type DayCount struct {
+	Day   string `json:"day" ksql:"day"`
+	Count int64  `json:"count" ksql:"count"`
+}
+
+// Votes of a RecommendationFeedback.
+const (
+	VoteUp   = "up"
+	VoteDown = "down"
+)
+
+// FeedbackRequest rates a recommendation. PreferredStyle optionally names
+// the beer style the drinker would rather have had.
+type FeedbackRequest struct {
+	Vote           string `json:"vote"`
+	PreferredStyle string `json:"preferred_style,omitempty"`
+}
+
+// RecommendationFeedback is the rating of a recorded recommendation. A new
+// vote replaces the previous one. The preferred style is identified by
+// PreferredStyleUUID; PreferredStyle keeps its name for display.
+type RecommendationFeedback struct {
+	RecommendationID   string    `json:"recommendation_id" ksql:"recommendation_id"`
+	Vote               string    `json:"vote" ksql:"vote"`
+	PreferredStyleUUID string    `json:"preferred_style_uuid,omitempty" ksql:"preferred_style_uuid"`
+	PreferredStyle     string    `json:"preferred_style,omitempty" ksql:"preferred_style"`
+	CreatedAt          time.Time `json:"created_at" ksql:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" ksql:"updated_at"`
+}
+
+// FeedbackTally counts the votes on a beer style, by UUID, in a temperature
+// bucket: up and down votes on recommendations of the style, and
+// Preferred, the votes that named it as the preferred style.
+type FeedbackTally struct {
+	BeerStyleUUID string `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	Up            int64  `json:"up" ksql:"up"`
+	Down          int64  `json:"down" ksql:"down"`
+	Preferred     int64  `json:"preferred" ksql:"preferred"`
+}
+
+// RatedRecommendation is a recorded recommendation and its feedback, as
+// replayed by the ranking evaluation. Styles are matched by UUID; the names
+// are for display.
+type RatedRecommendation struct {
+	ID                 string    `json:"id" ksql:"id"`
+	Temperature        float64   `json:"temperature" ksql:"temperature"`
+	BeerStyleUUID      string    `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	BeerStyle          string    `json:"beer_style" ksql:"beer_style"`
+	Vote               string    `json:"vote" ksql:"vote"`
+	PreferredStyleUUID string    `json:"preferred_style_uuid,omitempty" ksql:"preferred_style_uuid"`
+	PreferredStyle     string    `json:"preferred_style,omitempty" ksql:"preferred_style"`
+	CreatedAt          time.Time `json:"created_at" ksql:"created_at"`
+}
+
+// RankingEvaluation is how a ranking strategy fares against the feedback of
+// the recorded recommendations. A rating is a hit when the style ranked
+// first is the one the drinker wanted (the recommended style of an up vote,
+// the preferred style otherwise) or, for a down vote without a preferred
+// style, any other than the one rejected. MeanReciprocalRank averages
+// 1/rank of the wanted style over the ratings that name one.
+type RankingEvaluation struct {
+	Strategy           string  `json:"strategy"`
+	Evaluated          int     `json:"evaluated"`
+	Skipped            int     `json:"skipped"`
+	Hits               int     `json:"hits"`
+	Accuracy           float64 `json:"accuracy"`
+	MeanReciprocalRank float64 `json:"mean_reciprocal_rank"`
+}
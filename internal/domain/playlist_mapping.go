@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// BeerStylePlaylist pins a provider playlist to a beer style. Pinned
+// playlists are used in preference to searching by the style name.
+type BeerStylePlaylist struct {
+	UUID          string    `json:"uuid" ksql:"uuid"`
+	BeerStyleUUID string    `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	Provider      string    `json:"provider" ksql:"provider"`
+	PlaylistID    string    `json:"playlist_id" ksql:"playlist_id"`
+	Weight        int       `json:"weight" ksql:"weight"`
+	CreatedAt     time.Time `json:"created_at" ksql:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" ksql:"updated_at"`
+}
+
+type BeerStylePlaylistRequest struct {
+	Provider   string `json:"provider"`
+	PlaylistID string `json:"playlist_id"`
+	Weight     int    `json:"weight"`
+}
@@ -1,22 +1,154 @@
 package domain
 
-
 type TemperatureRequest struct {
-	Temperature float64 `json:"temperature"`
+	Temperature   float64  `json:"temperature"`
+	ExcludeStyles []string `json:"exclude_styles,omitempty"`
+	IncludeOnly   []string `json:"include_only,omitempty"`
+	MaxABV        *float64 `json:"max_abv,omitempty"`
+	Categories    []string `json:"categories,omitempty"`
+	MinTracks     int      `json:"min_tracks,omitempty"`
+
+	TrackLimit      int    `json:"track_limit,omitempty"`
+	Shuffle         bool   `json:"shuffle,omitempty"`
+	ShuffleSeed     *int64 `json:"shuffle_seed,omitempty"`
+	DistinctArtists bool   `json:"distinct_artists,omitempty"`
+
+	Provider string `json:"provider,omitempty"`
 }
 
 type TrackInfo struct {
 	Name   string `json:"name"`
 	Artist string `json:"artist"`
 	Link   string `json:"link"`
+
+	Artists     []string `json:"artists,omitempty"`
+	Album       string   `json:"album,omitempty"`
+	DurationMs  int      `json:"duration_ms,omitempty"`
+	Explicit    bool     `json:"explicit,omitempty"`
+	Popularity  int      `json:"popularity,omitempty"`
+	PreviewURL  string   `json:"preview_url,omitempty"`
+	ISRC        string   `json:"isrc,omitempty"`
+	AlbumArtURL string   `json:"album_art_url,omitempty"`
 }
 
 type PlaylistInfo struct {
-	Name   string      `json:"name"`
-	Tracks []TrackInfo `json:"tracks"`
+	ID          string      `json:"id,omitempty"`
+	Provider    string      `json:"provider,omitempty"`
+	Name        string      `json:"name"`
+	Tracks      []TrackInfo `json:"tracks"`
+	ShuffleSeed *int64      `json:"shuffle_seed,omitempty"`
+}
+
+// FallbackInfo tells which step of the playlist fallback chain produced the
+// playlist and the search query used, if any.
+type FallbackInfo struct {
+	Step  string `json:"step"`
+	Query string `json:"query,omitempty"`
+}
+
+// RankedBeerStyle is a candidate of a recommendation: the closer the
+// temperature to the midpoint of its range, the better its rank, from 1.
+// Under the feedback ranking the candidates are ordered by
+// AdjustedDistance, the distance less the weighted FeedbackScore; otherwise
+// it equals Distance.
+type RankedBeerStyle struct {
+	Style            BeerStyle `json:"style"`
+	Rank             int       `json:"rank"`
+	Midpoint         float64   `json:"midpoint"`
+	Distance         float64   `json:"distance"`
+	FeedbackScore    float64   `json:"feedback_score"`
+	AdjustedDistance float64   `json:"adjusted_distance"`
 }
 
 type RecommendationResponse struct {
+	// ID identifies the recommendation in the history; empty when it was
+	// not recorded.
+	ID        string       `json:"id,omitempty"`
 	BeerStyle string       `json:"beerStyle"`
 	Playlist  PlaylistInfo `json:"playlist"`
+	// Fallback is nil when the playlist was pinned to the style or found
+	// by its name.
+	Fallback *FallbackInfo `json:"fallback,omitempty"`
+}
+
+// RecommendationExplanation tells how a recommendation is reached: the
+// ranking of the candidate styles, the ones the preferences left out and
+// the playlist search.
+type RecommendationExplanation struct {
+	Temperature float64 `json:"temperature"`
+	// Ranking is the strategy that ordered the candidates: distance or
+	// feedback.
+	Ranking    string                 `json:"ranking"`
+	BeerStyle  string                 `json:"beer_style,omitempty"`
+	Candidates []CandidateExplanation `json:"candidates"`
+	Excluded   []ExcludedBeerStyle    `json:"excluded"`
+	// Constraint is the preference that left no candidate, if any.
+	Constraint string                     `json:"constraint,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+	Playlist   *PlaylistSearchExplanation `json:"playlist,omitempty"`
+}
+
+// CandidateExplanation is a ranked style. TieBreak is set when its distance
+// equals that of another candidate, which the name order then decides.
+// FeedbackScore and AdjustedDistance are only set under the feedback
+// ranking, where the adjusted distance is compared instead.
+type CandidateExplanation struct {
+	UUID             string   `json:"uuid"`
+	Name             string   `json:"name"`
+	TempMin          float64  `json:"temp_min"`
+	TempMax          float64  `json:"temp_max"`
+	Midpoint         float64  `json:"midpoint"`
+	Distance         float64  `json:"distance"`
+	FeedbackScore    *float64 `json:"feedback_score,omitempty"`
+	AdjustedDistance *float64 `json:"adjusted_distance,omitempty"`
+	Rank             int      `json:"rank"`
+	TieBreak         string   `json:"tie_break,omitempty"`
+}
+
+// ExcludedBeerStyle is a style the preference Constraint left out.
+type ExcludedBeerStyle struct {
+	UUID       string `json:"uuid"`
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	Reason     string `json:"reason"`
+}
+
+// PlaylistSearchExplanation lists the steps of the playlist fallback chain.
+// Unless Resolved, the music provider was not called and the attempts are
+// the plan of the search, which stops at the first playlist accepted.
+type PlaylistSearchExplanation struct {
+	Provider   string            `json:"provider"`
+	Resolved   bool              `json:"resolved"`
+	Attempts   []PlaylistAttempt `json:"attempts"`
+	PlaylistID string            `json:"playlist_id,omitempty"`
+	Fallback   *FallbackInfo     `json:"fallback,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Outcomes of a PlaylistAttempt.
+const (
+	AttemptPlanned   = "planned"
+	AttemptSelected  = "selected"
+	AttemptRejected  = "rejected"
+	AttemptNoResults = "no_results"
+	AttemptFailed    = "failed"
+)
+
+// PlaylistAttempt is a search query, or a pinned or default playlist
+// fetched by ID, for the style of a fallback step.
+type PlaylistAttempt struct {
+	BeerStyle  string              `json:"beer_style"`
+	Step       string              `json:"step"`
+	Query      string              `json:"query,omitempty"`
+	PlaylistID string              `json:"playlist_id,omitempty"`
+	ResultIDs  []string            `json:"result_ids,omitempty"`
+	Rejected   []PlaylistRejection `json:"rejected,omitempty"`
+	Outcome    string              `json:"outcome"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// PlaylistRejection is a playlist found but not used.
+type PlaylistRejection struct {
+	PlaylistID string `json:"playlist_id"`
+	Reason     string `json:"reason"`
 }
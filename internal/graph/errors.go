@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"backend-test/internal/access"
+	"backend-test/internal/ratelimit"
+	"backend-test/internal/service"
+	"errors"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Error codes returned in the extensions of every error.
+const (
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeRateLimited     = "RATE_LIMITED"
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeUnavailable     = "UNAVAILABLE"
+	CodeInternal        = "INTERNAL"
+	CodeQueryTooDeep    = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+	CodeInvalidQuery    = "GRAPHQL_VALIDATION_FAILED"
+)
+
+// Error is a resolver failure with the code clients branch on. Details go
+// next to the code in the extensions.
+type Error struct {
+	Code    string
+	Message string
+	Details map[string]interface{}
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.Code}
+	for key, value := range e.Details {
+		extensions[key] = value
+	}
+	return extensions
+}
+
+// withCodes fills the extensions the executor dropped. It keeps them for
+// errors returned by resolvers but not for those returned by thunks, so
+// the original error is looked up again for every error.
+func withCodes(formatted []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, err := range formatted {
+		if err.Extensions != nil {
+			continue
+		}
+		var graphErr *Error
+		if errors.As(unwrap(err), &graphErr) {
+			formatted[i].Extensions = graphErr.Extensions()
+		} else {
+			formatted[i].Extensions = map[string]interface{}{"code": CodeInternal}
+		}
+	}
+	return formatted
+}
+
+// unwrap follows the original errors kept by the executor, which do not
+// implement Unwrap.
+func unwrap(err error) error {
+	for {
+		switch wrapper := err.(type) {
+		case gqlerrors.FormattedError:
+			if wrapper.OriginalError() == nil {
+				return err
+			}
+			err = wrapper.OriginalError()
+		case *gqlerrors.Error:
+			if wrapper.OriginalError == nil {
+				return err
+			}
+			err = wrapper.OriginalError
+		default:
+			return err
+		}
+	}
+}
+
+// accessError maps the rejections of access.Checker.
+func accessError(err error) *Error {
+	var limitErr *access.LimitError
+	var roleErr *access.RoleError
+	switch {
+	case errors.As(err, &limitErr):
+		return &Error{Code: CodeRateLimited, Message: err.Error(), Details: map[string]interface{}{
+			"retryAfter": ratelimit.CeilSeconds(limitErr.Result.RetryAfter),
+		}}
+	case errors.Is(err, access.ErrAuthenticationRequired):
+		return newError(CodeUnauthenticated, err.Error())
+	case errors.As(err, &roleErr):
+		return newError(CodeForbidden, err.Error())
+	default:
+		return newError(CodeInternal, "internal error")
+	}
+}
+
+// nameError maps a failed unique name check.
+func nameError(err error) *Error {
+	if strings.Contains(err.Error(), "already exists") {
+		return newError(CodeConflict, err.Error())
+	}
+	return newError(CodeInternal, "failed to validate beer style name")
+}
+
+// recommendationError maps the recommendation failures as the REST
+// controller does. The constraint that could not be met is returned in the
+// extensions.
+func recommendationError(err error) *Error {
+	errorMsg := err.Error()
+
+	var graphErr *Error
+	switch {
+	case strings.Contains(errorMsg, "no beer style satisfies constraint"),
+		strings.Contains(errorMsg, "no playlist found"):
+		graphErr = newError(CodeNotFound, errorMsg)
+	case strings.Contains(errorMsg, "spotify service unavailable"):
+		graphErr = newError(CodeUnavailable, "Spotify service is temporarily unavailable")
+	case strings.Contains(errorMsg, "service unavailable"):
+		graphErr = newError(CodeUnavailable, "Music provider is temporarily unavailable")
+	case strings.Contains(errorMsg, "failed to find best beer style"):
+		graphErr = newError(CodeInternal, "Unable to determine suitable beer style")
+	default:
+		graphErr = newError(CodeInternal, "Internal server error")
+	}
+
+	var constraintErr *service.ConstraintError
+	if errors.As(err, &constraintErr) {
+		graphErr.Details = map[string]interface{}{"constraint": constraintErr.Constraint}
+	}
+	return graphErr
+}
@@ -0,0 +1,474 @@
+package graph
+
+import (
+	"backend-test/internal/access"
+	"backend-test/internal/auth"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/ratelimit"
+	"backend-test/internal/service"
+	"backend-test/internal/storage/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type memoryBeerRepository struct {
+	styles []domain.BeerStyle
+}
+
+func (m *memoryBeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return m.styles, nil
+}
+
+func (m *memoryBeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	for _, style := range m.styles {
+		if style.UUID == beerUUID {
+			return style, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	beerStyle.UUID = fmt.Sprintf("style-%d", len(m.styles)+1)
+	beerStyle.CreatedAt = testNow
+	beerStyle.UpdatedAt = testNow
+	m.styles = append(m.styles, beerStyle)
+	return beerStyle, nil
+}
+
+func (m *memoryBeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	for i := range m.styles {
+		if m.styles[i].UUID == beerStyle.UUID {
+			m.styles[i] = beerStyle
+			return beerStyle, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	for i := range m.styles {
+		if m.styles[i].UUID == beerUUID {
+			m.styles = append(m.styles[:i], m.styles[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+var _ repository.BeerRepositoryInterface = (*memoryBeerRepository)(nil)
+
+// mockPlaylistMappingService records the batches it is asked for.
+type mockPlaylistMappingService struct {
+	service.PlaylistMappingServiceInterface
+	playlists map[string][]domain.BeerStylePlaylist
+	batches   [][]string
+	err       error
+}
+
+func (m *mockPlaylistMappingService) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) (map[string][]domain.BeerStylePlaylist, error) {
+	m.batches = append(m.batches, beerStyleUUIDs)
+	if m.err != nil {
+		return nil, m.err
+	}
+	byStyle := make(map[string][]domain.BeerStylePlaylist)
+	for _, beerStyleUUID := range beerStyleUUIDs {
+		if playlists, ok := m.playlists[beerStyleUUID]; ok {
+			byStyle[beerStyleUUID] = playlists
+		}
+	}
+	return byStyle, nil
+}
+
+type mockRecommendationService struct {
+	request  domain.TemperatureRequest
+	response *domain.RecommendationResponse
+	err      error
+}
+
+func (m *mockRecommendationService) GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
+	m.request = request
+	return m.response, m.err
+}
+
+func (m *mockRecommendationService) ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	return nil, m.err
+}
+
+// apiKeys authenticates the keys named after their role.
+type apiKeys struct{}
+
+func (apiKeys) AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error) {
+	role := domain.Role(key)
+	if !role.Valid() {
+		return domain.Principal{}, service.ErrInvalidAPIKey
+	}
+	return domain.Principal{Subject: key, Role: role, Method: "api_key"}, nil
+}
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+type testServer struct {
+	router          *gin.Engine
+	playlists       *mockPlaylistMappingService
+	recommendations *mockRecommendationService
+}
+
+func setupServer(t *testing.T, configure func(*Access, *config.GraphQLConfig)) testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := logging.Discard()
+
+	beerService := service.NewBeerService(&memoryBeerRepository{styles: []domain.BeerStyle{
+		{UUID: "style-ipa", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale", ABV: float(6.5), Aliases: []string{"India Pale Ale"}, CreatedAt: testNow.Add(-time.Hour), UpdatedAt: testNow},
+		{UUID: "style-pilsner", Name: "Pilsner", TempMin: 4, TempMax: 6, Category: "Lager", ABV: float(4.8), CreatedAt: testNow, UpdatedAt: testNow},
+		{UUID: "style-stout", Name: "Stout", TempMin: 8, TempMax: 12, Category: "Ale", CreatedAt: testNow.Add(-2 * time.Hour), UpdatedAt: testNow},
+	}})
+	playlists := &mockPlaylistMappingService{playlists: map[string][]domain.BeerStylePlaylist{
+		"style-ipa":   {{UUID: "pin-1", BeerStyleUUID: "style-ipa", Provider: "deezer", PlaylistID: "101", Weight: 2, CreatedAt: testNow, UpdatedAt: testNow}},
+		"style-stout": {{UUID: "pin-2", BeerStyleUUID: "style-stout", Provider: "spotify", PlaylistID: "stout", Weight: 1, CreatedAt: testNow, UpdatedAt: testNow}},
+	}}
+	recommendations := &mockRecommendationService{}
+
+	guard := Access{Rules: access.Rules{
+		Read:      access.Rule{Policy: "read"},
+		Write:     access.Rule{Role: domain.RoleEditor, Policy: "write"},
+		Recommend: access.Rule{Policy: "recommendation"},
+	}}
+	cfg := config.GraphQLConfig{Enabled: true, MaxDepth: 8, MaxComplexity: 1000}
+	if configure != nil {
+		configure(&guard, &cfg)
+	}
+
+	server, err := NewServer(Services{
+		Beer:            beerService,
+		Validation:      service.NewValidationService(beerService),
+		Update:          service.NewUpdateService(),
+		Recommendation:  recommendations,
+		PlaylistMapping: playlists,
+	}, guard, cfg, logger)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	router := gin.New()
+	router.POST("/graphql", auth.NewAuthenticator(apiKeys{}, nil, logger).Middleware(), server.Handle)
+	return testServer{router: router, playlists: playlists, recommendations: recommendations}
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// code is the code of the only error of the response, or "" without errors.
+func (r response) code(t *testing.T) string {
+	t.Helper()
+	switch len(r.Errors) {
+	case 0:
+		return ""
+	case 1:
+		code, _ := r.Errors[0].Extensions["code"].(string)
+		return code
+	}
+	t.Fatalf("expected at most one error, got %+v", r.Errors)
+	return ""
+}
+
+func (s testServer) query(t *testing.T, query string, variables map[string]interface{}, key string) (int, response) {
+	t.Helper()
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var parsed response
+	if err := json.Unmarshal(w.Body.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid response %s: %v", w.Body.String(), err)
+	}
+	return w.Code, parsed
+}
+
+func TestBeerStyles_FiltersSortsAndPages(t *testing.T) {
+	server := setupServer(t, nil)
+
+	tests := []struct {
+		name      string
+		arguments string
+		expected  string
+	}{
+		{"defaults to name order", ``, `{"items":[{"name":"IPA"},{"name":"Pilsner"},{"name":"Stout"}],"total":3}`},
+		{"filters by alias ignoring case", `filter: {name: "pale"}`, `{"items":[{"name":"IPA"}],"total":1}`},
+		{"filters by category and temperature", `filter: {category: "ale", temperature: 9.5}`, `{"items":[{"name":"IPA"},{"name":"Stout"}],"total":2}`},
+		{"filters by ABV", `filter: {minAbv: 5}`, `{"items":[{"name":"IPA"}],"total":1}`},
+		{"keeps styles without ABV last", `sort: {field: ABV, direction: DESC}`, `{"items":[{"name":"IPA"},{"name":"Pilsner"},{"name":"Stout"}],"total":3}`},
+		{"sorts by creation", `sort: {field: CREATED_AT}`, `{"items":[{"name":"Stout"},{"name":"IPA"},{"name":"Pilsner"}],"total":3}`},
+		{"pages", `sort: {field: TEMP_MAX, direction: DESC}, page: {offset: 1, limit: 1}`, `{"items":[{"name":"IPA"}],"total":3}`},
+		{"pages past the end", `page: {offset: 5}`, `{"items":[],"total":3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "{ beerStyles { items { name } total } }"
+			if tt.arguments != "" {
+				query = "{ beerStyles(" + tt.arguments + ") { items { name } total } }"
+			}
+			code, result := server.query(t, query, nil, "")
+			if code != http.StatusOK || result.code(t) != "" || string(result.Data["beerStyles"]) != tt.expected {
+				t.Errorf("expected %s, got %d %+v %s", tt.expected, code, result.Errors, result.Data["beerStyles"])
+			}
+		})
+	}
+
+	_, result := server.query(t, "{ beerStyles(page: {limit: 101}) { total } }", nil, "")
+	if result.code(t) != CodeBadUserInput {
+		t.Errorf("expected an oversized page to be rejected, got %+v", result.Errors)
+	}
+}
+
+func TestBeerStyle(t *testing.T) {
+	server := setupServer(t, nil)
+
+	_, result := server.query(t, `query($uuid: ID!) { beerStyle(uuid: $uuid) { name abv aliases createdAt } }`, map[string]interface{}{"uuid": "style-ipa"}, "")
+	expected := `{"abv":6.5,"aliases":["India Pale Ale"],"createdAt":"2024-01-01T11:00:00Z","name":"IPA"}`
+	if result.code(t) != "" || string(result.Data["beerStyle"]) != expected {
+		t.Errorf("expected %s, got %+v %s", expected, result.Errors, result.Data["beerStyle"])
+	}
+
+	_, result = server.query(t, `{ beerStyle(uuid: "missing") { name } }`, nil, "")
+	if result.code(t) != "" || string(result.Data["beerStyle"]) != "null" {
+		t.Errorf("expected null for a missing style, got %+v %s", result.Errors, result.Data["beerStyle"])
+	}
+}
+
+func TestPlaylists_AreLoadedInOneBatch(t *testing.T) {
+	server := setupServer(t, nil)
+
+	_, result := server.query(t, `{
+		beerStyles { items { uuid playlists { playlistId } } }
+		ipa: beerStyle(uuid: "style-ipa") { playlists { provider } }
+	}`, nil, "")
+	if result.code(t) != "" {
+		t.Fatalf("expected no errors, got %+v", result.Errors)
+	}
+	expected := `{"items":[{"playlists":[{"playlistId":"101"}],"uuid":"style-ipa"},{"playlists":[],"uuid":"style-pilsner"},{"playlists":[{"playlistId":"stout"}],"uuid":"style-stout"}]}`
+	if string(result.Data["beerStyles"]) != expected {
+		t.Errorf("expected %s, got %s", expected, result.Data["beerStyles"])
+	}
+	if string(result.Data["ipa"]) != `{"playlists":[{"provider":"deezer"}]}` {
+		t.Errorf("unexpected aliased style %s", result.Data["ipa"])
+	}
+
+	if len(server.playlists.batches) != 1 || len(server.playlists.batches[0]) != 3 {
+		t.Errorf("expected the three styles in a single batch, got %v", server.playlists.batches)
+	}
+}
+
+func TestPlaylists_FailureKeepsTheRestOfTheResponse(t *testing.T) {
+	server := setupServer(t, nil)
+	server.playlists.err = errors.New("connection refused")
+
+	_, result := server.query(t, `{ beerStyle(uuid: "style-ipa") { name playlists { uuid } } }`, nil, "")
+	if result.code(t) != CodeInternal || result.Errors[0].Message != "failed to load pinned playlists" {
+		t.Errorf("expected an internal error, got %+v", result.Errors)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	server := setupServer(t, func(_ *Access, cfg *config.GraphQLConfig) {
+		cfg.MaxDepth = 3
+		cfg.MaxComplexity = 300
+	})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		code      string
+	}{
+		{"within the limits", `{ beerStyles { items { name } } }`, nil, ""},
+		{"too deep", `{ beerStyles { items { playlists { uuid } } } }`, nil, CodeQueryTooDeep},
+		{"too deep through fragments", `{ beerStyles { ...page } } fragment page on BeerStylePage { items { ...pins } } fragment pins on BeerStyle { playlists { uuid } }`, nil, CodeQueryTooDeep},
+		{"too complex for the page size", `{ beerStyles(page: {limit: 100}) { items { name category tempMin } } }`, nil, CodeQueryTooComplex},
+		{"too complex through a variable", `query($page: PageInput) { beerStyles(page: $page) { items { name category tempMin } } }`, map[string]interface{}{"page": map[string]interface{}{"limit": 100}}, CodeQueryTooComplex},
+		{"introspection is free", `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, result := server.query(t, tt.query, tt.variables, "")
+			if result.code(t) != tt.code {
+				t.Fatalf("expected %q, got %d %+v", tt.code, code, result.Errors)
+			}
+			if tt.code != "" && (code != http.StatusBadRequest || result.Data != nil) {
+				t.Errorf("expected 400 without data, got %d %v", code, result.Data)
+			}
+		})
+	}
+	if len(server.playlists.batches) != 0 {
+		t.Errorf("expected rejected queries not to run, got %v", server.playlists.batches)
+	}
+}
+
+func TestHandle_RejectsInvalidRequests(t *testing.T) {
+	server := setupServer(t, nil)
+
+	for _, query := range []string{`{ beerStyles {`, `{ beerStyles { unknown } }`} {
+		code, result := server.query(t, query, nil, "")
+		if code != http.StatusBadRequest || result.code(t) != CodeInvalidQuery {
+			t.Errorf("expected %q to be invalid, got %d %+v", query, code, result.Errors)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`not json`))
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), CodeBadUserInput) {
+		t.Errorf("expected 400 for a body that is not JSON, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestMutations(t *testing.T) {
+	server := setupServer(t, nil)
+	create := `mutation($input: BeerStyleInput!) { createBeerStyle(input: $input) { uuid name abv aliases } }`
+	dubbel := map[string]interface{}{"input": map[string]interface{}{"name": "Dubbel", "tempMin": 8, "tempMax": 12, "abv": 7, "aliases": []string{"Abbey Dubbel"}}}
+
+	_, result := server.query(t, create, dubbel, "")
+	if result.code(t) != CodeUnauthenticated {
+		t.Errorf("expected anonymous writes to be rejected, got %+v", result.Errors)
+	}
+	_, result = server.query(t, create, dubbel, "reader")
+	if result.code(t) != CodeForbidden {
+		t.Errorf("expected readers to be rejected, got %+v", result.Errors)
+	}
+
+	_, result = server.query(t, create, dubbel, "editor")
+	if result.code(t) != "" || string(result.Data["createBeerStyle"]) != `{"abv":7,"aliases":["Abbey Dubbel"],"name":"Dubbel","uuid":"style-4"}` {
+		t.Fatalf("expected the style to be created, got %+v %s", result.Errors, result.Data["createBeerStyle"])
+	}
+	_, result = server.query(t, create, dubbel, "editor")
+	if result.code(t) != CodeConflict {
+		t.Errorf("expected a duplicate name to conflict, got %+v", result.Errors)
+	}
+	_, result = server.query(t, create, map[string]interface{}{"input": map[string]interface{}{"name": "Tripel", "tempMin": 12, "tempMax": 8}}, "editor")
+	if result.code(t) != CodeBadUserInput {
+		t.Errorf("expected an inverted range to be rejected, got %+v", result.Errors)
+	}
+
+	update := `mutation($uuid: ID!, $input: BeerStyleChanges!) { updateBeerStyle(uuid: $uuid, input: $input) { name tempMax } }`
+	_, result = server.query(t, update, map[string]interface{}{"uuid": "style-4", "input": map[string]interface{}{"tempMax": 14}}, "editor")
+	if result.code(t) != "" || string(result.Data["updateBeerStyle"]) != `{"name":"Dubbel","tempMax":14}` {
+		t.Errorf("expected the style to be updated, got %+v %s", result.Errors, result.Data["updateBeerStyle"])
+	}
+	_, result = server.query(t, update, map[string]interface{}{"uuid": "style-4", "input": map[string]interface{}{"name": "IPA"}}, "editor")
+	if result.code(t) != CodeConflict {
+		t.Errorf("expected renaming to an existing name to conflict, got %+v", result.Errors)
+	}
+	_, result = server.query(t, update, map[string]interface{}{"uuid": "missing", "input": map[string]interface{}{}}, "editor")
+	if result.code(t) != CodeNotFound {
+		t.Errorf("expected a missing style, got %+v", result.Errors)
+	}
+
+	_, result = server.query(t, `mutation { deleteBeerStyle(uuid: "style-4") }`, nil, "editor")
+	if result.code(t) != "" || string(result.Data["deleteBeerStyle"]) != "true" {
+		t.Errorf("expected the style to be deleted, got %+v %s", result.Errors, result.Data["deleteBeerStyle"])
+	}
+	_, result = server.query(t, `mutation { deleteBeerStyle(uuid: "style-4") }`, nil, "editor")
+	if result.code(t) != CodeNotFound {
+		t.Errorf("expected a deleted style to be missing, got %+v", result.Errors)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	server := setupServer(t, nil)
+	seed := int64(1704110400000000000)
+	server.recommendations.response = &domain.RecommendationResponse{
+		BeerStyle: "IPA",
+		Playlist: domain.PlaylistInfo{
+			Provider:    "deezer",
+			Name:        "IPA Session",
+			Tracks:      []domain.TrackInfo{{Name: "Hop", Artist: "Band", Link: "https://example.com/hop"}},
+			ShuffleSeed: &seed,
+		},
+		Fallback: &domain.FallbackInfo{Step: "search", Query: "IPA"},
+	}
+
+	query := `query($options: RecommendOptions) {
+		recommend(temperature: 8, options: $options) {
+			beerStyle
+			playlist { name shuffleSeed tracks { name artists } }
+			fallback { step }
+		}
+	}`
+	options := map[string]interface{}{"options": map[string]interface{}{
+		"maxAbv": 7, "categories": []string{"Ale"}, "trackLimit": 5, "shuffle": true, "shuffleSeed": "1704110400000000000",
+	}}
+	_, result := server.query(t, query, options, "")
+	expected := `{"beerStyle":"IPA","fallback":{"step":"search"},"playlist":{"name":"IPA Session","shuffleSeed":"1704110400000000000","tracks":[{"artists":[],"name":"Hop"}]}}`
+	if result.code(t) != "" || string(result.Data["recommend"]) != expected {
+		t.Fatalf("expected %s, got %+v %s", expected, result.Errors, result.Data["recommend"])
+	}
+
+	request := server.recommendations.request
+	expectedRequest := domain.TemperatureRequest{
+		Temperature: 8, MaxABV: float(7), Categories: []string{"Ale"}, TrackLimit: 5, Shuffle: true, ShuffleSeed: &seed,
+	}
+	if !reflect.DeepEqual(request, expectedRequest) {
+		t.Errorf("expected %+v, got %+v", expectedRequest, request)
+	}
+
+	_, result = server.query(t, `{ recommend(temperature: 200) { beerStyle } }`, nil, "")
+	if result.code(t) != CodeBadUserInput {
+		t.Errorf("expected an out of range temperature to be rejected, got %+v", result.Errors)
+	}
+
+	server.recommendations.err = &service.ConstraintError{Constraint: "max_abv"}
+	_, result = server.query(t, `{ recommend(temperature: 8) { beerStyle } }`, nil, "")
+	if result.code(t) != CodeNotFound || result.Errors[0].Extensions["constraint"] != "max_abv" {
+		t.Errorf("expected the unsatisfiable constraint, got %+v", result.Errors)
+	}
+}
+
+func TestAccess_RateLimits(t *testing.T) {
+	server := setupServer(t, func(guard *Access, _ *config.GraphQLConfig) {
+		guard.Checker = access.NewChecker(ratelimit.NewLimiter(ratelimit.NewMemory(), func() time.Time { return testNow }, logging.Discard()))
+		guard.Rules.Read.Limit = ratelimit.PerMinute(30, 1)
+		guard.Rules.Read.Role = domain.RoleReader
+	})
+
+	_, result := server.query(t, `{ beerStyles { total } }`, nil, "")
+	if result.code(t) != CodeUnauthenticated {
+		t.Errorf("expected anonymous reads to be rejected, got %+v", result.Errors)
+	}
+	_, result = server.query(t, `{ beerStyles { total } }`, nil, "reader")
+	if result.code(t) != "" {
+		t.Errorf("expected the first read to pass, got %+v", result.Errors)
+	}
+	_, result = server.query(t, `{ beerStyles { total } }`, nil, "reader")
+	if result.code(t) != CodeRateLimited || result.Errors[0].Extensions["retryAfter"] != float64(2) {
+		t.Errorf("expected the second read to be limited, got %+v", result.Errors)
+	}
+}
@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Estimated sizes of the lists whose length is not set by an argument.
+const (
+	pinnedPlaylistsPerStyle = 10
+	tracksPerPlaylist       = 50
+)
+
+// recommendCost is the base cost of a recommendation, which calls the music
+// provider.
+const recommendCost = 20
+
+// cost is the depth and estimated cost of a selection.
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// limitError rejects a document over maxDepth or maxComplexity. Each
+// operation is measured on its own; introspection fields are free so tools
+// can load the schema.
+func limitError(schema graphql.Schema, document *ast.Document, variables map[string]interface{}, maxDepth, maxComplexity int) *Error {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	measure := measurer{fragments: fragments, variables: variables}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		root := schema.QueryType()
+		if operation.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+		}
+		if root == nil {
+			continue
+		}
+
+		measured := measure.selections(operation.SelectionSet, root, 0)
+		if measured.depth > maxDepth {
+			return &Error{
+				Code:    CodeQueryTooDeep,
+				Message: fmt.Sprintf("query depth %d exceeds the maximum of %d", measured.depth, maxDepth),
+				Details: map[string]interface{}{"depth": measured.depth, "maxDepth": maxDepth},
+			}
+		}
+		if measured.complexity > maxComplexity {
+			return &Error{
+				Code:    CodeQueryTooComplex,
+				Message: fmt.Sprintf("query complexity %d exceeds the maximum of %d", measured.complexity, maxComplexity),
+				Details: map[string]interface{}{"complexity": measured.complexity, "maxComplexity": maxComplexity},
+			}
+		}
+	}
+	return nil
+}
+
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections measures a selection set of parent. Fragments count as if
+// their fields were written in place. The document is validated first, so
+// fragments exist and do not cycle.
+func (m measurer) selections(set *ast.SelectionSet, parent *graphql.Object, depth int) cost {
+	var total cost
+	if set == nil {
+		return total
+	}
+
+	for _, selection := range set.Selections {
+		var measured cost
+		switch node := selection.(type) {
+		case *ast.Field:
+			measured = m.field(node, parent, depth)
+		case *ast.InlineFragment:
+			measured = m.selections(node.SelectionSet, parent, depth)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[node.Name.Value]; ok {
+				measured = m.selections(fragment.SelectionSet, parent, depth)
+			}
+		}
+		total.depth = max(total.depth, measured.depth)
+		total.complexity += measured.complexity
+	}
+	return total
+}
+
+func (m measurer) field(node *ast.Field, parent *graphql.Object, depth int) cost {
+	name := node.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return cost{}
+	}
+	definition, ok := parent.Fields()[name]
+	if !ok {
+		return cost{}
+	}
+
+	var children cost
+	if object, ok := graphql.GetNamed(definition.Type).(*graphql.Object); ok {
+		children = m.selections(node.SelectionSet, object, depth+1)
+	}
+
+	base, size := 1, 1
+	switch parent.Name() + "." + name {
+	case "Query.beerStyles":
+		size = m.pageLimit(node)
+	case "Query.recommend":
+		base = recommendCost
+	case "BeerStyle.playlists":
+		size = pinnedPlaylistsPerStyle
+	case "Playlist.tracks":
+		size = tracksPerPlaylist
+	}
+	return cost{
+		depth:      max(depth+1, children.depth),
+		complexity: base + size*children.complexity,
+	}
+}
+
+// pageLimit is the page size requested from beerStyles, clamped as the
+// resolver does.
+func (m measurer) pageLimit(node *ast.Field) int {
+	limit := defaultPageLimit
+	for _, argument := range node.Arguments {
+		if argument.Name.Value != "page" {
+			continue
+		}
+		switch page := argument.Value.(type) {
+		case *ast.ObjectValue:
+			for _, field := range page.Fields {
+				if field.Name.Value == "limit" {
+					if value, ok := m.intValue(field.Value); ok {
+						limit = value
+					}
+				}
+			}
+		case *ast.Variable:
+			if fields, ok := m.variables[page.Name.Value].(map[string]interface{}); ok {
+				if value, ok := toInt(fields["limit"]); ok {
+					limit = value
+				}
+			}
+		}
+	}
+	return min(max(limit, 1), maxPageLimit)
+}
+
+func (m measurer) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		parsed, err := strconv.Atoi(value.Value)
+		return parsed, err == nil
+	case *ast.Variable:
+		return toInt(m.variables[value.Name.Value])
+	}
+	return 0, false
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	case json.Number:
+		parsed, err := value.Int64()
+		return int(parsed), err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// playlistLoader batches the pinned playlist lookups of one request. The
+// playlists field returns a thunk that the executor resolves only after
+// every sibling object of the list, so the first thunk called loads all the
+// styles requested so far with a single query.
+type playlistLoader struct {
+	service service.PlaylistMappingServiceInterface
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	pending []string
+	loaded  map[string][]domain.BeerStylePlaylist
+	failed  map[string]error
+}
+
+func newPlaylistLoader(playlistMappingService service.PlaylistMappingServiceInterface, logger *slog.Logger) *playlistLoader {
+	return &playlistLoader{
+		service: playlistMappingService,
+		logger:  logger,
+		loaded:  make(map[string][]domain.BeerStylePlaylist),
+		failed:  make(map[string]error),
+	}
+}
+
+// load queues beerStyleUUID and returns the thunk resolving its playlists.
+func (l *playlistLoader) load(ctx context.Context, beerStyleUUID string) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.known(beerStyleUUID) {
+		l.pending = append(l.pending, beerStyleUUID)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			l.flush(ctx)
+		}
+		if err := l.failed[beerStyleUUID]; err != nil {
+			return nil, err
+		}
+		playlists := l.loaded[beerStyleUUID]
+		if playlists == nil {
+			playlists = []domain.BeerStylePlaylist{}
+		}
+		return playlists, nil
+	}
+}
+
+func (l *playlistLoader) known(beerStyleUUID string) bool {
+	if _, ok := l.loaded[beerStyleUUID]; ok {
+		return true
+	}
+	if _, ok := l.failed[beerStyleUUID]; ok {
+		return true
+	}
+	for _, pending := range l.pending {
+		if pending == beerStyleUUID {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *playlistLoader) flush(ctx context.Context) {
+	batch := l.pending
+	l.pending = nil
+
+	playlists, err := l.service.ListPlaylistsForBeerStyles(ctx, batch)
+	if err != nil {
+		l.logger.ErrorContext(ctx, "ListPlaylistsForBeerStyles failed", "beerStyles", len(batch), "err", err)
+	}
+	for _, beerStyleUUID := range batch {
+		if err != nil {
+			l.failed[beerStyleUUID] = newError(CodeInternal, "failed to load pinned playlists")
+			continue
+		}
+		l.loaded[beerStyleUUID] = playlists[beerStyleUUID]
+	}
+}
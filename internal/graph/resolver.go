@@ -0,0 +1,383 @@
+package graph
+
+import (
+	"backend-test/internal/access"
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Services are the services the resolvers are backed by, shared with the
+// REST controllers.
+type Services struct {
+	Beer            service.BeerServiceInterface
+	Validation      service.ValidationServiceInterface
+	Update          service.UpdateServiceInterface
+	Recommendation  service.RecommendationServiceInterface
+	PlaylistMapping service.PlaylistMappingServiceInterface
+}
+
+// resolver resolves the root fields with the checks of the REST
+// controllers. Each root field enforces the rule of its REST route.
+type resolver struct {
+	Services
+	checker *access.Checker
+	rules   access.Rules
+	logger  *slog.Logger
+}
+
+// requestState is what the resolvers of one request share.
+type requestState struct {
+	clientIP  string
+	playlists *playlistLoader
+}
+
+type requestStateKey struct{}
+
+func stateFrom(ctx context.Context) *requestState {
+	state, _ := ctx.Value(requestStateKey{}).(*requestState)
+	return state
+}
+
+// allow enforces rule for the caller of the request.
+func (r *resolver) allow(ctx context.Context, rule access.Rule) error {
+	if _, err := r.checker.Check(ctx, rule, stateFrom(ctx).clientIP); err != nil {
+		return accessError(err)
+	}
+	return nil
+}
+
+func (r *resolver) beerStyles(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Read); err != nil {
+		return nil, err
+	}
+
+	page, _ := p.Args["page"].(map[string]interface{})
+	offset, _ := page["offset"].(int)
+	limit, ok := page["limit"].(int)
+	if !ok {
+		limit = defaultPageLimit
+	}
+	if offset < 0 {
+		return nil, newError(CodeBadUserInput, "page.offset must not be negative")
+	}
+	if limit < 1 || limit > maxPageLimit {
+		return nil, newError(CodeBadUserInput, "page.limit must be between 1 and 100")
+	}
+
+	beerStyles, err := r.Beer.ListAllBeerStyles(p.Context)
+	if err != nil && !r.Validation.IsNoRowsError(err) {
+		r.logger.ErrorContext(p.Context, "beerStyles failed", "err", err)
+		return nil, newError(CodeInternal, "internal error")
+	}
+
+	filter, _ := p.Args["filter"].(map[string]interface{})
+	matching := make([]domain.BeerStyle, 0, len(beerStyles))
+	for _, beerStyle := range beerStyles {
+		if matches(beerStyle, filter) {
+			matching = append(matching, beerStyle)
+		}
+	}
+
+	order, _ := p.Args["sort"].(map[string]interface{})
+	field, _ := order["field"].(string)
+	direction, _ := order["direction"].(string)
+	sortBeerStyles(matching, field, direction == "desc")
+
+	items := matching[min(offset, len(matching)):min(offset+limit, len(matching))]
+	return map[string]interface{}{
+		"items":  items,
+		"total":  len(matching),
+		"offset": offset,
+		"limit":  limit,
+	}, nil
+}
+
+// matches reports whether beerStyle satisfies every field of filter.
+func matches(beerStyle domain.BeerStyle, filter map[string]interface{}) bool {
+	if name, ok := filter["name"].(string); ok {
+		name = strings.ToLower(name)
+		found := strings.Contains(strings.ToLower(beerStyle.Name), name)
+		for _, alias := range beerStyle.Aliases {
+			found = found || strings.Contains(strings.ToLower(alias), name)
+		}
+		if !found {
+			return false
+		}
+	}
+	if category, ok := filter["category"].(string); ok && !strings.EqualFold(beerStyle.Category, category) {
+		return false
+	}
+	if temperature, ok := filter["temperature"].(float64); ok && (temperature < beerStyle.TempMin || temperature > beerStyle.TempMax) {
+		return false
+	}
+	if minABV, ok := filter["minAbv"].(float64); ok && (beerStyle.ABV == nil || *beerStyle.ABV < minABV) {
+		return false
+	}
+	if maxABV, ok := filter["maxAbv"].(float64); ok && (beerStyle.ABV == nil || *beerStyle.ABV > maxABV) {
+		return false
+	}
+	return true
+}
+
+// sortBeerStyles orders by field, then by name. Styles without ABV come
+// last when sorting by it, whatever the direction.
+func sortBeerStyles(beerStyles []domain.BeerStyle, field string, descending bool) {
+	sort.SliceStable(beerStyles, func(i, j int) bool {
+		a, b := beerStyles[i], beerStyles[j]
+		var compared int
+		switch field {
+		case "temp_min":
+			compared = compareFloat(a.TempMin, b.TempMin)
+		case "temp_max":
+			compared = compareFloat(a.TempMax, b.TempMax)
+		case "abv":
+			if (a.ABV == nil) != (b.ABV == nil) {
+				return b.ABV == nil
+			}
+			if a.ABV != nil {
+				compared = compareFloat(*a.ABV, *b.ABV)
+			}
+		case "created_at":
+			compared = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if compared == 0 {
+			compared = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}
+		if descending {
+			return compared > 0
+		}
+		return compared < 0
+	})
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (r *resolver) beerStyle(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Read); err != nil {
+		return nil, err
+	}
+
+	beerUUID, _ := p.Args["uuid"].(string)
+	beerStyle, err := r.Beer.GetBeerStyleByUUID(p.Context, beerUUID)
+	if err != nil {
+		if r.Validation.IsNoRowsError(err) {
+			return nil, nil
+		}
+		r.logger.ErrorContext(p.Context, "beerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, newError(CodeInternal, "internal error")
+	}
+	return beerStyle, nil
+}
+
+// playlists defers to the request loader so the playlists of every style in
+// the response are loaded together.
+func (r *resolver) playlists(p graphql.ResolveParams) (interface{}, error) {
+	beerStyle, _ := p.Source.(domain.BeerStyle)
+	return stateFrom(p.Context).playlists.load(p.Context, beerStyle.UUID), nil
+}
+
+func (r *resolver) recommend(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Recommend); err != nil {
+		return nil, err
+	}
+
+	temperature, _ := p.Args["temperature"].(float64)
+	options, _ := p.Args["options"].(map[string]interface{})
+	request := temperatureRequest(temperature, options)
+
+	if err := r.Validation.ValidateTemperatureInput(request.Temperature); err != nil {
+		r.logger.WarnContext(p.Context, "recommend failed", "temperature", request.Temperature, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+	if err := r.Validation.ValidateRecommendationPreferences(request); err != nil {
+		r.logger.WarnContext(p.Context, "recommend failed", "temperature", request.Temperature, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+
+	recommendation, err := r.Recommendation.GetRecommendationForTemperature(p.Context, request)
+	if err != nil {
+		r.logger.WarnContext(p.Context, "recommend failed", "temperature", request.Temperature, "err", err)
+		return nil, recommendationError(err)
+	}
+	return recommendation, nil
+}
+
+func temperatureRequest(temperature float64, options map[string]interface{}) domain.TemperatureRequest {
+	request := domain.TemperatureRequest{
+		Temperature:   temperature,
+		ExcludeStyles: stringsArg(options["excludeStyles"]),
+		IncludeOnly:   stringsArg(options["includeOnly"]),
+		Categories:    stringsArg(options["categories"]),
+	}
+	if maxABV, ok := options["maxAbv"].(float64); ok {
+		request.MaxABV = &maxABV
+	}
+	request.MinTracks, _ = options["minTracks"].(int)
+	request.TrackLimit, _ = options["trackLimit"].(int)
+	request.Shuffle, _ = options["shuffle"].(bool)
+	if seed, ok := options["shuffleSeed"].(int64); ok {
+		request.ShuffleSeed = &seed
+	}
+	request.DistinctArtists, _ = options["distinctArtists"].(bool)
+	request.Provider, _ = options["provider"].(string)
+	return request
+}
+
+func stringsArg(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+func (r *resolver) createBeerStyle(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Write); err != nil {
+		return nil, err
+	}
+
+	input, _ := p.Args["input"].(map[string]interface{})
+	inputStyle := domain.BeerStyle{Aliases: stringsArg(input["aliases"])}
+	inputStyle.Name, _ = input["name"].(string)
+	inputStyle.TempMin, _ = input["tempMin"].(float64)
+	inputStyle.TempMax, _ = input["tempMax"].(float64)
+	inputStyle.Category, _ = input["category"].(string)
+	if abv, ok := input["abv"].(float64); ok {
+		inputStyle.ABV = &abv
+	}
+	if inputStyle.Name == "" {
+		return nil, newError(CodeBadUserInput, "name is required")
+	}
+
+	if err := r.Validation.ValidateUniqueNameForCreate(p.Context, inputStyle.Name); err != nil {
+		r.logger.WarnContext(p.Context, "createBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, nameError(err)
+	}
+	if err := r.Validation.ValidateTemperatureRange(inputStyle); err != nil {
+		r.logger.WarnContext(p.Context, "createBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+	if err := r.Validation.ValidateABV(inputStyle.ABV); err != nil {
+		r.logger.WarnContext(p.Context, "createBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+
+	newBeerStyle, err := r.Beer.CreateBeerStyle(p.Context, inputStyle)
+	if err != nil {
+		r.logger.ErrorContext(p.Context, "createBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, newError(CodeInternal, "failed to create beer style")
+	}
+	return newBeerStyle, nil
+}
+
+func (r *resolver) updateBeerStyle(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Write); err != nil {
+		return nil, err
+	}
+
+	beerUUID, _ := p.Args["uuid"].(string)
+	input, _ := p.Args["input"].(map[string]interface{})
+	updates := beerStyleUpdate(input)
+
+	currentBeerStyle, err := r.Beer.GetBeerStyleByUUID(p.Context, beerUUID)
+	if err != nil {
+		return nil, r.lookupError(p.Context, "updateBeerStyle", beerUUID, err)
+	}
+
+	if updates.Name != nil && *updates.Name != "" && *updates.Name != currentBeerStyle.Name {
+		if err := r.Validation.ValidateUniqueNameForUpdate(p.Context, *updates.Name, currentBeerStyle.UUID); err != nil {
+			r.logger.WarnContext(p.Context, "updateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+			return nil, nameError(err)
+		}
+	}
+
+	if !r.Update.ApplyBeerStyleUpdates(&currentBeerStyle, updates) {
+		return currentBeerStyle, nil
+	}
+
+	if err := r.Validation.ValidateTemperatureRange(currentBeerStyle); err != nil {
+		r.logger.WarnContext(p.Context, "updateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+	if err := r.Validation.ValidateABV(currentBeerStyle.ABV); err != nil {
+		r.logger.WarnContext(p.Context, "updateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, newError(CodeBadUserInput, err.Error())
+	}
+
+	updatedBeerStyle, err := r.Beer.UpdateBeerStyle(p.Context, currentBeerStyle)
+	if err != nil {
+		r.logger.ErrorContext(p.Context, "updateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, newError(CodeInternal, "failed to update beer style")
+	}
+	return updatedBeerStyle, nil
+}
+
+func beerStyleUpdate(input map[string]interface{}) domain.BeerStyleUpdateRequest {
+	var updates domain.BeerStyleUpdateRequest
+	if name, ok := input["name"].(string); ok {
+		updates.Name = &name
+	}
+	if tempMin, ok := input["tempMin"].(float64); ok {
+		updates.TempMin = &tempMin
+	}
+	if tempMax, ok := input["tempMax"].(float64); ok {
+		updates.TempMax = &tempMax
+	}
+	if category, ok := input["category"].(string); ok {
+		updates.Category = &category
+	}
+	if abv, ok := input["abv"].(float64); ok {
+		updates.ABV = &abv
+	}
+	if _, ok := input["aliases"].([]interface{}); ok {
+		aliases := stringsArg(input["aliases"])
+		updates.Aliases = &aliases
+	}
+	return updates
+}
+
+func (r *resolver) deleteBeerStyle(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.allow(p.Context, r.rules.Write); err != nil {
+		return nil, err
+	}
+
+	beerUUID, _ := p.Args["uuid"].(string)
+	if _, err := r.Beer.GetBeerStyleByUUID(p.Context, beerUUID); err != nil {
+		return nil, r.lookupError(p.Context, "deleteBeerStyle", beerUUID, err)
+	}
+
+	if err := r.Beer.DeleteBeerStyle(p.Context, beerUUID); err != nil {
+		r.logger.ErrorContext(p.Context, "deleteBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, newError(CodeInternal, "internal error")
+	}
+	return true, nil
+}
+
+// lookupError maps the failure to load the beer style of a mutation.
+func (r *resolver) lookupError(ctx context.Context, operation, beerUUID string, err error) error {
+	r.logger.ErrorContext(ctx, operation+" failed", "beerUUID", beerUUID, "err", err)
+	if r.Validation.IsNoRowsError(err) {
+		return newError(CodeNotFound, "beer style not found")
+	}
+	return newError(CodeInternal, "internal error")
+}
@@ -0,0 +1,337 @@
+package graph
+
+import (
+	"backend-test/internal/domain"
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Page sizes of beerStyles.
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// int64Scalar carries shuffle seeds, which overflow the 32-bit Int. It is
+// serialized as a string, like the int64 fields of protobuf JSON, so
+// JavaScript clients keep every digit; integer inputs are accepted too.
+var int64Scalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Int64",
+	Description: "A 64-bit integer, serialized as a string.",
+	Serialize: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case int64:
+			return strconv.FormatInt(value, 10)
+		case *int64:
+			if value == nil {
+				return nil
+			}
+			return strconv.FormatInt(*value, 10)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case string:
+			if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return parsed
+			}
+		case int:
+			return int64(value)
+		case float64:
+			if value == math.Trunc(value) && math.Abs(value) <= 1<<53 {
+				return int64(value)
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(value ast.Value) interface{} {
+		switch value := value.(type) {
+		case *ast.IntValue:
+			if parsed, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				return parsed
+			}
+		case *ast.StringValue:
+			if parsed, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				return parsed
+			}
+		}
+		return nil
+	},
+})
+
+// stringList resolves a list field that is empty rather than null when unset.
+func stringList(values func(source interface{}) []string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		list := values(p.Source)
+		if list == nil {
+			list = []string{}
+		}
+		return list, nil
+	}
+}
+
+func nonNullList(of graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(of)))
+}
+
+var pinnedPlaylistType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PinnedPlaylist",
+	Description: "A provider playlist pinned to a beer style, preferred over searching by the style name.",
+	Fields: graphql.Fields{
+		"uuid":       {Type: graphql.NewNonNull(graphql.ID)},
+		"provider":   {Type: graphql.NewNonNull(graphql.String)},
+		"playlistId": {Type: graphql.NewNonNull(graphql.String)},
+		"weight":     {Type: graphql.NewNonNull(graphql.Int)},
+		"createdAt":  {Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt":  {Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var trackType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Track",
+	Fields: graphql.Fields{
+		"name":   {Type: graphql.NewNonNull(graphql.String)},
+		"artist": {Type: graphql.NewNonNull(graphql.String)},
+		"link":   {Type: graphql.NewNonNull(graphql.String)},
+		"artists": {
+			Type:    nonNullList(graphql.String),
+			Resolve: stringList(func(source interface{}) []string { return source.(domain.TrackInfo).Artists }),
+		},
+		"album":       {Type: graphql.String},
+		"durationMs":  {Type: graphql.Int},
+		"explicit":    {Type: graphql.NewNonNull(graphql.Boolean)},
+		"popularity":  {Type: graphql.Int},
+		"previewUrl":  {Type: graphql.String},
+		"isrc":        {Type: graphql.String},
+		"albumArtUrl": {Type: graphql.String},
+	},
+})
+
+var playlistType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Playlist",
+	Fields: graphql.Fields{
+		"id":       {Type: graphql.String},
+		"provider": {Type: graphql.String},
+		"name":     {Type: graphql.NewNonNull(graphql.String)},
+		"tracks":   {Type: nonNullList(trackType)},
+		"shuffleSeed": {
+			Type:        int64Scalar,
+			Description: "The seed of a shuffled playlist, to reproduce its order.",
+		},
+	},
+})
+
+var fallbackType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Fallback",
+	Description: "The step of the playlist fallback chain that produced the playlist.",
+	Fields: graphql.Fields{
+		"step":  {Type: graphql.NewNonNull(graphql.String)},
+		"query": {Type: graphql.String},
+	},
+})
+
+var recommendationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Recommendation",
+	Fields: graphql.Fields{
+		"id": {
+			Type:        graphql.String,
+			Description: "Identifies the recommendation in the history; empty when it was not recorded.",
+		},
+		"beerStyle": {Type: graphql.NewNonNull(graphql.String)},
+		"playlist":  {Type: graphql.NewNonNull(playlistType)},
+		"fallback": {
+			Type:        fallbackType,
+			Description: "Null when the playlist was pinned to the style or found by its name.",
+		},
+	},
+})
+
+var sortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		"ASC":  {Value: "asc"},
+		"DESC": {Value: "desc"},
+	},
+})
+
+var beerStyleSortFieldEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "BeerStyleSortField",
+	Values: graphql.EnumValueConfigMap{
+		"NAME":       {Value: "name"},
+		"TEMP_MIN":   {Value: "temp_min"},
+		"TEMP_MAX":   {Value: "temp_max"},
+		"ABV":        {Value: "abv", Description: "Styles without ABV come last in both directions."},
+		"CREATED_AT": {Value: "created_at"},
+	},
+})
+
+var beerStyleFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BeerStyleFilter",
+	Description: "Every given field must match.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":        {Type: graphql.String, Description: "Part of the name or of an alias, ignoring case."},
+		"category":    {Type: graphql.String, Description: "The category, ignoring case."},
+		"temperature": {Type: graphql.Float, Description: "A temperature within the serving range."},
+		"minAbv":      {Type: graphql.Float},
+		"maxAbv":      {Type: graphql.Float},
+	},
+})
+
+var beerStyleSortInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "BeerStyleSort",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":     {Type: beerStyleSortFieldEnum, DefaultValue: "name"},
+		"direction": {Type: sortDirectionEnum, DefaultValue: "asc"},
+	},
+})
+
+var pageInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PageInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"offset": {Type: graphql.Int, DefaultValue: 0},
+		"limit":  {Type: graphql.Int, DefaultValue: defaultPageLimit, Description: "At most 100."},
+	},
+})
+
+var recommendOptionsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RecommendOptions",
+	Description: "The preferences of POST /api/recommendations/suggest.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"excludeStyles":   {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"includeOnly":     {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"maxAbv":          {Type: graphql.Float},
+		"categories":      {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"minTracks":       {Type: graphql.Int},
+		"trackLimit":      {Type: graphql.Int},
+		"shuffle":         {Type: graphql.Boolean},
+		"shuffleSeed":     {Type: int64Scalar},
+		"distinctArtists": {Type: graphql.Boolean},
+		"provider":        {Type: graphql.String},
+	},
+})
+
+var beerStyleInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "BeerStyleInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":     {Type: graphql.NewNonNull(graphql.String)},
+		"tempMin":  {Type: graphql.NewNonNull(graphql.Float)},
+		"tempMax":  {Type: graphql.NewNonNull(graphql.Float)},
+		"category": {Type: graphql.String},
+		"abv":      {Type: graphql.Float},
+		"aliases":  {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+var beerStyleChangesInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BeerStyleChanges",
+	Description: "The fields to change; omitted and null fields are kept.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":     {Type: graphql.String},
+		"tempMin":  {Type: graphql.Float},
+		"tempMax":  {Type: graphql.Float},
+		"category": {Type: graphql.String},
+		"abv":      {Type: graphql.Float},
+		"aliases":  {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+// newSchema builds the schema around the resolvers of r.
+func newSchema(r *resolver) (graphql.Schema, error) {
+	beerStyleType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BeerStyle",
+		Fields: graphql.Fields{
+			"uuid":     {Type: graphql.NewNonNull(graphql.ID)},
+			"name":     {Type: graphql.NewNonNull(graphql.String)},
+			"tempMin":  {Type: graphql.NewNonNull(graphql.Float)},
+			"tempMax":  {Type: graphql.NewNonNull(graphql.Float)},
+			"category": {Type: graphql.NewNonNull(graphql.String)},
+			"abv":      {Type: graphql.Float},
+			"aliases": {
+				Type:    nonNullList(graphql.String),
+				Resolve: stringList(func(source interface{}) []string { return source.(domain.BeerStyle).Aliases }),
+			},
+			"createdAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+			"playlists": {
+				Type:        nonNullList(pinnedPlaylistType),
+				Description: "The pinned playlists, heaviest first. Loaded in one batch for every style of the response.",
+				Resolve:     r.playlists,
+			},
+		},
+	})
+
+	beerStylePageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BeerStylePage",
+		Fields: graphql.Fields{
+			"items":  {Type: nonNullList(beerStyleType)},
+			"total":  {Type: graphql.NewNonNull(graphql.Int), Description: "The number of styles matching the filter."},
+			"offset": {Type: graphql.NewNonNull(graphql.Int)},
+			"limit":  {Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"beerStyles": {
+				Type: graphql.NewNonNull(beerStylePageType),
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: beerStyleFilterInput},
+					"sort":   {Type: beerStyleSortInput},
+					"page":   {Type: pageInput},
+				},
+				Resolve: r.beerStyles,
+			},
+			"beerStyle": {
+				Type:        beerStyleType,
+				Description: "The beer style, or null when it does not exist.",
+				Args: graphql.FieldConfigArgument{
+					"uuid": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.beerStyle,
+			},
+			"recommend": {
+				Type: graphql.NewNonNull(recommendationType),
+				Args: graphql.FieldConfigArgument{
+					"temperature": {Type: graphql.NewNonNull(graphql.Float)},
+					"options":     {Type: recommendOptionsInput},
+				},
+				Resolve: r.recommend,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBeerStyle": {
+				Type: graphql.NewNonNull(beerStyleType),
+				Args: graphql.FieldConfigArgument{
+					"input": {Type: graphql.NewNonNull(beerStyleInput)},
+				},
+				Resolve: r.createBeerStyle,
+			},
+			"updateBeerStyle": {
+				Type: graphql.NewNonNull(beerStyleType),
+				Args: graphql.FieldConfigArgument{
+					"uuid":  {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(beerStyleChangesInput)},
+				},
+				Resolve: r.updateBeerStyle,
+			},
+			"deleteBeerStyle": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"uuid": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.deleteBeerStyle,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
@@ -0,0 +1,117 @@
+// Package graph serves the beer styles and recommendations over GraphQL at
+// /graphql, backed by the services of the REST API. Queries are checked
+// against depth and complexity limits before they run, and the pinned
+// playlists of the styles in a response are loaded in one batch.
+package graph
+
+import (
+	"backend-test/internal/access"
+	config "backend-test/internal/cmd/server"
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Access holds the rules enforced by the root fields: reads for the beer
+// style queries, recommendations for recommend and writes for the
+// mutations.
+type Access struct {
+	Checker *access.Checker
+	Rules   access.Rules
+}
+
+// Server executes GraphQL requests.
+type Server struct {
+	schema        graphql.Schema
+	resolver      *resolver
+	maxDepth      int
+	maxComplexity int
+	logger        *slog.Logger
+}
+
+func NewServer(services Services, guard Access, cfg config.GraphQLConfig, logger *slog.Logger) (*Server, error) {
+	logger = logger.With("component", "graphql")
+	r := &resolver{Services: services, checker: guard.Checker, rules: guard.Rules, logger: logger}
+
+	schema, err := newSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		schema:        schema,
+		resolver:      r,
+		maxDepth:      cfg.MaxDepth,
+		maxComplexity: cfg.MaxComplexity,
+		logger:        logger,
+	}, nil
+}
+
+// Request is the body of POST /graphql.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Handle answers 400 when the request cannot run at all: a body that is not
+// JSON, a query that does not parse or validate, or one over the limits.
+// Once it runs, the response is 200 with the data and the errors of the
+// fields that failed, each with a code in its extensions.
+func (s *Server) Handle(c *gin.Context) {
+	var request Request
+	if err := c.ShouldBindJSON(&request); err != nil || request.Query == "" {
+		s.reject(c, newError(CodeBadUserInput, "the body must be a JSON object with a query"))
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(request.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		formatted := gqlerrors.FormatError(err)
+		formatted.Extensions = map[string]interface{}{"code": CodeInvalidQuery}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gqlerrors.FormattedError{formatted}})
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&s.schema, document, nil); !validation.IsValid {
+		for i := range validation.Errors {
+			validation.Errors[i].Extensions = map[string]interface{}{"code": CodeInvalidQuery}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validation.Errors})
+		return
+	}
+
+	if limitErr := limitError(s.schema, document, request.Variables, s.maxDepth, s.maxComplexity); limitErr != nil {
+		s.logger.WarnContext(c.Request.Context(), "query rejected", "code", limitErr.Code, "err", limitErr)
+		s.reject(c, limitErr)
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), requestStateKey{}, &requestState{
+		clientIP:  c.ClientIP(),
+		playlists: newPlaylistLoader(s.resolver.PlaylistMapping, s.logger),
+	})
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+	result.Errors = withCodes(result.Errors)
+	c.JSON(http.StatusOK, result)
+}
+
+func (s *Server) reject(c *gin.Context, err *Error) {
+	formatted := gqlerrors.NewFormattedError(err.Message)
+	formatted.Extensions = err.Extensions()
+	c.JSON(http.StatusBadRequest, gin.H{"errors": []gqlerrors.FormattedError{formatted}})
+}
@@ -0,0 +1,115 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+var largeBody = strings.Repeat(`{"name":"IPA","category":"Ale"},`, 100)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Middleware(1024))
+	router.GET("/large", func(c *gin.Context) {
+		c.Header("ETag", `"v1"`)
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeBody))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`{"ok":true}`))
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(largeBody))
+	})
+	router.GET("/not-modified", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusNotModified)
+	})
+	return router
+}
+
+func get(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNegotiate(t *testing.T) {
+	for accept, expected := range map[string]string{
+		"":                    "",
+		"identity":            "",
+		"gzip":                Gzip,
+		"gzip, deflate, br":   Brotli,
+		"br;q=0.5, gzip":      Gzip,
+		"*":                   Brotli,
+		"gzip;q=0, br;q=0":    "",
+		"deflate, GZIP;q=0.8": Gzip,
+	} {
+		if got := Negotiate(accept); got != expected {
+			t.Errorf("Negotiate(%q): expected %q, got %q", accept, expected, got)
+		}
+	}
+}
+
+func TestMiddleware_Compresses(t *testing.T) {
+	router := setupRouter()
+
+	readers := map[string]func(io.Reader) (io.Reader, error){
+		Gzip:   func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		Brotli: func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+	for encoding, newReader := range readers {
+		w := get(router, "/large", encoding)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != encoding {
+			t.Fatalf("expected a %s body, got %d %v", encoding, w.Code, w.Header())
+		}
+		if w.Header().Get("ETag") != `W/"v1"` || w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("expected a weak ETag and Vary, got %v", w.Header())
+		}
+		if w.Body.Len() >= len(largeBody) {
+			t.Errorf("expected %s to shrink the body, got %d bytes", encoding, w.Body.Len())
+		}
+
+		reader, err := newReader(w.Body)
+		if err != nil {
+			t.Fatalf("invalid %s body: %v", encoding, err)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil || string(body) != largeBody {
+			t.Errorf("expected the original body back from %s, got %v", encoding, err)
+		}
+	}
+}
+
+func TestMiddleware_SkipsWhenNotWorthIt(t *testing.T) {
+	router := setupRouter()
+
+	for _, tt := range []struct{ name, path, acceptEncoding string }{
+		{"below the threshold", "/small", "gzip"},
+		{"already compressed type", "/image", "gzip"},
+		{"identity requested", "/large", ""},
+	} {
+		w := get(router, tt.path, tt.acceptEncoding)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+			t.Errorf("%s: expected an uncompressed body, got %d %v", tt.name, w.Code, w.Header())
+		}
+	}
+
+	if w := get(router, "/small", "gzip"); w.Body.String() != `{"ok":true}` {
+		t.Errorf("expected the small body as is, got %q", w.Body.String())
+	}
+	if w := get(router, "/not-modified", "br"); w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected a bare 304, got %d %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	minTemperatureBucket = 0.5
+	maxTemperatureBucket = 50.0
+)
+
+type AnalyticsController struct {
+	HistoryService service.RecommendationHistoryServiceInterface
+	Logger         *slog.Logger
+}
+
+func NewAnalyticsController(historyService service.RecommendationHistoryServiceInterface, logger *slog.Logger) *AnalyticsController {
+	return &AnalyticsController{
+		HistoryService: historyService,
+		Logger:         loggerOrDefault(logger).With("controller", "AnalyticsController"),
+	}
+}
+
+// GetRecommendationAnalytics aggregates the recommendation history between
+// ?from and ?to by beer style, temperature bucket and day.
+func (ac *AnalyticsController) GetRecommendationAnalytics(c *gin.Context) {
+	query, err := analyticsQuery(c)
+	if err != nil {
+		ac.Logger.WarnContext(c.Request.Context(), "GetRecommendationAnalytics failed", "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	analytics, err := ac.HistoryService.GetRecommendationAnalytics(c.Request.Context(), query)
+	if err != nil {
+		ac.Logger.ErrorContext(c.Request.Context(), "GetRecommendationAnalytics failed", "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// analyticsQuery reads the optional from, to and bucket_size parameters.
+// Dates without a time start at midnight UTC.
+func analyticsQuery(c *gin.Context) (domain.AnalyticsQuery, error) {
+	var query domain.AnalyticsQuery
+	var err error
+
+	if query.From, err = parseAnalyticsTime("from", c.Query("from")); err != nil {
+		return query, err
+	}
+	if query.To, err = parseAnalyticsTime("to", c.Query("to")); err != nil {
+		return query, err
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return query, fmt.Errorf("from must be before to")
+	}
+
+	if value := c.Query("bucket_size"); value != "" {
+		query.BucketSize, err = strconv.ParseFloat(value, 64)
+		if err != nil || query.BucketSize < minTemperatureBucket || query.BucketSize > maxTemperatureBucket {
+			return query, fmt.Errorf("bucket_size must be a number between %.1f and %.1f", minTemperatureBucket, maxTemperatureBucket)
+		}
+	}
+
+	return query, nil
+}
+
+func parseAnalyticsTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be a date (2006-01-02) or an RFC 3339 time", name)
+}
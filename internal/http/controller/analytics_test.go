@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockHistoryService struct {
+	lastQuery domain.AnalyticsQuery
+}
+
+func (m *mockHistoryService) GetRecommendationAnalytics(ctx context.Context, query domain.AnalyticsQuery) (*domain.RecommendationAnalytics, error) {
+	m.lastQuery = query
+	return &domain.RecommendationAnalytics{From: query.From, To: query.To, BucketSize: query.BucketSize}, nil
+}
+
+func TestAnalyticsController_GetRecommendationAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	historyService := &mockHistoryService{}
+	controller := NewAnalyticsController(historyService, logging.Discard())
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"", http.StatusOK},
+		{"?from=2024-03-01&to=2024-03-08T12:00:00Z&bucket_size=2.5", http.StatusOK},
+		{"?from=yesterday", http.StatusBadRequest},
+		{"?from=2024-03-08&to=2024-03-01", http.StatusBadRequest},
+		{"?bucket_size=0", http.StatusBadRequest},
+		{"?bucket_size=warm", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/analytics/recommendations"+tt.query, nil)
+
+		controller.GetRecommendationAnalytics(c)
+
+		if w.Code != tt.expected {
+			t.Errorf("Query %q: expected status %d, got %d %s", tt.query, tt.expected, w.Code, w.Body.String())
+		}
+	}
+
+	// The second case is the last one reaching the service.
+	query := historyService.lastQuery
+	if !query.From.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || !query.To.Equal(time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)) || query.BucketSize != 2.5 {
+		t.Errorf("Unexpected query: %+v", query)
+	}
+}
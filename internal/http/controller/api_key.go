@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController struct {
+	APIKeyService     service.APIKeyServiceInterface
+	ValidationService service.ValidationServiceInterface
+	Logger            *slog.Logger
+}
+
+func NewAPIKeyController(apiKeyService service.APIKeyServiceInterface, validationService service.ValidationServiceInterface, logger *slog.Logger) *APIKeyController {
+	return &APIKeyController{
+		APIKeyService:     apiKeyService,
+		ValidationService: validationService,
+		Logger:            loggerOrDefault(logger).With("controller", "APIKeyController"),
+	}
+}
+
+func (ac *APIKeyController) ListAPIKeys(c *gin.Context) {
+	apiKeys, err := ac.APIKeyService.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		ac.Logger.ErrorContext(c.Request.Context(), "ListAPIKeys failed", "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "internal error",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, apiKeysResponse{APIKeys: apiKeys})
+}
+
+func (ac *APIKeyController) CreateAPIKey(c *gin.Context) {
+	var request domain.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	if err := ac.ValidationService.ValidateAPIKeyRequest(request); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	created, err := ac.APIKeyService.CreateAPIKey(c.Request.Context(), request)
+	if err != nil {
+		ac.Logger.ErrorContext(c.Request.Context(), "CreateAPIKey failed", "name", request.Name, "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "failed to create API key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Store the key now, it cannot be shown again.",
+		"data":    created,
+	})
+}
+
+func (ac *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	keyUUID := c.Param("keyUUID")
+	if err := ac.ValidationService.ValidateUUID(keyUUID); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := ac.APIKeyService.RevokeAPIKey(c.Request.Context(), keyUUID); err != nil {
+		status := http.StatusInternalServerError
+		message := "failed to revoke API key"
+
+		if ac.ValidationService.IsNoRowsError(err) {
+			status = http.StatusNotFound
+			message = "API key not found or already revoked"
+		} else {
+			ac.Logger.ErrorContext(c.Request.Context(), "RevokeAPIKey failed", "keyUUID", keyUUID, "err", err)
+		}
+
+		respondError(c, status, gin.H{
+			"message": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked",
+	})
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/service"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockAPIKeyService struct {
+	revoked []string
+}
+
+func (m *mockAPIKeyService) CreateAPIKey(ctx context.Context, request domain.APIKeyRequest) (domain.CreatedAPIKey, error) {
+	return domain.CreatedAPIKey{
+		APIKey: domain.APIKey{UUID: "key-uuid", Name: request.Name, Prefix: "bk_abcdefgh", KeyHash: "hash", Role: string(request.Role)},
+		Key:    "bk_abcdefgh-secret",
+	}, nil
+}
+
+func (m *mockAPIKeyService) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return []domain.APIKey{{UUID: "key-uuid", Name: "ci", Prefix: "bk_abcdefgh", KeyHash: "hash", Role: "editor"}}, nil
+}
+
+func (m *mockAPIKeyService) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	if keyUUID != "6f1c1d3a-9a43-4d0e-8a3b-4f6ad6f1a001" {
+		return sql.ErrNoRows
+	}
+	m.revoked = append(m.revoked, keyUUID)
+	return nil
+}
+
+func (m *mockAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error) {
+	return domain.Principal{}, service.ErrInvalidAPIKey
+}
+
+func setupAPIKeyController(apiKeyService *mockAPIKeyService) *APIKeyController {
+	gin.SetMode(gin.TestMode)
+	return NewAPIKeyController(apiKeyService, service.NewValidationService(nil), logging.Discard())
+}
+
+func TestAPIKeyController_CreateAPIKey(t *testing.T) {
+	controller := setupAPIKeyController(&mockAPIKeyService{})
+
+	tests := []struct {
+		body     string
+		expected int
+	}{
+		{`{"name": "ci", "role": "editor"}`, http.StatusCreated},
+		{`{"name": "", "role": "editor"}`, http.StatusBadRequest},
+		{`{"name": "ci", "role": "owner"}`, http.StatusBadRequest},
+		{`{"name": "ci", "role": 1}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/api-keys", bytes.NewBufferString(tt.body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.CreateAPIKey(c)
+
+		if w.Code != tt.expected {
+			t.Errorf("Body %s: expected status %d, got %d", tt.body, tt.expected, w.Code)
+		}
+		if w.Code == http.StatusCreated && !strings.Contains(w.Body.String(), `"key":"bk_abcdefgh-secret"`) {
+			t.Errorf("Expected the plain key in the response, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestAPIKeyController_ListAPIKeysHidesHashes(t *testing.T) {
+	controller := setupAPIKeyController(&mockAPIKeyService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/auth/api-keys", nil)
+
+	controller.ListAPIKeys(c)
+
+	var response map[string][]map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if _, ok := response["apiKeys"][0]["key_hash"]; ok || strings.Contains(w.Body.String(), "hash") {
+		t.Errorf("Expected the key hash to be hidden, got %s", w.Body.String())
+	}
+}
+
+func TestAPIKeyController_RevokeAPIKey(t *testing.T) {
+	apiKeyService := &mockAPIKeyService{}
+	controller := setupAPIKeyController(apiKeyService)
+
+	tests := map[string]int{
+		"6f1c1d3a-9a43-4d0e-8a3b-4f6ad6f1a001": http.StatusOK,
+		"6f1c1d3a-9a43-4d0e-8a3b-4f6ad6f1a002": http.StatusNotFound,
+		"not-a-uuid":                           http.StatusBadRequest,
+	}
+
+	for keyUUID, expected := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("DELETE", "/api/auth/api-keys/"+keyUUID, nil)
+		c.Params = gin.Params{{Key: "keyUUID", Value: keyUUID}}
+
+		controller.RevokeAPIKey(c)
+
+		if w.Code != expected {
+			t.Errorf("Key %s: expected status %d, got %d", keyUUID, expected, w.Code)
+		}
+	}
+
+	if len(apiKeyService.revoked) != 1 {
+		t.Errorf("Expected one revoked key, got %v", apiKeyService.revoked)
+	}
+}
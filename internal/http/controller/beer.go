@@ -2,10 +2,13 @@ package controller
 
 import (
 	"backend-test/internal/domain"
+	"backend-test/internal/http/headers"
+	"backend-test/internal/http/serializer"
 	"backend-test/internal/service"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -15,20 +18,22 @@ type BeerController struct {
 	BeerService       service.BeerServiceInterface
 	ValidationService service.ValidationServiceInterface
 	UpdateService     service.UpdateServiceInterface
+	Logger            *slog.Logger
 }
 
-func NewBeerController(beerService service.BeerServiceInterface, validationService service.ValidationServiceInterface, updateService service.UpdateServiceInterface) *BeerController {
+func NewBeerController(beerService service.BeerServiceInterface, validationService service.ValidationServiceInterface, updateService service.UpdateServiceInterface, logger *slog.Logger) *BeerController {
 	return &BeerController{
 		BeerService:       beerService,
 		ValidationService: validationService,
 		UpdateService:     updateService,
+		Logger:            loggerOrDefault(logger).With("controller", "BeerController"),
 	}
 }
 
 func (bc *BeerController) ListAllBeerStyles(c *gin.Context) {
-	beerStyles, err := bc.BeerService.ListAllBeerStyles()
+	beerStyles, err := bc.BeerService.ListAllBeerStyles(c.Request.Context())
 	if err != nil {
-		log.Printf("controller=BeerController func=ListAllBeerStyles err=%v", err)
+		bc.Logger.ErrorContext(c.Request.Context(), "ListAllBeerStyles failed", "err", err)
 
 		status := http.StatusInternalServerError
 		message := "internal error"
@@ -38,50 +43,117 @@ func (bc *BeerController) ListAllBeerStyles(c *gin.Context) {
 			message = "no beer styles found"
 		}
 
-		c.AbortWithStatusJSON(status, gin.H{
+		respondError(c, status, gin.H{
 			"message": message,
 		})
 		return
 	}
 
+	body := beerStylesResponse{BeerStyles: beerStyles}
+	format, ok := negotiate(c, body)
+	if !ok {
+		return
+	}
+	if headers.NotModified(c, beerStylesValidators(beerStyles, format)) {
+		return
+	}
+
+	serializer.Write(c, http.StatusOK, format, body)
+}
+
+func (bc *BeerController) GetBeerStyle(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if beerUUID == "" {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": "beerUUID is required",
+		})
+		return
+	}
+
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	beerStyle, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
+	if err != nil {
+		bc.Logger.ErrorContext(c.Request.Context(), "GetBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		status := http.StatusInternalServerError
+		message := "internal error"
+
+		if bc.ValidationService.IsNoRowsError(err) {
+			status = http.StatusNotFound
+			message = "beer style not found"
+		}
+
+		respondError(c, status, gin.H{
+			"message": message,
+		})
+		return
+	}
+
+	// A single style can carry Last-Modified: deleting it answers 404.
+	validators := beerStylesValidators([]domain.BeerStyle{beerStyle}, serializer.JSON)
+	validators.LastModified = beerStyle.UpdatedAt
+	if headers.NotModified(c, validators) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"beerStyles": beerStyles,
+		"data": beerStyle,
 	})
 }
 
+// beerStylesValidators derive the ETag from the UUID and update time of each
+// style, which every write changes, so conditional requests are answered
+// without serializing the styles. The format is part of the tag since each
+// representation has its own bytes. There is no Last-Modified: the newest
+// update time of a list does not change when a style is deleted, so
+// If-Modified-Since would answer 304 with the deleted style.
+func beerStylesValidators(beerStyles []domain.BeerStyle, format string) headers.Validators {
+	parts := make([]string, 0, 2*len(beerStyles)+2)
+	parts = append(parts, format, strconv.Itoa(len(beerStyles)))
+	for _, beerStyle := range beerStyles {
+		parts = append(parts, beerStyle.UUID, strconv.FormatInt(beerStyle.UpdatedAt.UnixNano(), 10))
+	}
+	return headers.Validators{ETag: headers.StrongETag(parts...)}
+}
+
 func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 	var rawData map[string]interface{}
 	body, err := c.GetRawData()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "cannot read request body",
 		})
 		return
 	}
 
 	if err := json.Unmarshal(body, &rawData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "invalid JSON format",
 		})
 		return
 	}
 
 	if _, exists := rawData["name"]; !exists {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "name is required",
 		})
 		return
 	}
 
 	if _, exists := rawData["temp_min"]; !exists {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "temp_min is required",
 		})
 		return
 	}
 
 	if _, exists := rawData["temp_max"]; !exists {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "temp_max is required",
 		})
 		return
@@ -89,41 +161,49 @@ func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 
 	var inputStyle domain.BeerStyle
 	if err := json.Unmarshal(body, &inputStyle); err != nil {
-		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		bc.Logger.WarnContext(c.Request.Context(), "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "invalid field types",
 		})
 		return
 	}
 
-	if err := bc.ValidationService.ValidateUniqueNameForCreate(inputStyle.Name); err != nil {
-		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
+	if err := bc.ValidationService.ValidateUniqueNameForCreate(c.Request.Context(), inputStyle.Name); err != nil {
+		bc.Logger.WarnContext(c.Request.Context(), "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
 
 		if strings.Contains(err.Error(), "already exists") {
-			c.JSON(http.StatusConflict, gin.H{
+			respondError(c, http.StatusConflict, gin.H{
 				"message": err.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
+		respondError(c, http.StatusInternalServerError, gin.H{
 			"message": "failed to validate beer style name",
 		})
 		return
 	}
 
 	if err := bc.ValidationService.ValidateTemperatureRange(inputStyle); err != nil {
-		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		bc.Logger.WarnContext(c.Request.Context(), "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := bc.ValidationService.ValidateABV(inputStyle.ABV); err != nil {
+		bc.Logger.WarnContext(c.Request.Context(), "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
 		return
 	}
 
-	newBeerStyle, err := bc.BeerService.CreateBeerStyle(inputStyle)
+	newBeerStyle, err := bc.BeerService.CreateBeerStyle(c.Request.Context(), inputStyle)
 	if err != nil {
-		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		bc.Logger.ErrorContext(c.Request.Context(), "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
 			"message": "failed to create beer style",
 		})
 		return
@@ -137,7 +217,7 @@ func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 	beerUUID := c.Param("beerUUID")
 	if beerUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "beerUUID is required",
 		})
 		return
@@ -145,16 +225,16 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 
 	var updateRequest domain.BeerStyleUpdateRequest
 	if err := c.ShouldBindJSON(&updateRequest); err != nil {
-		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		bc.Logger.WarnContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "invalid request body",
 		})
 		return
 	}
 
-	currentBeerStyle, err := bc.BeerService.GetBeerStyleByUUID(beerUUID)
+	currentBeerStyle, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
 	if err != nil {
-		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		bc.Logger.ErrorContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
 		status := http.StatusInternalServerError
 		message := "internal error"
 
@@ -163,24 +243,24 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 			message = "beer style not found"
 		}
 
-		c.AbortWithStatusJSON(status, gin.H{
+		respondError(c, status, gin.H{
 			"message": message,
 		})
 		return
 	}
 
 	if updateRequest.Name != nil && *updateRequest.Name != "" && *updateRequest.Name != currentBeerStyle.Name {
-		if err := bc.ValidationService.ValidateUniqueNameForUpdate(*updateRequest.Name, currentBeerStyle.UUID); err != nil {
-			log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		if err := bc.ValidationService.ValidateUniqueNameForUpdate(c.Request.Context(), *updateRequest.Name, currentBeerStyle.UUID); err != nil {
+			bc.Logger.WarnContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
 
 			if strings.Contains(err.Error(), "already exists") {
-				c.JSON(http.StatusConflict, gin.H{
+				respondError(c, http.StatusConflict, gin.H{
 					"message": err.Error(),
 				})
 				return
 			}
 
-			c.JSON(http.StatusInternalServerError, gin.H{
+			respondError(c, http.StatusInternalServerError, gin.H{
 				"message": "failed to validate beer style name",
 			})
 			return
@@ -191,8 +271,16 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 
 	if changed {
 		if err := bc.ValidationService.ValidateTemperatureRange(currentBeerStyle); err != nil {
-			log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-			c.JSON(http.StatusBadRequest, gin.H{
+			bc.Logger.WarnContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+			respondError(c, http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if err := bc.ValidationService.ValidateABV(currentBeerStyle.ABV); err != nil {
+			bc.Logger.WarnContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+			respondError(c, http.StatusBadRequest, gin.H{
 				"message": err.Error(),
 			})
 			return
@@ -207,10 +295,10 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 		return
 	}
 
-	updatedBeerStyle, err := bc.BeerService.UpdateBeerStyle(currentBeerStyle)
+	updatedBeerStyle, err := bc.BeerService.UpdateBeerStyle(c.Request.Context(), currentBeerStyle)
 	if err != nil {
-		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		bc.Logger.ErrorContext(c.Request.Context(), "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
 			"message": "failed to update beer style",
 		})
 		return
@@ -225,15 +313,15 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 func (bc *BeerController) DeleteBeerStyle(c *gin.Context) {
 	beerUUID := c.Param("beerUUID")
 	if beerUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "beerUUID is required",
 		})
 		return
 	}
 
-	_, err := bc.BeerService.GetBeerStyleByUUID(beerUUID)
+	_, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
 	if err != nil {
-		log.Printf("controller=BeerController func=DeleteBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		bc.Logger.ErrorContext(c.Request.Context(), "DeleteBeerStyle failed", "beerUUID", beerUUID, "err", err)
 		status := http.StatusInternalServerError
 		message := "internal error"
 
@@ -242,19 +330,19 @@ func (bc *BeerController) DeleteBeerStyle(c *gin.Context) {
 			message = "beer style not found"
 		}
 
-		c.AbortWithStatusJSON(status, gin.H{
+		respondError(c, status, gin.H{
 			"message": message,
 		})
 		return
 	}
 
-	err = bc.BeerService.DeleteBeerStyle(beerUUID)
+	err = bc.BeerService.DeleteBeerStyle(c.Request.Context(), beerUUID)
 	if err != nil {
-		log.Printf("controller=BeerController func=DeleteBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		bc.Logger.ErrorContext(c.Request.Context(), "DeleteBeerStyle failed", "beerUUID", beerUUID, "err", err)
 		status := http.StatusInternalServerError
 		message := "internal error"
 
-		c.AbortWithStatusJSON(status, gin.H{
+		respondError(c, status, gin.H{
 			"message": message,
 		})
 		return
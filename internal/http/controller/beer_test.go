@@ -2,7 +2,9 @@ package controller
 
 import (
 	"backend-test/internal/domain"
+	"backend-test/internal/logging"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -18,14 +20,14 @@ type mockBeerService struct {
 	errorMsg    string
 }
 
-func (m *mockBeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
+func (m *mockBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
 	if m.shouldError {
 		return nil, &testError{message: m.errorMsg}
 	}
 	return m.beers, nil
 }
 
-func (m *mockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
+func (m *mockBeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
@@ -37,7 +39,7 @@ func (m *mockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle,
 	return domain.BeerStyle{}, &testError{message: "not found"}
 }
 
-func (m *mockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *mockBeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
@@ -47,23 +49,32 @@ func (m *mockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Be
 	return beerStyle, nil
 }
 
-func (m *mockBeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *mockBeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
 	return beerStyle, nil
 }
 
-func (m *mockBeerService) DeleteBeerStyle(beerUUID string) error {
+func (m *mockBeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
 	if m.shouldError {
 		return &testError{message: m.errorMsg}
 	}
+	for i, beer := range m.beers {
+		if beer.UUID == beerUUID {
+			m.beers = append(m.beers[:i], m.beers[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
 type mockValidationService struct {
-	shouldError bool
-	errorMsg    string
+	shouldError      bool
+	errorMsg         string
+	preferencesError string
+	// invalidUUID is rejected by ValidateUUID.
+	invalidUUID string
 }
 
 func (m *mockValidationService) ValidateTemperatureRange(beerStyle domain.BeerStyle) error {
@@ -80,14 +91,46 @@ func (m *mockValidationService) ValidateTemperatureInput(temperature float64) er
 	return nil
 }
 
-func (m *mockValidationService) ValidateUniqueNameForCreate(name string) error {
+func (m *mockValidationService) ValidateABV(abv *float64) error {
+	return nil
+}
+
+func (m *mockValidationService) ValidateRecommendationPreferences(request domain.TemperatureRequest) error {
+	if m.preferencesError != "" {
+		return &testError{message: m.preferencesError}
+	}
+	return nil
+}
+
+func (m *mockValidationService) ValidatePlaylistMapping(request domain.BeerStylePlaylistRequest) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *mockValidationService) ValidateAPIKeyRequest(request domain.APIKeyRequest) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *mockValidationService) ValidateFeedbackRequest(request domain.FeedbackRequest) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *mockValidationService) ValidateUniqueNameForCreate(ctx context.Context, name string) error {
 	if m.shouldError {
 		return &testError{message: m.errorMsg}
 	}
 	return nil
 }
 
-func (m *mockValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
+func (m *mockValidationService) ValidateUniqueNameForUpdate(ctx context.Context, name string, excludeUUID string) error {
 	return nil
 }
 
@@ -96,6 +139,9 @@ func (m *mockValidationService) IsNoRowsError(err error) bool {
 }
 
 func (m *mockValidationService) ValidateUUID(uuidStr string) error {
+	if m.invalidUUID != "" && uuidStr == m.invalidUUID {
+		return &testError{message: "invalid UUID format"}
+	}
 	return nil
 }
 
@@ -118,7 +164,7 @@ func setupController() *BeerController {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	return NewBeerController(beerService, validationService, updateService)
+	return NewBeerController(beerService, validationService, updateService, logging.Discard())
 }
 
 func TestBeerController_NewBeerController(t *testing.T) {
@@ -153,10 +199,11 @@ func TestBeerController_ListAllBeerStyles_Success(t *testing.T) {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
 
 	controller.ListAllBeerStyles(c)
 
@@ -178,6 +225,169 @@ func TestBeerController_ListAllBeerStyles_Success(t *testing.T) {
 	}
 }
 
+func TestBeerController_ListAllBeerStyles_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	beerService := &mockBeerService{beers: []domain.BeerStyle{
+		{UUID: "1", Name: "IPA", UpdatedAt: updatedAt},
+		{UUID: "2", Name: "Lager", UpdatedAt: updatedAt.Add(-time.Hour)},
+	}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{}, logging.Discard())
+
+	list := func(header, value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/", nil)
+		if header != "" {
+			c.Request.Header.Set(header, value)
+		}
+		controller.ListAllBeerStyles(c)
+		return w
+	}
+
+	w := list("", "")
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") != "" {
+		t.Fatalf("Expected an ETag and no Last-Modified, got %v", w.Header())
+	}
+
+	if w := list("If-None-Match", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 without body, got %d %s", w.Code, w.Body.String())
+	}
+
+	beerService.beers[1].UpdatedAt = updatedAt.Add(-time.Minute)
+	if w := list("If-None-Match", etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected a new ETag once a style changes, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+
+	beerService.beers = beerService.beers[:1]
+	if w := list("If-None-Match", etag); w.Code != http.StatusOK {
+		t.Errorf("Expected a new ETag once a style is deleted, got %d", w.Code)
+	}
+}
+
+func TestBeerController_ListAllBeerStyles_ConditionalAfterDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	beerService := &mockBeerService{beers: []domain.BeerStyle{
+		{UUID: "1", Name: "IPA", UpdatedAt: updatedAt},
+		{UUID: "2", Name: "Lager", UpdatedAt: updatedAt.Add(-time.Hour)},
+	}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "2"}}
+	controller.DeleteBeerStyle(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the style to be deleted, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("If-Modified-Since", updatedAt.Format(http.TimeFormat))
+	controller.ListAllBeerStyles(c)
+
+	var response struct {
+		BeerStyles []domain.BeerStyle `json:"beerStyles"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected the list after a delete, got %d: %v", w.Code, err)
+	}
+	if w.Code != http.StatusOK || len(response.BeerStyles) != 1 {
+		t.Errorf("Expected the list without the deleted style, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestBeerController_GetBeerStyle_InvalidUUID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewBeerController(&mockBeerService{}, &mockValidationService{invalidUUID: "not-a-uuid"}, &mockUpdateService{}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "not-a-uuid"}}
+	controller.GetBeerStyle(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBeerController_ListAllBeerStyles_Negotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	abv := 6.5
+	beerService := &mockBeerService{beers: []domain.BeerStyle{
+		{UUID: "1", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale", ABV: &abv, Aliases: []string{"India Pale Ale", "American IPA"}},
+	}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{}, logging.Discard())
+
+	list := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Accept", accept)
+		controller.ListAllBeerStyles(c)
+		return w
+	}
+
+	w := list("text/csv")
+	expected := "uuid,name,temp_min,temp_max,category,abv,aliases,created_at,updated_at\n" +
+		"1,IPA,7,10,Ale,6.5,India Pale Ale|American IPA,0001-01-01T00:00:00Z,0001-01-01T00:00:00Z\n"
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("Expected the styles as CSV, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("ETag") == list("application/json").Header().Get("ETag") {
+		t.Error("Expected each format to have its own ETag")
+	}
+
+	if w := list("application/xml"); w.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected status %d, got %d", http.StatusNotAcceptable, w.Code)
+	}
+}
+
+func TestBeerController_GetBeerStyle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{beers: []domain.BeerStyle{
+		{UUID: "test-uuid-1", Name: "Test IPA", UpdatedAt: time.Now()},
+	}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{}, logging.Discard())
+
+	get := func(beerUUID, ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/", nil)
+		if ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		c.Params = []gin.Param{{Key: "beerUUID", Value: beerUUID}}
+		controller.GetBeerStyle(c)
+		return w
+	}
+
+	w := get("test-uuid-1", "")
+	var response struct {
+		Data domain.BeerStyle `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if w.Code != http.StatusOK || response.Data.Name != "Test IPA" {
+		t.Errorf("Expected the style, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := get("test-uuid-1", w.Header().Get("ETag")); w.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+}
+
 func TestBeerController_ListAllBeerStyles_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -185,10 +395,11 @@ func TestBeerController_ListAllBeerStyles_ServiceError(t *testing.T) {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
 
 	controller.ListAllBeerStyles(c)
 
@@ -197,6 +408,29 @@ func TestBeerController_ListAllBeerStyles_ServiceError(t *testing.T) {
 	}
 }
 
+func TestBeerController_ErrorResponseIncludesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{shouldError: true, errorMsg: "database error"}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), "req-123"))
+
+	controller.ListAllBeerStyles(c)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response["request_id"] != "req-123" {
+		t.Errorf("Expected request_id req-123, got %v", response["request_id"])
+	}
+}
+
 func TestBeerController_CreateBeerStyle_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -204,7 +438,7 @@ func TestBeerController_CreateBeerStyle_Success(t *testing.T) {
 	validationService := &mockValidationService{shouldError: false}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"name":     "Test Beer",
@@ -243,7 +477,7 @@ func TestBeerController_UpdateBeerStyle_Success(t *testing.T) {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"name": "Updated Beer",
@@ -281,10 +515,11 @@ func TestBeerController_DeleteBeerStyle_Success(t *testing.T) {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
 	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
 
 	controller.DeleteBeerStyle(c)
@@ -312,10 +547,11 @@ func TestBeerController_DeleteBeerStyle_ServiceError(t *testing.T) {
 	validationService := &mockValidationService{}
 	updateService := &mockUpdateService{}
 
-	controller := NewBeerController(beerService, validationService, updateService)
+	controller := NewBeerController(beerService, validationService, updateService, logging.Discard())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
 	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
 
 	controller.DeleteBeerStyle(c)
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedbackController struct {
+	FeedbackService   service.FeedbackServiceInterface
+	ValidationService service.ValidationServiceInterface
+	Logger            *slog.Logger
+}
+
+func NewFeedbackController(feedbackService service.FeedbackServiceInterface, validationService service.ValidationServiceInterface, logger *slog.Logger) *FeedbackController {
+	return &FeedbackController{
+		FeedbackService:   feedbackService,
+		ValidationService: validationService,
+		Logger:            loggerOrDefault(logger).With("controller", "FeedbackController"),
+	}
+}
+
+// SubmitFeedback rates the recommendation :id, the ID returned with it. A
+// new vote replaces the previous one. Only the client the recommendation was
+// served to may rate it. A recommendation the history has not stored in
+// time gets 409 with Retry-After.
+func (fc *FeedbackController) SubmitFeedback(c *gin.Context) {
+	id := c.Param("id")
+	if err := fc.ValidationService.ValidateUUID(id); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var request domain.FeedbackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	if err := fc.ValidationService.ValidateFeedbackRequest(request); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	feedback, err := fc.FeedbackService.SubmitFeedback(c.Request.Context(), id, request)
+	if err != nil {
+		switch {
+		case fc.ValidationService.IsNoRowsError(err):
+			respondError(c, http.StatusNotFound, gin.H{
+				"message": "recommendation not found",
+			})
+		case errors.Is(err, service.ErrForeignRecommendation):
+			respondError(c, http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, service.ErrRecommendationPending):
+			fc.Logger.WarnContext(c.Request.Context(), "SubmitFeedback failed", "id", id, "err", err)
+			c.Header("Retry-After", "1")
+			respondError(c, http.StatusConflict, gin.H{
+				"message": "recommendation not stored yet, retry shortly",
+			})
+		case errors.Is(err, service.ErrUnknownBeerStyle):
+			fc.Logger.WarnContext(c.Request.Context(), "SubmitFeedback failed", "id", id, "err", err)
+			respondError(c, http.StatusUnprocessableEntity, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			fc.Logger.ErrorContext(c.Request.Context(), "SubmitFeedback failed", "id", id, "err", err)
+			respondError(c, http.StatusInternalServerError, gin.H{
+				"message": "failed to save feedback",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Feedback recorded",
+		"data":    feedback,
+	})
+}
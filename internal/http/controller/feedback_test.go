@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/service"
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ratedRecommendationID   = "4f0e8a52-0000-4000-8000-000000000001"
+	pendingRecommendationID = "4f0e8a52-0000-4000-8000-000000000002"
+	foreignRecommendationID = "4f0e8a52-0000-4000-8000-000000000003"
+)
+
+type mockFeedbackService struct{}
+
+func (m *mockFeedbackService) SubmitFeedback(ctx context.Context, id string, request domain.FeedbackRequest) (domain.RecommendationFeedback, error) {
+	switch {
+	case id == foreignRecommendationID:
+		return domain.RecommendationFeedback{}, service.ErrForeignRecommendation
+	case id == pendingRecommendationID:
+		return domain.RecommendationFeedback{}, service.ErrRecommendationPending
+	case id != ratedRecommendationID:
+		return domain.RecommendationFeedback{}, fmt.Errorf("failed to get recommendation: %w", sql.ErrNoRows)
+	case request.PreferredStyle == "Mead":
+		return domain.RecommendationFeedback{}, fmt.Errorf("%w: 'Mead'", service.ErrUnknownBeerStyle)
+	}
+	return domain.RecommendationFeedback{RecommendationID: id, Vote: request.Vote, PreferredStyle: request.PreferredStyle}, nil
+}
+
+func TestFeedbackController_SubmitFeedback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewFeedbackController(&mockFeedbackService{}, service.NewValidationService(nil), logging.Discard())
+
+	tests := []struct {
+		id       string
+		body     string
+		expected int
+	}{
+		{ratedRecommendationID, `{"vote": "up"}`, http.StatusOK},
+		{ratedRecommendationID, `{"vote": "down", "preferred_style": "Stout"}`, http.StatusOK},
+		{ratedRecommendationID, `{"vote": "meh"}`, http.StatusBadRequest},
+		{ratedRecommendationID, `{"vote": 1}`, http.StatusBadRequest},
+		{"not-a-uuid", `{"vote": "up"}`, http.StatusBadRequest},
+		{"4f0e8a52-0000-4000-8000-00000000ffff", `{"vote": "up"}`, http.StatusNotFound},
+		{ratedRecommendationID, `{"vote": "down", "preferred_style": "Mead"}`, http.StatusUnprocessableEntity},
+		{pendingRecommendationID, `{"vote": "up"}`, http.StatusConflict},
+		{foreignRecommendationID, `{"vote": "up"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: tt.id}}
+		c.Request, _ = http.NewRequest("POST", "/api/recommendations/"+tt.id+"/feedback", bytes.NewBufferString(tt.body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		controller.SubmitFeedback(c)
+
+		if w.Code != tt.expected {
+			t.Errorf("%s %s: expected status %d, got %d %s", tt.id, tt.body, tt.expected, w.Code, w.Body.String())
+		}
+	}
+}
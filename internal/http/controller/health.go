@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessState reports whether the instance accepts new traffic; it turns
+// false once graceful shutdown starts.
+type ReadinessState interface {
+	IsReady() bool
+}
+
+type HealthController struct {
+	HealthService  service.HealthServiceInterface
+	ReadinessState ReadinessState
+	Logger         *slog.Logger
+}
+
+func NewHealthController(healthService service.HealthServiceInterface, readiness ReadinessState, logger *slog.Logger) *HealthController {
+	return &HealthController{
+		HealthService:  healthService,
+		ReadinessState: readiness,
+		Logger:         loggerOrDefault(logger).With("controller", "HealthController"),
+	}
+}
+
+// Liveness only reports that the process is serving requests. Dependencies
+// are left to Readiness so an outage does not get the instance restarted.
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": domain.HealthStatusUp,
+	})
+}
+
+// Check keeps the original /api/check response, which only reports that the
+// process is serving requests, for the clients built on it.
+func (hc *HealthController) Check(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "OK.",
+	})
+}
+
+// Readiness pings the dependencies and answers 503 when a critical one is
+// down or the instance is shutting down. A degraded instance stays ready.
+func (hc *HealthController) Readiness(c *gin.Context) {
+	if !hc.ReadinessState.IsReady() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  domain.HealthStatusDown,
+			"message": "shutting down",
+		})
+		return
+	}
+
+	report := hc.HealthService.Check(c.Request.Context())
+	if report.Status == domain.HealthStatusDown {
+		hc.Logger.WarnContext(c.Request.Context(), "Readiness failed", "status", report.Status, "checks", report.Checks)
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
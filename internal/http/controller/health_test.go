@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockHealthService struct {
+	report domain.HealthReport
+}
+
+func (m *mockHealthService) Check(ctx context.Context) domain.HealthReport {
+	return m.report
+}
+
+type mockReadiness struct {
+	ready bool
+}
+
+func (m *mockReadiness) IsReady() bool {
+	return m.ready
+}
+
+func performReadiness(controller *HealthController) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/readyz", nil)
+
+	controller.Readiness(c)
+	return w
+}
+
+func TestHealthController_Liveness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewHealthController(&mockHealthService{}, &mockReadiness{ready: false}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	controller.Liveness(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHealthController_Check(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewHealthController(&mockHealthService{}, &mockReadiness{ready: false}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	controller.Check(c)
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"message":"OK."}` {
+		t.Errorf("Expected the original OK. response, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHealthController_Readiness_Degraded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthService := &mockHealthService{report: domain.HealthReport{
+		Status: domain.HealthStatusDegraded,
+		Checks: []domain.HealthCheckResult{
+			{Name: "postgres", Status: domain.HealthStatusUp, Critical: true},
+			{Name: "redis", Status: domain.HealthStatusDown, Error: "connection refused"},
+		},
+	}}
+	controller := NewHealthController(healthService, &mockReadiness{ready: true}, logging.Discard())
+
+	w := performReadiness(controller)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response domain.HealthReport
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Status != domain.HealthStatusDegraded || len(response.Checks) != 2 {
+		t.Errorf("Expected degraded report with 2 checks, got %+v", response)
+	}
+}
+
+func TestHealthController_Readiness_Down(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthService := &mockHealthService{report: domain.HealthReport{Status: domain.HealthStatusDown}}
+	controller := NewHealthController(healthService, &mockReadiness{ready: true}, logging.Discard())
+
+	w := performReadiness(controller)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestHealthController_Readiness_ShuttingDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthService := &mockHealthService{report: domain.HealthReport{Status: domain.HealthStatusUp}}
+	controller := NewHealthController(healthService, &mockReadiness{ready: false}, logging.Discard())
+
+	w := performReadiness(controller)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
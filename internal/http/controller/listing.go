@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The listing bodies below are written as JSON, MessagePack or CSV by the
+// serializer; the json tags name the fields in the first two.
+
+type beerStylesResponse struct {
+	BeerStyles []domain.BeerStyle `json:"beerStyles"`
+}
+
+func (r beerStylesResponse) CSVHeader() []string {
+	return []string{"uuid", "name", "temp_min", "temp_max", "category", "abv", "aliases", "created_at", "updated_at"}
+}
+
+// CSVRows joins the aliases with "|" so each style stays a single row.
+func (r beerStylesResponse) CSVRows() [][]string {
+	rows := make([][]string, 0, len(r.BeerStyles))
+	for _, style := range r.BeerStyles {
+		abv := ""
+		if style.ABV != nil {
+			abv = formatFloat(*style.ABV)
+		}
+		rows = append(rows, []string{
+			style.UUID, style.Name, formatFloat(style.TempMin), formatFloat(style.TempMax), style.Category,
+			abv, strings.Join(style.Aliases, "|"), formatTime(style.CreatedAt), formatTime(style.UpdatedAt),
+		})
+	}
+	return rows
+}
+
+type playlistsResponse struct {
+	Playlists []domain.BeerStylePlaylist `json:"playlists"`
+}
+
+func (r playlistsResponse) CSVHeader() []string {
+	return []string{"uuid", "beer_style_uuid", "provider", "playlist_id", "weight", "created_at", "updated_at"}
+}
+
+func (r playlistsResponse) CSVRows() [][]string {
+	rows := make([][]string, 0, len(r.Playlists))
+	for _, playlist := range r.Playlists {
+		rows = append(rows, []string{
+			playlist.UUID, playlist.BeerStyleUUID, playlist.Provider, playlist.PlaylistID,
+			strconv.Itoa(playlist.Weight), formatTime(playlist.CreatedAt), formatTime(playlist.UpdatedAt),
+		})
+	}
+	return rows
+}
+
+type apiKeysResponse struct {
+	APIKeys []domain.APIKey `json:"apiKeys"`
+}
+
+func (r apiKeysResponse) CSVHeader() []string {
+	return []string{"uuid", "name", "prefix", "role", "created_at", "revoked_at"}
+}
+
+func (r apiKeysResponse) CSVRows() [][]string {
+	rows := make([][]string, 0, len(r.APIKeys))
+	for _, key := range r.APIKeys {
+		revokedAt := ""
+		if key.RevokedAt != nil {
+			revokedAt = formatTime(*key.RevokedAt)
+		}
+		rows = append(rows, []string{key.UUID, key.Name, key.Prefix, key.Role, formatTime(key.CreatedAt), revokedAt})
+	}
+	return rows
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistMappingController struct {
+	PlaylistMappingService service.PlaylistMappingServiceInterface
+	BeerService            service.BeerServiceInterface
+	ValidationService      service.ValidationServiceInterface
+	Logger                 *slog.Logger
+}
+
+func NewPlaylistMappingController(playlistMappingService service.PlaylistMappingServiceInterface, beerService service.BeerServiceInterface, validationService service.ValidationServiceInterface, logger *slog.Logger) *PlaylistMappingController {
+	return &PlaylistMappingController{
+		PlaylistMappingService: playlistMappingService,
+		BeerService:            beerService,
+		ValidationService:      validationService,
+		Logger:                 loggerOrDefault(logger).With("controller", "PlaylistMappingController"),
+	}
+}
+
+func (pc *PlaylistMappingController) ListBeerStylePlaylists(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if !pc.ensureBeerStyleExists(c, beerUUID, "ListBeerStylePlaylists") {
+		return
+	}
+
+	playlists, err := pc.PlaylistMappingService.ListPlaylistsForBeerStyle(c.Request.Context(), beerUUID)
+	if err != nil {
+		pc.Logger.ErrorContext(c.Request.Context(), "ListBeerStylePlaylists failed", "beerUUID", beerUUID, "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "internal error",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, playlistsResponse{Playlists: playlists})
+}
+
+func (pc *PlaylistMappingController) PinBeerStylePlaylist(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+
+	var request domain.BeerStylePlaylistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pc.Logger.WarnContext(c.Request.Context(), "PinBeerStylePlaylist failed", "beerUUID", beerUUID, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	if err := pc.ValidationService.ValidatePlaylistMapping(request); err != nil {
+		pc.Logger.WarnContext(c.Request.Context(), "PinBeerStylePlaylist failed", "beerUUID", beerUUID, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if !pc.ensureBeerStyleExists(c, beerUUID, "PinBeerStylePlaylist") {
+		return
+	}
+
+	pinned, err := pc.PlaylistMappingService.PinPlaylist(c.Request.Context(), beerUUID, request)
+	if err != nil {
+		pc.Logger.WarnContext(c.Request.Context(), "PinBeerStylePlaylist failed", "beerUUID", beerUUID, "playlistID", request.PlaylistID, "err", err)
+
+		if strings.Contains(err.Error(), "not found on provider") {
+			respondError(c, http.StatusUnprocessableEntity, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "failed to pin playlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": pinned,
+	})
+}
+
+func (pc *PlaylistMappingController) UnpinBeerStylePlaylist(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	playlistUUID := c.Param("playlistUUID")
+
+	for _, id := range []string{beerUUID, playlistUUID} {
+		if err := pc.ValidationService.ValidateUUID(id); err != nil {
+			respondError(c, http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	err := pc.PlaylistMappingService.UnpinPlaylist(c.Request.Context(), beerUUID, playlistUUID)
+	if err != nil {
+		pc.Logger.ErrorContext(c.Request.Context(), "UnpinBeerStylePlaylist failed", "beerUUID", beerUUID, "playlistUUID", playlistUUID, "err", err)
+		status := http.StatusInternalServerError
+		message := "internal error"
+
+		if pc.ValidationService.IsNoRowsError(err) {
+			status = http.StatusNotFound
+			message = "pinned playlist not found"
+		}
+
+		respondError(c, status, gin.H{
+			"message": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Playlist unpinned successfully",
+	})
+}
+
+func (pc *PlaylistMappingController) ensureBeerStyleExists(c *gin.Context, beerUUID string, funcName string) bool {
+	if err := pc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return false
+	}
+
+	if _, err := pc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID); err != nil {
+		pc.Logger.ErrorContext(c.Request.Context(), funcName+" failed", "beerUUID", beerUUID, "err", err)
+		status := http.StatusInternalServerError
+		message := "internal error"
+
+		if pc.ValidationService.IsNoRowsError(err) {
+			status = http.StatusNotFound
+			message = "beer style not found"
+		}
+
+		respondError(c, status, gin.H{
+			"message": message,
+		})
+		return false
+	}
+
+	return true
+}
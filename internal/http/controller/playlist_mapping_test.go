@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockPlaylistMappingService struct {
+	playlists   []domain.BeerStylePlaylist
+	shouldError bool
+	errorMsg    string
+}
+
+func (m *mockPlaylistMappingService) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	return m.playlists, nil
+}
+
+func (m *mockPlaylistMappingService) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) (map[string][]domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	byStyle := map[string][]domain.BeerStylePlaylist{}
+	for _, playlist := range m.playlists {
+		byStyle[playlist.BeerStyleUUID] = append(byStyle[playlist.BeerStyleUUID], playlist)
+	}
+	return byStyle, nil
+}
+
+func (m *mockPlaylistMappingService) PinPlaylist(ctx context.Context, beerStyleUUID string, request domain.BeerStylePlaylistRequest) (domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return domain.BeerStylePlaylist{}, &testError{message: m.errorMsg}
+	}
+	return domain.BeerStylePlaylist{
+		UUID:          "pin-uuid",
+		BeerStyleUUID: beerStyleUUID,
+		Provider:      "spotify",
+		PlaylistID:    request.PlaylistID,
+		Weight:        request.Weight,
+	}, nil
+}
+
+func (m *mockPlaylistMappingService) UnpinPlaylist(ctx context.Context, beerStyleUUID string, playlistUUID string) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func setupPlaylistMappingController(playlistService *mockPlaylistMappingService, validationService *mockValidationService) *PlaylistMappingController {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{{UUID: "style-uuid", Name: "Zero Point", TempMin: -1, TempMax: 1}},
+	}
+
+	return NewPlaylistMappingController(playlistService, beerService, validationService, logging.Discard())
+}
+
+func TestPlaylistMappingController_ListBeerStylePlaylists_Success(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{
+		playlists: []domain.BeerStylePlaylist{
+			{UUID: "pin-1", BeerStyleUUID: "style-uuid", Provider: "spotify", PlaylistID: "37i9dQZF1DX0XUsuxWHRQd", Weight: 3},
+		},
+	}, &mockValidationService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "style-uuid"}}
+
+	controller.ListBeerStylePlaylists(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Playlists []domain.BeerStylePlaylist `json:"playlists"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(response.Playlists) != 1 || response.Playlists[0].Weight != 3 {
+		t.Errorf("Expected one pinned playlist with weight 3, got %+v", response.Playlists)
+	}
+}
+
+func TestPlaylistMappingController_ListBeerStylePlaylists_UnknownStyle(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{}, &mockValidationService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "missing-uuid"}}
+
+	controller.ListBeerStylePlaylists(c)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestPlaylistMappingController_PinBeerStylePlaylist_Success(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{}, &mockValidationService{})
+
+	jsonBody, _ := json.Marshal(domain.BeerStylePlaylistRequest{PlaylistID: "37i9dQZF1DX0XUsuxWHRQd", Weight: 2})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBuffer(jsonBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "style-uuid"}}
+
+	controller.PinBeerStylePlaylist(c)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var response struct {
+		Data domain.BeerStylePlaylist `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Data.PlaylistID != "37i9dQZF1DX0XUsuxWHRQd" {
+		t.Errorf("Expected pinned playlist ID, got '%s'", response.Data.PlaylistID)
+	}
+}
+
+func TestPlaylistMappingController_PinBeerStylePlaylist_ValidationError(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{}, &mockValidationService{
+		shouldError: true,
+		errorMsg:    "playlist_id is required",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"weight": 1}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "style-uuid"}}
+
+	controller.PinBeerStylePlaylist(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestPlaylistMappingController_PinBeerStylePlaylist_PlaylistMissingOnProvider(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{
+		shouldError: true,
+		errorMsg:    "playlist 'nope' not found on provider 'spotify'",
+	}, &mockValidationService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"playlist_id": "nope"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "style-uuid"}}
+
+	controller.PinBeerStylePlaylist(c)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestPlaylistMappingController_UnpinBeerStylePlaylist_Success(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{}, &mockValidationService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
+	c.Params = []gin.Param{
+		{Key: "beerUUID", Value: "style-uuid"},
+		{Key: "playlistUUID", Value: "pin-uuid"},
+	}
+
+	controller.UnpinBeerStylePlaylist(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response["message"] != "Playlist unpinned successfully" {
+		t.Errorf("Expected unpin message, got '%s'", response["message"])
+	}
+}
+
+func TestPlaylistMappingController_UnpinBeerStylePlaylist_InvalidBeerUUID(t *testing.T) {
+	controller := setupPlaylistMappingController(&mockPlaylistMappingService{}, &mockValidationService{invalidUUID: "not-a-uuid"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
+	c.Params = []gin.Param{
+		{Key: "beerUUID", Value: "not-a-uuid"},
+		{Key: "playlistUUID", Value: "pin-uuid"},
+	}
+
+	controller.UnpinBeerStylePlaylist(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -3,8 +3,10 @@ package controller
 import (
 	"backend-test/internal/domain"
 	"backend-test/internal/service"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,48 +15,75 @@ import (
 type RecommendationController struct {
 	RecommendationService service.RecommendationServiceInterface
 	ValidationService     service.ValidationServiceInterface
+	Logger                *slog.Logger
 }
 
-func NewRecommendationController(recommendationService service.RecommendationServiceInterface, validationService service.ValidationServiceInterface) *RecommendationController {
+func NewRecommendationController(recommendationService service.RecommendationServiceInterface, validationService service.ValidationServiceInterface, logger *slog.Logger) *RecommendationController {
 	return &RecommendationController{
 		RecommendationService: recommendationService,
 		ValidationService:     validationService,
+		Logger:                loggerOrDefault(logger).With("controller", "RecommendationController"),
 	}
 }
 
-func (rc *RecommendationController) SuggestSpotifyPlaylist(c *gin.Context) {
+// bindTemperatureRequest reads and validates the body of the
+// recommendation routes, responding with the error when it is invalid.
+func (rc *RecommendationController) bindTemperatureRequest(c *gin.Context, operation string) (domain.TemperatureRequest, bool) {
 	var request domain.TemperatureRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist err=%v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		rc.Logger.WarnContext(c.Request.Context(), operation+" failed", "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
 			"message": "invalid request body format",
 		})
-		return
+		return request, false
 	}
 
 	if err := rc.ValidationService.ValidateTemperatureInput(request.Temperature); err != nil {
-		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", request.Temperature, err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		rc.Logger.WarnContext(c.Request.Context(), operation+" failed", "temperature", request.Temperature, "err", err)
+		respondError(c, http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return request, false
+	}
+
+	if err := rc.ValidationService.ValidateRecommendationPreferences(request); err != nil {
+		rc.Logger.WarnContext(c.Request.Context(), operation+" failed", "temperature", request.Temperature, "err", err)
+		respondError(c, http.StatusUnprocessableEntity, gin.H{
 			"message": err.Error(),
 		})
+		return request, false
+	}
+
+	return request, true
+}
+
+func (rc *RecommendationController) SuggestSpotifyPlaylist(c *gin.Context) {
+	request, ok := rc.bindTemperatureRequest(c, "SuggestSpotifyPlaylist")
+	if !ok {
 		return
 	}
 
-	recommendation, err := rc.RecommendationService.GetRecommendationForTemperature(request.Temperature)
+	recommendation, err := rc.RecommendationService.GetRecommendationForTemperature(c.Request.Context(), request)
 	if err != nil {
-		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", request.Temperature, err)
+		rc.Logger.WarnContext(c.Request.Context(), "SuggestSpotifyPlaylist failed", "temperature", request.Temperature, "err", err)
 
 		var status int
 		var message string
 
 		errorMsg := err.Error()
 		switch {
+		case strings.Contains(errorMsg, "no beer style satisfies constraint"):
+			status = http.StatusNotFound
+			message = errorMsg
 		case strings.Contains(errorMsg, "no playlist found"):
 			status = http.StatusNotFound
 			message = errorMsg
 		case strings.Contains(errorMsg, "spotify service unavailable"):
 			status = http.StatusServiceUnavailable
 			message = "Spotify service is temporarily unavailable"
+		case strings.Contains(errorMsg, "service unavailable"):
+			status = http.StatusServiceUnavailable
+			message = "Music provider is temporarily unavailable"
 		case strings.Contains(errorMsg, "failed to find best beer style"):
 			status = http.StatusInternalServerError
 			message = "Unable to determine suitable beer style"
@@ -63,11 +92,51 @@ func (rc *RecommendationController) SuggestSpotifyPlaylist(c *gin.Context) {
 			message = "Internal server error"
 		}
 
-		c.JSON(status, gin.H{
+		response := gin.H{
 			"message": message,
-		})
+		}
+
+		var constraintErr *service.ConstraintError
+		if errors.As(err, &constraintErr) {
+			response["constraint"] = constraintErr.Constraint
+		}
+
+		respondError(c, status, response)
 		return
 	}
 
 	c.JSON(http.StatusOK, recommendation)
 }
+
+// ExplainRecommendation shows how the recommendation for the request body
+// is reached. The music provider is only called with ?resolve_playlist=true.
+func (rc *RecommendationController) ExplainRecommendation(c *gin.Context) {
+	resolvePlaylist := false
+	if value := c.Query("resolve_playlist"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			rc.Logger.WarnContext(c.Request.Context(), "ExplainRecommendation failed", "resolve_playlist", value, "err", err)
+			respondError(c, http.StatusBadRequest, gin.H{
+				"message": "resolve_playlist must be true or false",
+			})
+			return
+		}
+		resolvePlaylist = parsed
+	}
+
+	request, ok := rc.bindTemperatureRequest(c, "ExplainRecommendation")
+	if !ok {
+		return
+	}
+
+	explanation, err := rc.RecommendationService.ExplainRecommendation(c.Request.Context(), request, resolvePlaylist)
+	if err != nil {
+		rc.Logger.ErrorContext(c.Request.Context(), "ExplainRecommendation failed", "temperature", request.Temperature, "err", err)
+		respondError(c, http.StatusInternalServerError, gin.H{
+			"message": "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
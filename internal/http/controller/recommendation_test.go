@@ -2,10 +2,15 @@ package controller
 
 import (
 	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -14,16 +19,32 @@ import (
 type mockRecommendationService struct {
 	shouldError bool
 	errorMsg    string
+	err         error
 	response    domain.RecommendationResponse
+	lastRequest domain.TemperatureRequest
+	lastResolve bool
 }
 
-func (m *mockRecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
+func (m *mockRecommendationService) GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
+	m.lastRequest = request
+	if m.err != nil {
+		return nil, m.err
+	}
 	if m.shouldError {
 		return nil, &testError{message: m.errorMsg}
 	}
 	return &m.response, nil
 }
 
+func (m *mockRecommendationService) ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	m.lastRequest = request
+	m.lastResolve = resolvePlaylist
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.RecommendationExplanation{Temperature: request.Temperature, BeerStyle: m.response.BeerStyle}, nil
+}
+
 func setupRecommendationTestController() *RecommendationController {
 	gin.SetMode(gin.TestMode)
 
@@ -45,7 +66,7 @@ func setupRecommendationTestController() *RecommendationController {
 
 	validationService := &mockValidationService{}
 
-	return NewRecommendationController(recommendationService, validationService)
+	return NewRecommendationController(recommendationService, validationService, logging.Discard())
 }
 
 func TestRecommendationController_NewRecommendationController(t *testing.T) {
@@ -133,7 +154,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_ValidationError(t *test
 		errorMsg:    "temperature out of range",
 	}
 
-	controller := NewRecommendationController(recommendationService, validationService)
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	requestBody := domain.TemperatureRequest{
 		Temperature: 100.0, // Invalid temperature
@@ -172,7 +193,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_NoPlaylistFound(t *test
 	}
 	validationService := &mockValidationService{}
 
-	controller := NewRecommendationController(recommendationService, validationService)
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	requestBody := domain.TemperatureRequest{
 		Temperature: 6.0,
@@ -211,7 +232,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_SpotifyUnavailable(t *t
 	}
 	validationService := &mockValidationService{}
 
-	controller := NewRecommendationController(recommendationService, validationService)
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	requestBody := domain.TemperatureRequest{
 		Temperature: 6.0,
@@ -250,7 +271,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_BeerStyleError(t *testi
 	}
 	validationService := &mockValidationService{}
 
-	controller := NewRecommendationController(recommendationService, validationService)
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	requestBody := domain.TemperatureRequest{
 		Temperature: 6.0,
@@ -289,7 +310,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_GenericError(t *testing
 	}
 	validationService := &mockValidationService{}
 
-	controller := NewRecommendationController(recommendationService, validationService)
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	requestBody := domain.TemperatureRequest{
 		Temperature: 6.0,
@@ -318,3 +339,144 @@ func TestRecommendationController_SuggestSpotifyPlaylist_GenericError(t *testing
 		t.Errorf("Expected message 'Internal server error', got '%s'", response["message"])
 	}
 }
+
+func TestRecommendationController_SuggestSpotifyPlaylist_PassesPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recommendationService := &mockRecommendationService{}
+	controller := NewRecommendationController(recommendationService, &mockValidationService{}, logging.Discard())
+
+	body := `{"temperature": 11, "exclude_styles": ["Stout"], "categories": ["Ale"], "max_abv": 5.5, "min_tracks": 3}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	controller.SuggestSpotifyPlaylist(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	request := recommendationService.lastRequest
+	if len(request.ExcludeStyles) != 1 || request.ExcludeStyles[0] != "Stout" {
+		t.Errorf("Expected exclude_styles [Stout], got %v", request.ExcludeStyles)
+	}
+	if len(request.Categories) != 1 || request.Categories[0] != "Ale" {
+		t.Errorf("Expected categories [Ale], got %v", request.Categories)
+	}
+	if request.MaxABV == nil || *request.MaxABV != 5.5 {
+		t.Errorf("Expected max_abv 5.5, got %v", request.MaxABV)
+	}
+	if request.MinTracks != 3 {
+		t.Errorf("Expected min_tracks 3, got %d", request.MinTracks)
+	}
+}
+
+func TestRecommendationController_SuggestSpotifyPlaylist_InvalidPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recommendationService := &mockRecommendationService{}
+	validationService := &mockValidationService{
+		preferencesError: "min_tracks (-1) must be between 0 and 10",
+	}
+
+	controller := NewRecommendationController(recommendationService, validationService, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"temperature": 6, "min_tracks": -1}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	controller.SuggestSpotifyPlaylist(c)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response["message"] != "min_tracks (-1) must be between 0 and 10" {
+		t.Errorf("Expected preferences validation message, got '%s'", response["message"])
+	}
+}
+
+func TestRecommendationController_SuggestSpotifyPlaylist_ConstraintEliminatedAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recommendationService := &mockRecommendationService{
+		err: &service.ConstraintError{
+			Constraint: "max_abv",
+			Reason:     "no beer style with a known ABV at or below 2.0%",
+		},
+	}
+
+	controller := NewRecommendationController(recommendationService, &mockValidationService{}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"temperature": 6, "max_abv": 2}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	controller.SuggestSpotifyPlaylist(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response["constraint"] != "max_abv" {
+		t.Errorf("Expected constraint 'max_abv', got '%s'", response["constraint"])
+	}
+
+	expected := "no beer style satisfies constraint 'max_abv': no beer style with a known ABV at or below 2.0%"
+	if response["message"] != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, response["message"])
+	}
+}
+
+func TestRecommendationController_ExplainRecommendation(t *testing.T) {
+	controller := setupRecommendationTestController()
+	recommendationService := controller.RecommendationService.(*mockRecommendationService)
+
+	explain := func(target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", target, bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		controller.ExplainRecommendation(c)
+		return w
+	}
+
+	w := explain("/", `{"temperature": -7}`)
+	if w.Code != http.StatusOK || recommendationService.lastResolve {
+		t.Fatalf("Expected an explanation without resolving the playlist, got %d %s", w.Code, w.Body.String())
+	}
+	var explanation domain.RecommendationExplanation
+	if err := json.Unmarshal(w.Body.Bytes(), &explanation); err != nil || explanation.Temperature != -7 || explanation.BeerStyle != "IPA" {
+		t.Errorf("Unexpected explanation %s %v", w.Body.String(), err)
+	}
+
+	if w := explain("/?resolve_playlist=true", `{"temperature": -7}`); w.Code != http.StatusOK || !recommendationService.lastResolve {
+		t.Errorf("Expected the playlist to be resolved, got %d", w.Code)
+	}
+	if w := explain("/?resolve_playlist=maybe", `{"temperature": -7}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid resolve_playlist, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := explain("/", `{"temperature": "cold"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid body, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	recommendationService.err = errors.New("failed to get beer styles: connection refused")
+	if w := explain("/", `{"temperature": -7}`); w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "connection refused") {
+		t.Errorf("Expected an opaque 500, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"backend-test/internal/http/serializer"
+	"backend-test/internal/logging"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError aborts the request with an error body carrying the request ID,
+// so a client report can be matched with the server logs.
+func respondError(c *gin.Context, status int, body gin.H) {
+	if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.AbortWithStatusJSON(status, body)
+}
+
+// negotiate picks the format of body from the Accept header, responding 406
+// when the client accepts none of those body can be written in.
+func negotiate(c *gin.Context, body interface{}) (string, bool) {
+	formats := serializer.Formats(body)
+	c.Writer.Header().Add("Vary", "Accept")
+
+	format, ok := serializer.Negotiate(c.GetHeader("Accept"), formats)
+	if !ok {
+		respondError(c, http.StatusNotAcceptable, gin.H{
+			"message": "supported formats: " + strings.Join(formats, ", "),
+		})
+	}
+	return format, ok
+}
+
+// respond writes body in the format negotiated with the client.
+func respond(c *gin.Context, status int, body interface{}) {
+	if format, ok := negotiate(c, body); ok {
+		serializer.Write(c, status, format, body)
+	}
+}
+
+// loggerOrDefault lets constructors accept a nil logger.
+func loggerOrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
@@ -1,44 +1,146 @@
 package handler
 
 import (
+	"backend-test/internal/auth"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/graph"
 	"backend-test/internal/http/controller"
-	"backend-test/internal/service"
-	"backend-test/internal/storage/repository"
+	"backend-test/internal/http/headers"
+	"backend-test/internal/metrics"
+	"backend-test/internal/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
 
-var beerController *controller.BeerController
-var recommendationController *controller.RecommendationController
+// Controllers are the handlers registered by HandleRequests.
+type Controllers struct {
+	Beer            *controller.BeerController
+	Recommendation  *controller.RecommendationController
+	PlaylistMapping *controller.PlaylistMappingController
+	APIKey          *controller.APIKeyController
+	Analytics       *controller.AnalyticsController
+	Feedback        *controller.FeedbackController
+}
+
+// Access holds the middlewares guarding each group of API routes: the role
+// checks and the rate limits of reads, writes and recommendations.
+type Access struct {
+	Authenticate gin.HandlerFunc
+	Read         gin.HandlerFunc
+	Write        gin.HandlerFunc
+	Recommend    gin.HandlerFunc
+	Feedback     gin.HandlerFunc
+	Admin        gin.HandlerFunc
+
+	ReadLimit      gin.HandlerFunc
+	WriteLimit     gin.HandlerFunc
+	RecommendLimit gin.HandlerFunc
+}
+
+// NewAccess requires the editor role for beer style changes and the admin
+// role for API key management. Reads and recommendations stay public unless
+// the configuration requires the reader role for them; feedback always
+// requires it, so a vote can be tied to the client that was served. limiter is nil when
+// rate limiting is disabled.
+func NewAccess(authenticator *auth.Authenticator, limiter *ratelimit.Limiter, authCfg config.AuthConfig, rateLimitCfg config.RateLimitConfig) Access {
+	access := Access{
+		Authenticate:   authenticator.Middleware(),
+		Read:           auth.Public(),
+		Write:          auth.Require(domain.RoleEditor),
+		Recommend:      auth.Public(),
+		Feedback:       auth.Require(domain.RoleReader),
+		Admin:          auth.Require(domain.RoleAdmin),
+		ReadLimit:      unlimited,
+		WriteLimit:     unlimited,
+		RecommendLimit: unlimited,
+	}
+	if !authCfg.PublicReads {
+		access.Read = auth.Require(domain.RoleReader)
+	}
+	if !authCfg.PublicRecommendations {
+		access.Recommend = auth.Require(domain.RoleReader)
+	}
+	if limiter != nil {
+		access.ReadLimit = limiter.Middleware("read", perMinute(rateLimitCfg.Read))
+		access.WriteLimit = limiter.Middleware("write", perMinute(rateLimitCfg.Write))
+		access.RecommendLimit = limiter.Middleware("recommendation", perMinute(rateLimitCfg.Recommendation))
+	}
+	return access
+}
+
+func perMinute(rule config.RateLimitRule) ratelimit.Limit {
+	return ratelimit.PerMinute(rule.RequestsPerMinute, rule.Burst)
+}
 
-func init() {
-	beerRepo := &repository.BeerRepository{}
-	beerService := service.NewBeerService(beerRepo)
-	validationService := service.NewValidationService(beerService)
-	updateService := service.NewUpdateService()
+func unlimited(c *gin.Context) {
+	c.Next()
+}
+
+// HandleProbes registers the liveness (/healthz) and readiness (/readyz)
+// probes, plus the original /api/check. Readiness pings the dependencies and
+// starts failing as soon as graceful shutdown begins.
+func HandleProbes(router *gin.Engine, healthController *controller.HealthController) {
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/api/check", healthController.Check)
+	router.GET("/readyz", healthController.Readiness)
+}
 
-	recommendationService := service.NewRecommendationService(beerService, nil)
+// HandleMetrics exposes the Prometheus metrics at /metrics.
+func HandleMetrics(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
 
-	beerController = controller.NewBeerController(beerService, validationService, updateService)
-	recommendationController = controller.NewRecommendationController(recommendationService, validationService)
+// Caching holds the Cache-Control of the cacheable reads. Every other route
+// keeps the router's no-store.
+type Caching struct {
+	BeerStyles gin.HandlerFunc
+	BeerStyle  gin.HandlerFunc
+	Playlists  gin.HandlerFunc
 }
 
-func HealthCheckStatus(c *gin.Context) {
-	c.JSON(200, gin.H{
-		"message": "OK.",
-	})
+// NewCaching lets clients reuse beer styles for the configured max age and
+// pinned playlists until they revalidate. Shared caches may keep them only
+// while reads are public.
+func NewCaching(cfg config.HTTPCacheConfig, publicReads bool) Caching {
+	return Caching{
+		BeerStyles: headers.CacheControl(headers.MaxAge(cfg.BeerStylesMaxAge, publicReads)),
+		BeerStyle:  headers.CacheControl(headers.MaxAge(cfg.BeerStyleMaxAge, publicReads)),
+		Playlists:  headers.CacheControl(headers.MaxAge(0, publicReads)),
+	}
 }
 
-func HandleRequests(router *gin.Engine) {
-	api := router.Group("/api")
-	api.GET("/check", HealthCheckStatus)
+// HandleRequests registers the API routes. validate checks request bodies
+// against the OpenAPI document and runs once the caller is authorized.
+func HandleRequests(router *gin.Engine, controllers Controllers, access Access, caching Caching, validate gin.HandlerFunc) {
+	api := router.Group("/api", access.Authenticate)
 
 	beer := api.Group("/beer-styles")
-	beer.GET("/list", beerController.ListAllBeerStyles)
-	beer.POST("/create", beerController.CreateBeerStyle)
-	beer.PUT("/edit/:beerUUID", beerController.UpdateBeerStyle)
-	beer.DELETE("/:beerUUID", beerController.DeleteBeerStyle)
+	beer.GET("/list", access.ReadLimit, access.Read, caching.BeerStyles, controllers.Beer.ListAllBeerStyles)
+	beer.GET("/:beerUUID", access.ReadLimit, access.Read, caching.BeerStyle, controllers.Beer.GetBeerStyle)
+	beer.POST("/create", access.WriteLimit, access.Write, validate, controllers.Beer.CreateBeerStyle)
+	beer.PUT("/edit/:beerUUID", access.WriteLimit, access.Write, validate, controllers.Beer.UpdateBeerStyle)
+	beer.DELETE("/:beerUUID", access.WriteLimit, access.Write, controllers.Beer.DeleteBeerStyle)
+	beer.GET("/:beerUUID/playlists", access.ReadLimit, access.Read, caching.Playlists, controllers.PlaylistMapping.ListBeerStylePlaylists)
+	beer.POST("/:beerUUID/playlists", access.WriteLimit, access.Write, validate, controllers.PlaylistMapping.PinBeerStylePlaylist)
+	beer.DELETE("/:beerUUID/playlists/:playlistUUID", access.WriteLimit, access.Write, controllers.PlaylistMapping.UnpinBeerStylePlaylist)
 
 	recommendations := api.Group("/recommendations")
-	recommendations.POST("/suggest", recommendationController.SuggestSpotifyPlaylist)
+	recommendations.POST("/suggest", access.RecommendLimit, access.Recommend, validate, controllers.Recommendation.SuggestSpotifyPlaylist)
+	recommendations.POST("/explain", access.RecommendLimit, access.Recommend, validate, controllers.Recommendation.ExplainRecommendation)
+	recommendations.POST("/:id/feedback", access.RecommendLimit, access.Feedback, validate, controllers.Feedback.SubmitFeedback)
+
+	analytics := api.Group("/analytics")
+	analytics.GET("/recommendations", access.ReadLimit, access.Admin, controllers.Analytics.GetRecommendationAnalytics)
+
+	apiKeys := api.Group("/auth/api-keys")
+	apiKeys.GET("", access.ReadLimit, access.Admin, controllers.APIKey.ListAPIKeys)
+	apiKeys.POST("", access.WriteLimit, access.Admin, validate, controllers.APIKey.CreateAPIKey)
+	apiKeys.DELETE("/:keyUUID", access.WriteLimit, access.Admin, controllers.APIKey.RevokeAPIKey)
+}
+
+// HandleGraphQL registers POST /graphql. Its root fields enforce the roles
+// and rate limits of the matching REST routes themselves.
+func HandleGraphQL(router *gin.Engine, server *graph.Server, access Access) {
+	router.POST("/graphql", access.Authenticate, server.Handle)
 }
@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"backend-test/internal/auth"
+	"backend-test/internal/domain"
+	"backend-test/internal/http/headers"
+	"backend-test/internal/http/openapi"
+	"backend-test/internal/http/serializer"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Spec documents every route registered by this package. A route without an
+// entry here fails the app tests.
+func Spec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Beer Recommendation API",
+		Version:     "1.0.0",
+		Description: "Beer styles and the playlists recommended for the temperature they are served at.",
+	})
+	doc.Tags = []openapi.Tag{
+		{Name: "beer-styles", Description: "Beer style catalog and pinned playlists"},
+		{Name: "recommendations", Description: "Playlist recommendations by temperature"},
+		{Name: "analytics", Description: "Aggregations of the recommendation history"},
+		{Name: "auth", Description: "API key management"},
+		{Name: "operations", Description: "Probes, metrics and documentation"},
+	}
+	doc.Components.SecuritySchemes["apiKey"] = openapi.SecurityScheme{Type: "apiKey", In: "header", Name: auth.APIKeyHeader}
+	doc.Components.SecuritySchemes["bearer"] = openapi.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "A JWT signed by a key of the JWKS, or an API key"}
+	doc.Components.Schemas["Error"] = openapi.Schema{
+		"type": "object",
+		"properties": openapi.Schema{
+			"message":    openapi.String(""),
+			"request_id": openapi.String(""),
+			"errors":     openapi.ArrayOf(openapi.String("")),
+			"constraint": openapi.String(""),
+		},
+		"required": []string{"message"},
+	}
+	doc.Components.Schemas["Problem"] = openapi.Schema{
+		"type": "object",
+		"properties": openapi.Schema{
+			"type":       openapi.String(""),
+			"title":      openapi.String(""),
+			"status":     openapi.Schema{"type": "integer"},
+			"detail":     openapi.String(""),
+			"instance":   openapi.String(""),
+			"request_id": openapi.String(""),
+		},
+	}
+
+	var (
+		errorReply = func(description string) openapi.Response {
+			return openapi.Reply(description, openapi.Ref("Error"), "application/json")
+		}
+		message = openapi.Object(map[string]openapi.Schema{"message": openapi.String("")})
+		// optional lists the anonymous access first: reads and
+		// recommendations are public unless configured otherwise.
+		optional = []map[string][]string{{}, {"apiKey": {}}, {"bearer": {}}}
+		required = []map[string][]string{{"apiKey": {}}, {"bearer": {}}}
+		listing  = []string{serializer.JSON, serializer.MsgPack, serializer.CSV}
+	)
+	guarded := func(responses map[int]openapi.Response, public bool) map[string]openapi.Response {
+		responses[http.StatusUnauthorized] = errorReply("Missing or invalid credentials")
+		responses[http.StatusTooManyRequests] = openapi.Reply("Rate limit exceeded", openapi.Ref("Problem"), "application/problem+json")
+		responses[http.StatusInternalServerError] = errorReply("Internal error")
+		if !public {
+			responses[http.StatusForbidden] = errorReply("The role does not allow the operation")
+		}
+		return openapi.Responses(responses)
+	}
+	beerUUID := openapi.Parameter{Name: "beerUUID", In: "path", Required: true, Schema: openapi.String("uuid")}
+
+	beerStyle := doc.Schema(domain.BeerStyle{})
+	doc.Add(http.MethodGet, "/api/beer-styles/list", openapi.Operation{
+		OperationID: "listBeerStyles",
+		Summary:     "List every beer style",
+		Description: "Supports conditional requests with If-None-Match.",
+		Tags:        []string{"beer-styles"},
+		Security:    optional,
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:            openapi.Reply("The beer styles", openapi.Object(map[string]openapi.Schema{"beerStyles": openapi.ArrayOf(beerStyle)}), listing...),
+			http.StatusNotModified:   openapi.Reply("The client copy is current", nil),
+			http.StatusNotAcceptable: errorReply("No supported format in Accept"),
+		}, true),
+	})
+	doc.Add(http.MethodGet, "/api/beer-styles/:beerUUID", openapi.Operation{
+		OperationID: "getBeerStyle",
+		Summary:     "Get a beer style",
+		Description: "Supports conditional requests with If-None-Match and If-Modified-Since.",
+		Tags:        []string{"beer-styles"},
+		Security:    optional,
+		Parameters:  []openapi.Parameter{beerUUID},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:          openapi.Reply("The beer style", openapi.Object(map[string]openapi.Schema{"data": beerStyle}), "application/json"),
+			http.StatusNotModified: openapi.Reply("The client copy is current", nil),
+			http.StatusBadRequest:  errorReply("Invalid beer style UUID"),
+			http.StatusNotFound:    errorReply("Beer style not found"),
+		}, true),
+	})
+	doc.Add(http.MethodPost, "/api/beer-styles/create", openapi.Operation{
+		OperationID: "createBeerStyle",
+		Summary:     "Create a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    required,
+		RequestBody: openapi.JSON(openapi.Require(beerStyle, "name", "temp_min", "temp_max")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusCreated:    openapi.Reply("The created beer style", openapi.Object(map[string]openapi.Schema{"data": beerStyle}), "application/json"),
+			http.StatusBadRequest: errorReply("Invalid beer style"),
+			http.StatusConflict:   errorReply("A beer style with the name exists"),
+		}, false),
+	})
+	doc.Add(http.MethodPut, "/api/beer-styles/edit/:beerUUID", openapi.Operation{
+		OperationID: "updateBeerStyle",
+		Summary:     "Update the given fields of a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{beerUUID},
+		RequestBody: openapi.JSON(doc.Schema(domain.BeerStyleUpdateRequest{})),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK: openapi.Reply("The updated beer style", openapi.Object(map[string]openapi.Schema{
+				"message": openapi.String(""),
+				"data":    beerStyle,
+			}), "application/json"),
+			http.StatusBadRequest: errorReply("Invalid changes"),
+			http.StatusNotFound:   errorReply("Beer style not found"),
+			http.StatusConflict:   errorReply("A beer style with the name exists"),
+		}, false),
+	})
+	doc.Add(http.MethodDelete, "/api/beer-styles/:beerUUID", openapi.Operation{
+		OperationID: "deleteBeerStyle",
+		Summary:     "Delete a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{beerUUID},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:       openapi.Reply("The beer style was deleted", message, "application/json"),
+			http.StatusNotFound: errorReply("Beer style not found"),
+		}, false),
+	})
+
+	playlist := doc.Schema(domain.BeerStylePlaylist{})
+	doc.Add(http.MethodGet, "/api/beer-styles/:beerUUID/playlists", openapi.Operation{
+		OperationID: "listBeerStylePlaylists",
+		Summary:     "List the playlists pinned to a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    optional,
+		Parameters:  []openapi.Parameter{beerUUID},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:            openapi.Reply("The pinned playlists", openapi.Object(map[string]openapi.Schema{"playlists": openapi.ArrayOf(playlist)}), listing...),
+			http.StatusNotFound:      errorReply("Beer style not found"),
+			http.StatusNotAcceptable: errorReply("No supported format in Accept"),
+		}, true),
+	})
+	doc.Add(http.MethodPost, "/api/beer-styles/:beerUUID/playlists", openapi.Operation{
+		OperationID: "pinBeerStylePlaylist",
+		Summary:     "Pin a provider playlist to a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{beerUUID},
+		RequestBody: openapi.JSON(openapi.Require(doc.Schema(domain.BeerStylePlaylistRequest{}), "provider", "playlist_id")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusCreated:             openapi.Reply("The pinned playlist", openapi.Object(map[string]openapi.Schema{"data": playlist}), "application/json"),
+			http.StatusBadRequest:          errorReply("Invalid playlist"),
+			http.StatusNotFound:            errorReply("Beer style not found"),
+			http.StatusUnprocessableEntity: errorReply("The playlist does not exist on the provider"),
+		}, false),
+	})
+	doc.Add(http.MethodDelete, "/api/beer-styles/:beerUUID/playlists/:playlistUUID", openapi.Operation{
+		OperationID: "unpinBeerStylePlaylist",
+		Summary:     "Unpin a playlist from a beer style",
+		Tags:        []string{"beer-styles"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{beerUUID, {Name: "playlistUUID", In: "path", Required: true, Schema: openapi.String("uuid")}},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:         openapi.Reply("The playlist was unpinned", message, "application/json"),
+			http.StatusBadRequest: errorReply("Invalid playlist UUID"),
+			http.StatusNotFound:   errorReply("Pinned playlist not found"),
+		}, false),
+	})
+
+	doc.Add(http.MethodPost, "/api/recommendations/suggest", openapi.Operation{
+		OperationID: "suggestPlaylist",
+		Summary:     "Recommend a beer style and playlist for a temperature",
+		Tags:        []string{"recommendations"},
+		Security:    optional,
+		RequestBody: openapi.JSON(openapi.Require(doc.Schema(domain.TemperatureRequest{}), "temperature")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:                  openapi.Reply("The recommendation", doc.Schema(domain.RecommendationResponse{}), "application/json"),
+			http.StatusBadRequest:          errorReply("Invalid temperature"),
+			http.StatusNotFound:            errorReply("No beer style or playlist satisfies the request"),
+			http.StatusUnprocessableEntity: errorReply("Invalid preferences"),
+			http.StatusServiceUnavailable:  errorReply("The music provider is unavailable"),
+		}, true),
+	})
+
+	doc.Add(http.MethodPost, "/api/recommendations/explain", openapi.Operation{
+		OperationID: "explainRecommendation",
+		Summary:     "Explain the recommendation for a temperature",
+		Description: "Ranks every candidate style, lists the styles the preferences leave out and the playlist search. The music provider is only called with resolve_playlist=true; otherwise the search is the plan of queries. A request no style satisfies is explained with its constraint instead of failing.",
+		Tags:        []string{"recommendations"},
+		Security:    optional,
+		Parameters:  []openapi.Parameter{{Name: "resolve_playlist", In: "query", Schema: openapi.Schema{"type": "boolean"}}},
+		RequestBody: openapi.JSON(openapi.Require(doc.Schema(domain.TemperatureRequest{}), "temperature")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:                  openapi.Reply("The explanation", doc.Schema(domain.RecommendationExplanation{}), "application/json"),
+			http.StatusBadRequest:          errorReply("Invalid temperature or resolve_playlist"),
+			http.StatusUnprocessableEntity: errorReply("Invalid preferences"),
+		}, true),
+	})
+
+	doc.Add(http.MethodPost, "/api/recommendations/{id}/feedback", openapi.Operation{
+		OperationID: "submitRecommendationFeedback",
+		Summary:     "Rate a recommendation",
+		Description: "Votes up or down the recommendation with the id returned by the suggestion, optionally naming the beer style that should have been recommended. A new vote replaces the previous one. Requires the reader role, and only the client the recommendation was served to may rate it. Feedback on a recommendation the history has not written yet waits for it to be flushed.",
+		Tags:        []string{"recommendations"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: openapi.String("uuid")}},
+		RequestBody: openapi.JSON(openapi.Require(doc.Schema(domain.FeedbackRequest{}), "vote")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK: openapi.Reply("The stored feedback", openapi.Object(map[string]openapi.Schema{
+				"message": openapi.String(""),
+				"data":    doc.Schema(domain.RecommendationFeedback{}),
+			}), "application/json"),
+			http.StatusBadRequest:          errorReply("Invalid id, vote or preferred style"),
+			http.StatusNotFound:            errorReply("Recommendation not found"),
+			http.StatusForbidden:           errorReply("The reader role is required, or the recommendation was served to another client"),
+			http.StatusConflict:            errorReply("Recommendation not written yet; retry after Retry-After seconds"),
+			http.StatusUnprocessableEntity: errorReply("Unknown preferred style"),
+		}, true),
+	})
+
+	doc.Add(http.MethodGet, "/api/analytics/recommendations", openapi.Operation{
+		OperationID: "getRecommendationAnalytics",
+		Summary:     "Aggregate the recommendation history",
+		Description: "Counts the recommendations served between from (inclusive) and to (exclusive) by beer style, temperature bucket and UTC day. The period defaults to the last 30 days and the buckets to 5 degrees. Records older than the configured retention are purged.",
+		Tags:        []string{"analytics"},
+		Security:    required,
+		Parameters: []openapi.Parameter{
+			{Name: "from", In: "query", Description: "Date (2006-01-02) or RFC 3339 time", Schema: openapi.String("")},
+			{Name: "to", In: "query", Description: "Date (2006-01-02) or RFC 3339 time", Schema: openapi.String("")},
+			{Name: "bucket_size", In: "query", Description: "Width of the temperature buckets, in degrees, from 0.5 to 50", Schema: openapi.Schema{"type": "number"}},
+		},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:         openapi.Reply("The aggregations", doc.Schema(domain.RecommendationAnalytics{}), "application/json"),
+			http.StatusBadRequest: errorReply("Invalid period or bucket size"),
+		}, false),
+	})
+
+	apiKey := doc.Schema(domain.APIKey{})
+	keyUUID := openapi.Parameter{Name: "keyUUID", In: "path", Required: true, Schema: openapi.String("uuid")}
+	doc.Add(http.MethodGet, "/api/auth/api-keys", openapi.Operation{
+		OperationID: "listAPIKeys",
+		Summary:     "List the API keys, revoked ones included",
+		Tags:        []string{"auth"},
+		Security:    required,
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:            openapi.Reply("The API keys", openapi.Object(map[string]openapi.Schema{"apiKeys": openapi.ArrayOf(apiKey)}), listing...),
+			http.StatusNotAcceptable: errorReply("No supported format in Accept"),
+		}, false),
+	})
+	doc.Add(http.MethodPost, "/api/auth/api-keys", openapi.Operation{
+		OperationID: "createAPIKey",
+		Summary:     "Create an API key",
+		Description: "The key is only returned in this response.",
+		Tags:        []string{"auth"},
+		Security:    required,
+		RequestBody: openapi.JSON(openapi.Require(doc.Schema(domain.APIKeyRequest{}), "name", "role")),
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusCreated: openapi.Reply("The created key", openapi.Object(map[string]openapi.Schema{
+				"message": openapi.String(""),
+				"data":    doc.Schema(domain.CreatedAPIKey{}),
+			}), "application/json"),
+			http.StatusBadRequest: errorReply("Invalid name or role"),
+		}, false),
+	})
+	doc.Add(http.MethodDelete, "/api/auth/api-keys/:keyUUID", openapi.Operation{
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke an API key",
+		Tags:        []string{"auth"},
+		Security:    required,
+		Parameters:  []openapi.Parameter{keyUUID},
+		Responses: guarded(map[int]openapi.Response{
+			http.StatusOK:         openapi.Reply("The key was revoked", message, "application/json"),
+			http.StatusBadRequest: errorReply("Invalid key UUID"),
+			http.StatusNotFound:   errorReply("API key not found or already revoked"),
+		}, false),
+	})
+
+	status := openapi.Object(map[string]openapi.Schema{"status": openapi.String("")})
+	doc.Add(http.MethodGet, "/healthz", openapi.Operation{
+		OperationID: "liveness",
+		Summary:     "Report that the process is alive",
+		Tags:        []string{"operations"},
+		Responses:   openapi.Responses(map[int]openapi.Response{http.StatusOK: openapi.Reply("Alive", status, "application/json")}),
+	})
+	doc.Add(http.MethodGet, "/api/check", openapi.Operation{
+		OperationID: "check",
+		Summary:     "Report that the process is alive, in the original response format",
+		Tags:        []string{"operations"},
+		Responses:   openapi.Responses(map[int]openapi.Response{http.StatusOK: openapi.Reply("Alive", message, "application/json")}),
+	})
+	healthReport := doc.Schema(domain.HealthReport{})
+	doc.Add(http.MethodGet, "/readyz", openapi.Operation{
+		OperationID: "readiness",
+		Summary:     "Report whether the dependencies allow serving traffic",
+		Tags:        []string{"operations"},
+		Responses: openapi.Responses(map[int]openapi.Response{
+			http.StatusOK:                 openapi.Reply("Ready, possibly degraded", healthReport, "application/json"),
+			http.StatusServiceUnavailable: openapi.Reply("A critical dependency is down or the instance is shutting down", healthReport, "application/json"),
+		}),
+	})
+	doc.Add(http.MethodGet, "/metrics", openapi.Operation{
+		OperationID: "metrics",
+		Summary:     "Prometheus metrics",
+		Tags:        []string{"operations"},
+		Responses:   openapi.Responses(map[int]openapi.Response{http.StatusOK: openapi.Reply("Metrics in the Prometheus text format", openapi.String(""), "text/plain")}),
+	})
+	doc.Add(http.MethodGet, "/openapi.json", openapi.Operation{
+		OperationID: "openapi",
+		Summary:     "This document",
+		Tags:        []string{"operations"},
+		Responses:   openapi.Responses(map[int]openapi.Response{http.StatusOK: openapi.Reply("The OpenAPI document", openapi.Schema{"type": "object"}, "application/json")}),
+	})
+	doc.Add(http.MethodGet, "/docs", openapi.Operation{
+		OperationID: "docs",
+		Summary:     "Documentation page rendering this document",
+		Tags:        []string{"operations"},
+		Responses:   openapi.Responses(map[int]openapi.Response{http.StatusOK: openapi.Reply("The documentation page", openapi.String(""), "text/html")}),
+	})
+
+	return doc
+}
+
+// DocumentGraphQL adds POST /graphql to doc. The schema itself is
+// available through introspection.
+func DocumentGraphQL(doc *openapi.Document) {
+	doc.Tags = append(doc.Tags, openapi.Tag{Name: "graphql", Description: "Beer styles and recommendations over GraphQL"})
+	graphQLErrors := openapi.ArrayOf(openapi.Schema{
+		"type": "object",
+		"properties": openapi.Schema{
+			"message":    openapi.String(""),
+			"path":       openapi.Schema{"type": "array"},
+			"extensions": openapi.Object(map[string]openapi.Schema{"code": openapi.String("")}),
+		},
+		"required": []string{"message"},
+	})
+	doc.Add(http.MethodPost, "/graphql", openapi.Operation{
+		OperationID: "graphql",
+		Summary:     "Run a GraphQL query or mutation",
+		Description: "Each root field enforces the role and the rate limit of the matching REST route and reports failures in errors[].extensions.code.",
+		Tags:        []string{"graphql"},
+		Security:    []map[string][]string{{}, {"apiKey": {}}, {"bearer": {}}},
+		RequestBody: openapi.JSON(openapi.Schema{
+			"type": "object",
+			"properties": openapi.Schema{
+				"query":         openapi.String(""),
+				"operationName": openapi.String(""),
+				"variables":     openapi.Schema{"type": "object"},
+			},
+			"required": []string{"query"},
+		}),
+		Responses: openapi.Responses(map[int]openapi.Response{
+			http.StatusOK: openapi.Reply("The data, with the errors of the fields that failed", openapi.Schema{
+				"type": "object",
+				"properties": openapi.Schema{
+					"data":   openapi.Schema{"type": []string{"object", "null"}},
+					"errors": graphQLErrors,
+				},
+			}, "application/json"),
+			http.StatusBadRequest:   openapi.Reply("The body, the query or its limits are invalid", openapi.Object(map[string]openapi.Schema{"errors": graphQLErrors}), "application/json"),
+			http.StatusUnauthorized: openapi.Reply("Invalid credentials", openapi.Ref("Error"), "application/json"),
+		}),
+	})
+}
+
+// HandleDocs serves doc at /openapi.json and the documentation page at
+// /docs, whose content policy allows the Redoc assets.
+func HandleDocs(router *gin.Engine, doc *openapi.Document) error {
+	spec, err := openapi.SpecHandler(doc)
+	if err != nil {
+		return err
+	}
+	router.GET("/openapi.json", spec)
+	router.GET("/docs", headers.ContentSecurityPolicy(openapi.DocsPolicy), openapi.DocsHandler)
+	return nil
+}
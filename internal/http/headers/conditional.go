@@ -0,0 +1,101 @@
+package headers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Validators identify the current version of a resource for conditional
+// requests. A zero LastModified omits the Last-Modified header.
+type Validators struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// StrongETag hashes parts into a quoted strong entity tag, so a resource
+// gets a new tag whenever any of its parts changes.
+func StrongETag(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// NotModified sets the ETag and Last-Modified headers of v. When the
+// If-None-Match, or failing that If-Modified-Since, header of a GET or HEAD
+// request shows the client already holds this version, it responds 304 and
+// returns true so the handler can skip serializing the body.
+func NotModified(c *gin.Context, v Validators) bool {
+	header := c.Writer.Header()
+	if v.ETag != "" {
+		header.Set("ETag", v.ETag)
+	}
+	if !v.LastModified.IsZero() {
+		header.Set("Last-Modified", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if !isCurrent(c.Request, v) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// isCurrent follows RFC 9110: If-Modified-Since is ignored when
+// If-None-Match is present.
+func isCurrent(r *http.Request, v Validators) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return v.ETag != "" && matchesETag(ifNoneMatch, v.ETag)
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || v.LastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified has second precision.
+	return !v.LastModified.Truncate(time.Second).After(since)
+}
+
+// matchesETag uses the weak comparison If-None-Match calls for, so a W/
+// prefix added by a proxy still matches.
+func matchesETag(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxAge returns the Cache-Control directives letting clients reuse a
+// response for maxAge. shared allows proxies and CDNs to keep it too, which
+// only public responses should. A zero maxAge still allows caching but
+// requires revalidation on every use.
+func MaxAge(maxAge time.Duration, shared bool) string {
+	scope := "private"
+	if shared {
+		scope = "public"
+	}
+	if maxAge <= 0 {
+		return scope + ", " + Revalidate
+	}
+	return scope + ", max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
@@ -0,0 +1,78 @@
+// Package headers sets the security and caching response headers of the
+// API. Security headers apply to every response; caching is decided per
+// route.
+package headers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityPolicy lists the security headers sent with every response. Empty
+// values omit their header.
+type SecurityPolicy struct {
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	FrameOptions          string
+	ContentSecurityPolicy string
+	ReferrerPolicy        string
+}
+
+// Security sets the headers of policy, along with X-Content-Type-Options,
+// before the handler runs so they are present on errors too.
+func Security(policy SecurityPolicy) gin.HandlerFunc {
+	values := map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         policy.FrameOptions,
+		"Content-Security-Policy": policy.ContentSecurityPolicy,
+		"Referrer-Policy":         policy.ReferrerPolicy,
+	}
+	if policy.HSTSMaxAge > 0 {
+		hsts := "max-age=" + strconv.Itoa(int(policy.HSTSMaxAge.Seconds()))
+		if policy.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		values["Strict-Transport-Security"] = hsts
+	}
+	for name, value := range values {
+		if value == "" {
+			delete(values, name)
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		for name, value := range values {
+			header.Set(name, value)
+		}
+		c.Next()
+	}
+}
+
+// ContentSecurityPolicy replaces the policy of Security on a route, for
+// pages that load their own scripts and styles.
+func ContentSecurityPolicy(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Content-Security-Policy", policy)
+		c.Next()
+	}
+}
+
+// NoStore forbids any cache from keeping the response. The router uses it
+// for every route, so responses are only cached where a route opts in.
+const NoStore = "no-store"
+
+// Revalidate lets caches keep the response but requires them to check with
+// the API before reusing it.
+const Revalidate = "no-cache"
+
+// CacheControl sets Cache-Control to directives, replacing the value set by
+// an earlier middleware.
+func CacheControl(directives string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Cache-Control", directives)
+		c.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package headers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestSecurity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Security(SecurityPolicy{
+		HSTSMaxAge:            24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ContentSecurityPolicy: "default-src 'none'",
+	}))
+	router.GET("/api", func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"message": "not found"})
+	})
+	router.GET("/docs", ContentSecurityPolicy("default-src 'self'"), func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html", []byte("<html></html>"))
+	})
+
+	w := serve(router, "/api")
+	for header, expected := range map[string]string{
+		"Strict-Transport-Security": "max-age=86400; includeSubDomains",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Content-Security-Policy":   "default-src 'none'",
+	} {
+		if got := w.Header().Get(header); got != expected {
+			t.Errorf("expected %s %q, got %q", header, expected, got)
+		}
+	}
+	if _, ok := w.Header()["Referrer-Policy"]; ok {
+		t.Error("expected an empty policy to omit its header")
+	}
+
+	if got := serve(router, "/docs").Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("expected the route policy to replace the default, got %q", got)
+	}
+}
+
+func TestCacheControl_RouteOverridesDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CacheControl(NoStore))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/private", ok)
+	router.GET("/public", CacheControl(Revalidate), ok)
+
+	if got := serve(router, "/private").Header().Get("Cache-Control"); got != NoStore {
+		t.Errorf("expected %q by default, got %q", NoStore, got)
+	}
+	if got := serve(router, "/public").Header().Get("Cache-Control"); got != Revalidate {
+		t.Errorf("expected the route to replace the default, got %q", got)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 500, time.UTC)
+	validators := Validators{ETag: StrongETag("1", "ipa"), LastModified: modified}
+
+	router := gin.New()
+	router.GET("/styles", func(c *gin.Context) {
+		if NotModified(c, validators) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"beerStyles": []string{"IPA"}})
+	})
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+	}{
+		{"no validators", nil, http.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": validators.ETag}, http.StatusNotModified},
+		{"weak etag in a list", map[string]string{"If-None-Match": `"other", W/` + validators.ETag}, http.StatusNotModified},
+		{"wildcard", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": StrongETag("2", "ipa")}, http.StatusOK},
+		{"etag wins over date", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/styles", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, w.Code)
+			}
+			if w.Header().Get("ETag") != validators.ETag || w.Header().Get("Last-Modified") != "Mon, 15 Jan 2024 10:30:00 GMT" {
+				t.Errorf("expected the validators on every response, got %v", w.Header())
+			}
+			if tt.expected == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("expected no body on 304, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	for _, tt := range []struct {
+		maxAge   time.Duration
+		shared   bool
+		expected string
+	}{
+		{time.Minute, true, "public, max-age=60"},
+		{5 * time.Minute, false, "private, max-age=300"},
+		{0, true, "public, no-cache"},
+	} {
+		if got := MaxAge(tt.maxAge, tt.shared); got != tt.expected {
+			t.Errorf("MaxAge(%s, %t): expected %q, got %q", tt.maxAge, tt.shared, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package openapi builds the OpenAPI 3.1 document of the API, with the
+// schemas generated from the Go types the handlers read and write, serves it
+// along with a documentation page, and validates request bodies against it.
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of the document. Its schemas are JSON
+// Schema 2020-12.
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lowercase HTTP method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Schema      Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// New returns an empty document.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:         map[string]Schema{},
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+	}
+}
+
+// ginParam matches the :name path parameters of Gin routes.
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Path converts a Gin route path to its OpenAPI form, e.g. /styles/:uuid to
+// /styles/{uuid}.
+func Path(ginPath string) string {
+	return ginParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Add documents the operation served at the Gin route path. Path parameters
+// without a description are added as required strings.
+func (d *Document) Add(method, ginPath string, op Operation) {
+	path := Path(ginPath)
+	for _, match := range ginParam.FindAllStringSubmatch(ginPath, -1) {
+		if !op.hasParameter(match[1]) {
+			op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: Schema{"type": "string"}})
+		}
+	}
+	if d.Paths[path] == nil {
+		d.Paths[path] = PathItem{}
+	}
+	d.Paths[path][strings.ToLower(method)] = &op
+}
+
+func (op Operation) hasParameter(name string) bool {
+	for _, parameter := range op.Parameters {
+		if parameter.Name == name && parameter.In == "path" {
+			return true
+		}
+	}
+	return false
+}
+
+// Operation returns the operation of the Gin route, or nil when it is not
+// documented.
+func (d *Document) Operation(method, ginPath string) *Operation {
+	return d.Paths[Path(ginPath)][strings.ToLower(method)]
+}
+
+// JSON describes a JSON request body of schema.
+func JSON(schema Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]MediaType{"application/json": {Schema: schema}}}
+}
+
+// Responses builds the responses of an operation from status codes and the
+// responses returned by Reply.
+func Responses(byStatus map[int]Response) map[string]Response {
+	responses := make(map[string]Response, len(byStatus))
+	for status, response := range byStatus {
+		responses[strconv.Itoa(status)] = response
+	}
+	return responses
+}
+
+// Reply describes a response with a body of schema in each media type, or
+// without a body when no media type is given.
+func Reply(description string, schema Schema, mediaTypes ...string) Response {
+	response := Response{Description: description}
+	if len(mediaTypes) > 0 {
+		response.Content = make(map[string]MediaType, len(mediaTypes))
+		for _, mediaType := range mediaTypes {
+			response.Content[mediaType] = MediaType{Schema: schema}
+		}
+	}
+	return response
+}
+
+// Operations lists the documented "METHOD path" pairs, sorted.
+func (d *Document) Operations() []string {
+	var operations []string
+	for path, item := range d.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
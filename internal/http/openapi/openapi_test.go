@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type style struct {
+	audit
+	Name     string         `json:"name"`
+	TempMin  float64        `json:"temp_min"`
+	Aliases  []string       `json:"aliases"`
+	Notes    *string        `json:"notes,omitempty"`
+	Labels   map[string]int `json:"labels"`
+	Secret   string         `json:"-"`
+	Parent   *style         `json:"parent"`
+	internal string
+}
+
+func TestSchema(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+
+	if got := doc.Schema(style{}); !reflect.DeepEqual(got, Ref("style")) {
+		t.Fatalf("expected a reference to the component, got %v", got)
+	}
+
+	properties := doc.Components.Schemas["style"]["properties"].(Schema)
+	expected := Schema{
+		"created_at": Schema{"type": "string", "format": "date-time"},
+		"name":       Schema{"type": "string"},
+		"temp_min":   Schema{"type": "number"},
+		"aliases":    Schema{"type": []string{"array", "null"}, "items": Schema{"type": "string"}},
+		"notes":      Schema{"type": []string{"string", "null"}},
+		"labels":     Schema{"type": []string{"object", "null"}, "additionalProperties": Schema{"type": "integer"}},
+		"parent":     Schema{"anyOf": []Schema{Ref("style"), {"type": "null"}}},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected properties %v, got %v", expected, properties)
+	}
+}
+
+func TestAdd_DocumentsPathParameters(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Add(http.MethodDelete, "/styles/:uuid", Operation{Summary: "delete"})
+
+	op := doc.Operation(http.MethodDelete, "/styles/:uuid")
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].Name != "uuid" || !op.Parameters[0].Required {
+		t.Fatalf("expected the path parameter to be documented, got %+v", op)
+	}
+	if ops := doc.Operations(); !reflect.DeepEqual(ops, []string{"DELETE /styles/{uuid}"}) {
+		t.Errorf("unexpected operations %v", ops)
+	}
+}
+
+func setupValidator(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Add(http.MethodPost, "/styles/:uuid", Operation{
+		RequestBody: JSON(Require(doc.Schema(style{}), "name", "temp_min")),
+	})
+	validator, err := NewValidator(doc)
+	if err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+
+	router := gin.New()
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/styles/:uuid", validator.Middleware(), echo)
+	router.POST("/undocumented", validator.Middleware(), echo)
+	return router
+}
+
+func post(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return w
+}
+
+func TestValidator(t *testing.T) {
+	router := setupValidator(t)
+
+	body := `{"name": "Dubbel", "temp_min": 8, "aliases": null}`
+	if w := post(router, "/styles/1", body); w.Code != http.StatusOK || w.Body.String() != body {
+		t.Errorf("expected the body to reach the handler intact, got %d %q", w.Code, w.Body.String())
+	}
+	if w := post(router, "/undocumented", "not json"); w.Code != http.StatusOK {
+		t.Errorf("expected undocumented routes to pass through, got %d", w.Code)
+	}
+
+	w := post(router, "/styles/1", `{"name": `)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid JSON format") {
+		t.Errorf("expected invalid JSON to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = post(router, "/styles/1", `{"temp_min": "cold", "aliases": [1]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	var response struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	expected := []string{
+		"/aliases/0: expected string, but got number",
+		"/temp_min: expected number, but got string",
+		"missing properties: 'name'",
+	}
+	if !reflect.DeepEqual(response.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, response.Errors)
+	}
+}
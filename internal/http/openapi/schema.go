@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema 2020-12 object.
+type Schema map[string]interface{}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema returns the schema of the type of v, following its json tags.
+// Named structs are added once to the components and referenced. Pointers,
+// slices and maps may be null, as encoding/json writes them. No property is
+// required: the operations using a type as a request body list the
+// properties they require with Require.
+func (d *Document) Schema(v interface{}) Schema {
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+func (d *Document) schemaOf(t reflect.Type) Schema {
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Ptr:
+		return nullable(d.schemaOf(t.Elem()))
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.object(t)
+		}
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			// Registered before the properties so recursive types end.
+			d.Components.Schemas[t.Name()] = Schema{}
+			d.Components.Schemas[t.Name()] = d.object(t)
+		}
+		return Ref(t.Name())
+	case reflect.Slice, reflect.Array:
+		return nullable(Schema{"type": "array", "items": d.schemaOf(t.Elem())})
+	case reflect.Map:
+		return nullable(Schema{"type": "object", "additionalProperties": d.schemaOf(t.Elem())})
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	default:
+		return Schema{}
+	}
+}
+
+// object lists the properties of a struct; embedded structs are flattened
+// as encoding/json does.
+func (d *Document) object(t reflect.Type) Schema {
+	properties := Schema{}
+	d.addProperties(t, properties)
+	return Schema{"type": "object", "properties": properties}
+}
+
+func (d *Document) addProperties(t reflect.Type, properties Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			d.addProperties(field.Type, properties)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = d.schemaOf(field.Type)
+	}
+}
+
+// Ref references the named schema of the components.
+func Ref(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// Object describes an object with the given properties, all required, such
+// as the envelopes the handlers wrap their data in.
+func Object(properties map[string]Schema) Schema {
+	props := Schema{}
+	required := make([]string, 0, len(properties))
+	for name, schema := range properties {
+		props[name] = schema
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	return Schema{"type": "object", "properties": props, "required": required}
+}
+
+// Require returns schema with the given properties required.
+func Require(schema Schema, properties ...string) Schema {
+	return Schema{"allOf": []Schema{schema}, "required": properties}
+}
+
+// ArrayOf describes a list of items.
+func ArrayOf(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+// String describes a string, with an optional format such as uuid.
+func String(format string) Schema {
+	if format == "" {
+		return Schema{"type": "string"}
+	}
+	return Schema{"type": "string", "format": format}
+}
+
+// nullable also accepts null, with a type list when schema has a single
+// type.
+func nullable(schema Schema) Schema {
+	typ, ok := schema["type"].(string)
+	if !ok {
+		return Schema{"anyOf": []Schema{schema, {"type": "null"}}}
+	}
+	result := make(Schema, len(schema))
+	for key, value := range schema {
+		result[key] = value
+	}
+	result["type"] = []string{typ, "null"}
+	return result
+}
@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"backend-test/internal/logging"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Validator checks the JSON request bodies of the documented operations
+// against their schemas, so handlers only see well-typed input.
+type Validator struct {
+	bodies map[string]*jsonschema.Schema
+}
+
+// NewValidator compiles the request body schemas of doc.
+func NewValidator(doc *Document) (*Validator, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the OpenAPI document: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	if err := compiler.AddResource("openapi.json", bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	v := &Validator{bodies: map[string]*jsonschema.Schema{}}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			if op.RequestBody == nil {
+				continue
+			}
+			if _, ok := op.RequestBody.Content["application/json"]; !ok {
+				continue
+			}
+			pointer := "openapi.json#/paths/" + escapePointer(path) + "/" + method + "/requestBody/content/application~1json/schema"
+			schema, err := compiler.Compile(pointer)
+			if err != nil {
+				return nil, fmt.Errorf("invalid request schema of %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			v.bodies[strings.ToUpper(method)+" "+path] = schema
+		}
+	}
+	return v, nil
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// Middleware rejects with 400 the requests whose body is not JSON or does
+// not match the schema of their route, listing every mismatch. Routes
+// without a documented JSON body pass through. It runs after authentication
+// so anonymous clients learn nothing about the schemas.
+func (v *Validator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema, ok := v.bodies[c.Request.Method+" "+Path(c.FullPath())]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			abort(c, gin.H{"message": "cannot read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var instance interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&instance); err != nil {
+			abort(c, gin.H{"message": "invalid JSON format"})
+			return
+		}
+
+		if err := schema.Validate(instance); err != nil {
+			abort(c, gin.H{
+				"message": "request body does not match the API schema",
+				"errors":  mismatches(err),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// mismatches flattens a validation error into its leaf causes, prefixed by
+// the JSON pointer of the offending value.
+func mismatches(err error) []string {
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	var messages []string
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			message := e.Message
+			if e.InstanceLocation != "" {
+				message = e.InstanceLocation + ": " + message
+			}
+			messages = append(messages, message)
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+	sort.Strings(messages)
+	return messages
+}
+
+func abort(c *gin.Context, body gin.H) {
+	if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, body)
+}
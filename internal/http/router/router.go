@@ -1,30 +1,72 @@
 package router
 
 import (
-	"net/http"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/http/compress"
+	"backend-test/internal/http/headers"
+	"backend-test/internal/logging"
+	"backend-test/internal/metrics"
+	"backend-test/internal/ratelimit"
+	"backend-test/internal/tracing"
+	"log/slog"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter() (router *gin.Engine) {
-	return setConfigs(gin.Default())
+// NewRouter returns the Gin engine with the request ID, tracing, metrics,
+// access log, CORS, security header and compression middlewares. The access
+// log replaces the Gin default logger. Responses are not cached unless their
+// route allows it.
+func NewRouter(cfg *config.Config, logger *slog.Logger) (router *gin.Engine) {
+	return setConfigs(gin.New(), cfg, logger)
 }
 
-func setConfigs(router *gin.Engine) *gin.Engine {
-	router.Use(cors.New(cors.Config{AllowOrigins: []string{"*"},
-		AllowMethods:     []string{http.MethodGet, http.MethodPatch, http.MethodPut, http.MethodPost, http.MethodHead, http.MethodDelete, http.MethodOptions},
-		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true}))
-
-	router.Use(func(c *gin.Context) {
-		header := c.Writer.Header()
-		header.Add("Cache-Control", "no-cache, no-store, must-revalidate")
-		header.Add("Pragma", "no-cache")
-		header.Add("Expires", "0")
-		c.Next()
-	})
+func setConfigs(router *gin.Engine, cfg *config.Config, logger *slog.Logger) *gin.Engine {
+	// Gin trusts every proxy by default, which lets any client pick its
+	// ClientIP, and so its rate limit bucket, with X-Forwarded-For.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Error("invalid trusted proxies, trusting none", "err", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+
+	router.Use(gin.Recovery())
+	router.Use(logging.RequestIDMiddleware())
+	router.Use(tracing.Middleware(cfg.Tracing.ServiceName))
+	router.Use(metrics.HTTPMiddleware())
+	router.Use(logging.AccessLogMiddleware(logger))
+
+	if corsMiddleware := newCORS(cfg.CORS.WithPreset()); corsMiddleware != nil {
+		router.Use(corsMiddleware)
+	}
+	router.Use(headers.Security(headers.SecurityPolicy{
+		HSTSMaxAge:            cfg.Security.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.Security.HSTSIncludeSubdomains,
+		FrameOptions:          cfg.Security.FrameOptions,
+		ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+		ReferrerPolicy:        cfg.Security.ReferrerPolicy,
+	}))
+	// Routes that can be cached say so in the handler package.
+	router.Use(headers.CacheControl(headers.NoStore))
+	if cfg.Compression.Enabled {
+		router.Use(compress.Middleware(cfg.Compression.MinSize))
+	}
 
 	return router
 }
+
+// newCORS returns the CORS middleware of cfg, or nil when no origin is
+// allowed and browsers are left to their same-origin policy.
+func newCORS(cfg config.CORSConfig) gin.HandlerFunc {
+	if len(cfg.AllowOrigins) == 0 {
+		return nil
+	}
+	return cors.New(cors.Config{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    append([]string{"Content-Length", "ETag", logging.RequestIDHeader}, ratelimit.Headers...),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/logging"
+	"backend-test/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := NewRouter(cfg, logging.Discard())
+	router.GET("/api/beer-styles/list", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+	return router
+}
+
+func request(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/beer-styles/list", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewRouter_Defaults(t *testing.T) {
+	w := request(setupRouter(config.Default()), http.MethodGet, "https://any.example.com")
+
+	for header, expected := range map[string]string{
+		"Access-Control-Allow-Origin": "*",
+		"Cache-Control":               "no-store",
+		"X-Content-Type-Options":      "nosniff",
+		"X-Frame-Options":             "DENY",
+		"Strict-Transport-Security":   "max-age=15552000",
+		"Content-Security-Policy":     "default-src 'none'; frame-ancestors 'none'",
+	} {
+		if got := w.Header().Get(header); got != expected {
+			t.Errorf("expected %s %q, got %q", header, expected, got)
+		}
+	}
+	for _, legacy := range []string{"Pragma", "Expires"} {
+		if got := w.Header().Get(legacy); got != "" {
+			t.Errorf("expected no %s header, got %q", legacy, got)
+		}
+	}
+}
+
+func TestNewRouter_ProductionCORS(t *testing.T) {
+	cfg := config.Default()
+	cfg.CORS = config.CORSConfig{Preset: "production", AllowOrigins: []string{"https://beer.example.com"}, AllowCredentials: true}
+	router := setupRouter(cfg)
+
+	w := request(router, http.MethodOptions, "https://beer.example.com")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://beer.example.com" {
+		t.Errorf("expected the preflight of a listed origin to pass, got %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "true" || w.Header().Get("Access-Control-Max-Age") != "3600" {
+		t.Errorf("unexpected preflight headers: %v", w.Header())
+	}
+
+	if w := request(router, http.MethodGet, "https://evil.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("expected another origin to be rejected, got %d", w.Code)
+	}
+
+	cfg.CORS.AllowOrigins = nil
+	if w := request(setupRouter(cfg), http.MethodGet, "https://beer.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected no CORS headers without origins, got %v", w.Header())
+	}
+}
+
+func TestNewRouter_TrustedProxies(t *testing.T) {
+	rateLimited := func(cfg *config.Config) *gin.Engine {
+		router := setupRouter(cfg)
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemory(), nil, logging.Discard())
+		router.GET("/suggest", limiter.Middleware("recommendation", ratelimit.Limit{Rate: 0.5, Burst: 1}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	get := func(router *gin.Engine, remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/suggest", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	router := rateLimited(config.Default())
+	if code := get(router, "203.0.113.7:1234", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", code)
+	}
+	if code := get(router, "203.0.113.7:1234", "198.51.100.2"); code != http.StatusTooManyRequests {
+		t.Errorf("expected a spoofed X-Forwarded-For to hit the same bucket, got %d", code)
+	}
+
+	cfg := config.Default()
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8"}
+	router = rateLimited(cfg)
+	if code := get(router, "10.0.0.5:1234", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("expected the first client behind the proxy to pass, got %d", code)
+	}
+	if code := get(router, "10.0.0.5:1234", "198.51.100.2"); code != http.StatusOK {
+		t.Errorf("expected another client behind a trusted proxy to have its own bucket, got %d", code)
+	}
+}
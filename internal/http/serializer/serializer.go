@@ -0,0 +1,161 @@
+// Package serializer writes response bodies in the media type negotiated
+// with the client's Accept header: JSON, MessagePack or, for bodies that are
+// tables, CSV. Field names follow the json tags in every format.
+package serializer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	JSON    = "application/json"
+	MsgPack = "application/msgpack"
+	CSV     = "text/csv"
+)
+
+// aliases are media types clients send for the formats above.
+var aliases = map[string]string{
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+}
+
+// Table is implemented by listings that can also be written as CSV, with a
+// header row followed by one row per item.
+type Table interface {
+	CSVHeader() []string
+	CSVRows() [][]string
+}
+
+// Formats returns the media types body can be written in, JSON first.
+func Formats(body interface{}) []string {
+	if _, ok := body.(Table); ok {
+		return []string{JSON, MsgPack, CSV}
+	}
+	return []string{JSON, MsgPack}
+}
+
+// Negotiate returns the media type of offered that accept prefers, by
+// quality and then by the order of offered. A missing Accept header accepts
+// anything. It returns false when accept rules out every offered type.
+func Negotiate(accept string, offered []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offered[0], true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+	for _, mediaType := range offered {
+		if quality := qualityOf(mediaType, ranges); quality > bestQuality {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best, best != ""
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept returns the ranges of accept, the most specific first so the
+// first match of a media type carries its quality.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		if alias, ok := aliases[mediaType]; ok {
+			mediaType = alias
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+	return ranges
+}
+
+func specificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func qualityOf(mediaType string, ranges []mediaRange) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	for _, r := range ranges {
+		if r.mediaType == mediaType || r.mediaType == typ+"/*" || r.mediaType == "*/*" {
+			return r.quality
+		}
+	}
+	return 0
+}
+
+// Write encodes body as mediaType and writes it with status. Encoding
+// failures respond 500 without a partial body.
+func Write(c *gin.Context, status int, mediaType string, body interface{}) {
+	data, contentType, err := encode(mediaType, body)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, contentType, data)
+}
+
+func encode(mediaType string, body interface{}) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch mediaType {
+	case MsgPack:
+		encoder := msgpack.NewEncoder(&buf)
+		encoder.SetCustomStructTag("json")
+		if err := encoder.Encode(body); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), MsgPack, nil
+	case CSV:
+		table, _ := body.(Table)
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(table.CSVHeader()); err != nil {
+			return nil, "", err
+		}
+		if err := writer.WriteAll(table.CSVRows()); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), CSV + "; charset=utf-8", nil
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, JSON + "; charset=utf-8", nil
+	}
+}
@@ -0,0 +1,99 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type styles struct {
+	Names []string `json:"names"`
+}
+
+func (s styles) CSVHeader() []string { return []string{"name"} }
+
+func (s styles) CSVRows() [][]string {
+	rows := make([][]string, 0, len(s.Names))
+	for _, name := range s.Names {
+		rows = append(rows, []string{name})
+	}
+	return rows
+}
+
+func TestNegotiate(t *testing.T) {
+	all := []string{JSON, MsgPack, CSV}
+
+	tests := []struct {
+		accept   string
+		offered  []string
+		expected string
+	}{
+		{"", all, JSON},
+		{"*/*", all, JSON},
+		{"text/csv", all, CSV},
+		{"application/x-msgpack", all, MsgPack},
+		{"application/json;q=0.5, application/msgpack", all, MsgPack},
+		{"text/*, application/json;q=0.9", all, CSV},
+		{"application/*;q=0.2, application/msgpack;q=0", all, JSON},
+		{"text/html, */*;q=0.1", all, JSON},
+		{"text/csv", []string{JSON, MsgPack}, ""},
+		{"application/xml", all, ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := Negotiate(tt.accept, tt.offered)
+		if got != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("Negotiate(%q): expected %q, got %q (%t)", tt.accept, tt.expected, got, ok)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	if formats := Formats(styles{}); len(formats) != 3 || formats[2] != CSV {
+		t.Errorf("expected tables to offer CSV, got %v", formats)
+	}
+	if formats := Formats(gin.H{}); len(formats) != 2 {
+		t.Errorf("expected other bodies to offer JSON and MessagePack, got %v", formats)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := styles{Names: []string{"IPA", `Stout, "Imperial"`}}
+
+	write := func(mediaType string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		Write(c, http.StatusOK, mediaType, body)
+		return w
+	}
+
+	w := write(JSON)
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" || w.Body.String() != `{"names":["IPA","Stout, \"Imperial\""]}` {
+		t.Errorf("unexpected JSON: %s %s", w.Header().Get("Content-Type"), w.Body.String())
+	}
+
+	w = write(MsgPack)
+	var decoded map[string][]string
+	if err := msgpack.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid MessagePack: %v", err)
+	}
+	if w.Header().Get("Content-Type") != MsgPack || len(decoded["names"]) != 2 {
+		t.Errorf("expected the json field names in MessagePack, got %v", decoded)
+	}
+
+	w = write(CSV)
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if w.Header().Get("Content-Type") != "text/csv; charset=utf-8" || len(records) != 3 || records[0][0] != "name" || records[2][0] != `Stout, "Imperial"` {
+		t.Errorf("unexpected CSV: %q", records)
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Readiness reports whether the instance should receive new traffic. It is
+// flipped to not ready as soon as shutdown starts, before the drain.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	readiness := &Readiness{}
+	readiness.ready.Store(true)
+	return readiness
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// closer is a resource released after the HTTP server has drained.
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+type Options struct {
+	// DrainTimeout bounds how long in-flight requests have to complete.
+	DrainTimeout time.Duration
+	// ReadinessDelay is how long the instance reports not ready before the
+	// listener is closed, so load balancers stop routing new requests to it.
+	ReadinessDelay time.Duration
+}
+
+type Server struct {
+	httpServer *http.Server
+	readiness  *Readiness
+	options    Options
+	closers    []closer
+}
+
+func New(addr string, handler http.Handler, readiness *Readiness, options Options) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		readiness: readiness,
+		options:   options,
+	}
+}
+
+// OnShutdown registers a resource to be released once in-flight requests are
+// drained. Resources are released in registration order, so background
+// workers should be registered before the Redis client and database pool
+// they depend on.
+func (s *Server) OnShutdown(name string, close func(ctx context.Context) error) {
+	s.closers = append(s.closers, closer{name: name, close: close})
+}
+
+// ListenAndServe serves until ctx is cancelled and then shuts down gracefully.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections on listener until ctx is cancelled. Shutdown
+// flips readiness, waits ReadinessDelay, drains in-flight requests for up to
+// DrainTimeout and then releases the registered resources in order.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", listener.Addr().String())
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.closeResources()
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutdown signal received, marking instance as not ready")
+	s.readiness.SetReady(false)
+	if s.options.ReadinessDelay > 0 {
+		time.Sleep(s.options.ReadinessDelay)
+	}
+
+	slog.Info("draining in-flight requests", "timeout", s.options.DrainTimeout.String())
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.options.DrainTimeout)
+	defer cancel()
+
+	shutdownErr := s.httpServer.Shutdown(drainCtx)
+	if shutdownErr != nil {
+		slog.Warn("drain did not complete", "err", shutdownErr)
+		s.httpServer.Close()
+	}
+
+	s.closeResources()
+	slog.Info("server stopped")
+
+	return shutdownErr
+}
+
+func (s *Server) closeResources() {
+	for _, c := range s.closers {
+		slog.Info("closing resource", "resource", c.name)
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.options.DrainTimeout)
+		if err := c.close(ctx); err != nil {
+			slog.Error("failed to close resource", "resource", c.name, "err", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServer_Serve_DrainsInFlightRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	readiness := NewReadiness()
+	srv := New("", router, readiness, Options{DrainTimeout: 5 * time.Second})
+
+	var closed []string
+	srv.OnShutdown("workers", func(ctx context.Context) error {
+		closed = append(closed, "workers")
+		return nil
+	})
+	srv.OnShutdown("database pool", func(ctx context.Context) error {
+		closed = append(closed, "database pool")
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveDone := make(chan error, 1)
+	go func() {
+		serveDone <- srv.Serve(ctx, listener)
+	}()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- result{status: resp.StatusCode, body: string(body)}
+	}()
+
+	<-started
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for readiness.IsReady() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if readiness.IsReady() {
+		t.Fatal("Expected readiness to flip before the drain")
+	}
+
+	select {
+	case err := <-serveDone:
+		t.Fatalf("Expected Serve to wait for the in-flight request, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	response := <-responses
+	if response.err != nil {
+		t.Fatalf("Expected in-flight request to complete, got %v", response.err)
+	}
+	if response.status != http.StatusOK || response.body != "done" {
+		t.Errorf("Expected 200 'done', got %d '%s'", response.status, response.body)
+	}
+
+	if err := <-serveDone; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+
+	expected := []string{"workers", "database pool"}
+	if !reflect.DeepEqual(closed, expected) {
+		t.Errorf("Expected resources closed in order %v, got %v", expected, closed)
+	}
+}
+
+func TestServer_Serve_DrainTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	router := gin.New()
+	router.GET("/stuck", func(c *gin.Context) {
+		close(started)
+		<-release
+	})
+
+	srv := New("", router, NewReadiness(), Options{DrainTimeout: 50 * time.Millisecond})
+
+	resourcesClosed := false
+	srv.OnShutdown("database pool", func(ctx context.Context) error {
+		resourcesClosed = true
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveDone := make(chan error, 1)
+	go func() {
+		serveDone <- srv.Serve(ctx, listener)
+	}()
+
+	go http.Get("http://" + listener.Addr().String() + "/stuck")
+
+	<-started
+	cancel()
+
+	if err := <-serveDone; err != context.DeadlineExceeded {
+		t.Errorf("Expected drain timeout error, got %v", err)
+	}
+	if !resourcesClosed {
+		t.Error("Expected resources to be closed after the drain timeout")
+	}
+}
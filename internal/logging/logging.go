@@ -0,0 +1,141 @@
+// Package logging builds the structured slog logger of the API: level and
+// format from the environment, request and trace IDs taken from the context
+// of every record, and redaction of secrets.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// secretKeys are attribute keys whose values are always redacted.
+var secretKeys = []string{"secret", "password", "token", "authorization", "api_key", "apikey"}
+
+const redacted = "[REDACTED]"
+
+// New returns a logger writing to w. level is one of debug, info, warn or
+// error (default info); format is json (default) or text. Every occurrence
+// of one of secrets is masked.
+func New(w io.Writer, level, format string, secrets ...string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level:       ParseLevel(level),
+		ReplaceAttr: newRedactor(secrets).replaceAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "text") {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	return slog.New(&contextHandler{next: handler})
+}
+
+// Discard returns a logger that drops every record, for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request ID and trace ID found in the context to
+// every record logged with a *Context method.
+type contextHandler struct {
+	next slog.Handler
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name)}
+}
+
+// redactor masks attributes named like secrets and any occurrence of a
+// configured secret value inside string attributes, such as an error that
+// quotes a request URL.
+type redactor struct {
+	values   []string
+	replacer *strings.Replacer
+}
+
+func newRedactor(secrets []string) *redactor {
+	values := make([]string, 0, len(secrets))
+	pairs := make([]string, 0, len(secrets)*2)
+	for _, value := range secrets {
+		// Very short values would mask unrelated text.
+		if len(value) < 4 {
+			continue
+		}
+		values = append(values, value)
+		pairs = append(pairs, value, redacted)
+	}
+	return &redactor{values: values, replacer: strings.NewReplacer(pairs...)}
+}
+
+func (r *redactor) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	for _, secretKey := range secretKeys {
+		if strings.Contains(key, secretKey) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+
+	if len(r.values) == 0 {
+		return attr
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, r.replacer.Replace(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, r.replacer.Replace(err.Error()))
+		}
+	}
+	return attr
+}
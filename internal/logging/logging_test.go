@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "info", "json", "super-secret-value", "db-password", "abc")
+
+	logger.Info("token request failed",
+		"client_secret", "anything",
+		"err", errors.New("POST https://accounts.spotify.com?secret=super-secret-value failed"),
+		"dsn", "connecting with db-password",
+	)
+
+	output := buf.String()
+	for _, secret := range []string{"super-secret-value", "db-password", "anything"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("expected %q to be redacted, got %s", secret, output)
+		}
+	}
+
+	record := decodeRecords(t, &buf)[0]
+	if record["client_secret"] != redacted {
+		t.Errorf("expected client_secret to be redacted, got %v", record["client_secret"])
+	}
+}
+
+func TestNew_LevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "warn", "text")
+
+	logger.Info("hidden")
+	logger.Warn("shown", "key", "value")
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Errorf("expected info record to be dropped at warn level, got %s", output)
+	}
+	if !strings.Contains(output, "msg=shown") || !strings.Contains(output, "key=value") {
+		t.Errorf("expected text output, got %s", output)
+	}
+}
+
+func TestNew_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "info", "json")
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.With("controller", "BeerController").InfoContext(ctx, "handled")
+
+	record := decodeRecords(t, &buf)[0]
+	if record["request_id"] != "req-123" {
+		t.Errorf("expected request_id req-123, got %v", record["request_id"])
+	}
+	if record["controller"] != "BeerController" {
+		t.Errorf("expected controller attribute to be kept, got %v", record["controller"])
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"DEBUG":   slog.LevelDebug,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"bogus":   slog.LevelInfo,
+	}
+
+	for input, expected := range tests {
+		if got := ParseLevel(input); got != expected {
+			t.Errorf("ParseLevel(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+}
+
+func setupRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := New(buf, "info", "json")
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), AccessLogMiddleware(logger))
+	router.GET("/ping", func(c *gin.Context) {
+		logger.InfoContext(c.Request.Context(), "inside handler")
+		c.JSON(http.StatusOK, gin.H{"request_id": RequestID(c.Request.Context())})
+	})
+	return router
+}
+
+func TestRequestIDMiddleware_PropagatesHeader(t *testing.T) {
+	var buf bytes.Buffer
+	router := setupRouter(&buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "client-id-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "client-id-42" {
+		t.Errorf("expected response header client-id-42, got %q", got)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("expected handler and access log records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record["request_id"] != "client-id-42" {
+			t.Errorf("expected request_id on %v, got %v", record["msg"], record["request_id"])
+		}
+	}
+
+	access := records[1]
+	if access["route"] != "/ping" || access["status"] != float64(http.StatusOK) {
+		t.Errorf("unexpected access log record: %v", access)
+	}
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	var buf bytes.Buffer
+	router := setupRouter(&buf)
+
+	for _, header := range []string{"", "bad id with spaces\n"} {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get(RequestIDHeader)
+		if got == "" || got == header {
+			t.Errorf("expected a generated request ID for header %q, got %q", header, got)
+		}
+		if !strings.Contains(w.Body.String(), got) {
+			t.Errorf("expected the handler to see request ID %q, got %s", got, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package metrics holds the Prometheus collectors exposed at /metrics and the
+// helpers used to record HTTP, database, music provider, recommendation and
+// recommendation history activity.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "beer_api"
+
+var (
+	// Registry holds every collector of the API. A dedicated registry keeps
+	// /metrics free of collectors registered by dependencies.
+	Registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Repository query latency by repository, method and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method", "outcome"})
+
+	musicRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "music_provider_request_duration_seconds",
+		Help:      "Music provider call latency by provider and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "operation"})
+
+	musicRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "music_provider_errors_total",
+		Help:      "Failed music provider calls by provider and operation.",
+	}, []string{"provider", "operation"})
+
+	spotifyTokenRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "spotify_token_requests_total",
+		Help:      "Spotify token lookups by source (redis_cache, refresh) and outcome.",
+	}, []string{"source", "outcome"})
+
+	recommendations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recommendations_total",
+		Help:      "Recommendations served by beer style and music provider.",
+	}, []string{"beer_style", "provider"})
+
+	historyRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recommendation_history_records_total",
+		Help:      "Recommendation history records by outcome (stored, dropped, failed, purged).",
+	}, []string{"outcome"})
+
+	recommendationFeedback = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recommendation_feedback_total",
+		Help:      "Recommendation feedback received by vote (up, down).",
+	}, []string{"vote"})
+
+	rateLimitDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_decisions_total",
+		Help:      "Rate limit decisions by policy (read, write, recommendation) and decision (allowed, limited, error).",
+	}, []string{"policy", "decision"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		dbQueryDuration,
+		musicRequestDuration,
+		musicRequestErrors,
+		spotifyTokenRequests,
+		recommendations,
+		historyRecords,
+		recommendationFeedback,
+		rateLimitDecisions,
+	)
+}
+
+// Handler serves the collectors of Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// HTTPMiddleware records the count and latency of every request, labelled by
+// the Gin route template so path parameters do not explode cardinality.
+func HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveDBQuery records the latency of a repository method. Lookups that
+// find no row are labelled not_found rather than error.
+func ObserveDBQuery(repository, method string, start time.Time, err error) {
+	result := outcome(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		result = "not_found"
+	}
+	dbQueryDuration.WithLabelValues(repository, method, result).Observe(time.Since(start).Seconds())
+}
+
+// ObserveMusicRequest records the latency of a music provider call and
+// counts it as an error when err is set.
+func ObserveMusicRequest(provider, operation string, start time.Time, err error) {
+	musicRequestDuration.WithLabelValues(provider, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		musicRequestErrors.WithLabelValues(provider, operation).Inc()
+	}
+}
+
+// CountSpotifyToken counts a Spotify token lookup from source.
+func CountSpotifyToken(source string, err error) {
+	spotifyTokenRequests.WithLabelValues(source, outcome(err)).Inc()
+}
+
+// CountRecommendation counts a recommendation served for beerStyle.
+func CountRecommendation(beerStyle, provider string) {
+	recommendations.WithLabelValues(beerStyle, provider).Inc()
+}
+
+// CountHistoryRecords counts n recommendation history records with the
+// given outcome: stored, dropped when the buffer is full, failed to write or
+// purged after the retention.
+func CountHistoryRecords(outcome string, n int) {
+	historyRecords.WithLabelValues(outcome).Add(float64(n))
+}
+
+// CountFeedback counts a vote on a recommendation.
+func CountFeedback(vote string) {
+	recommendationFeedback.WithLabelValues(vote).Inc()
+}
+
+// CountRateLimit counts a rate limit decision of policy.
+func CountRateLimit(policy, decision string) {
+	rateLimitDecisions.WithLabelValues(policy, decision).Inc()
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"backend-test/external/music"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type stubProvider struct {
+	err error
+}
+
+func (s *stubProvider) Name() string {
+	return "stub"
+}
+
+func (s *stubProvider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	return nil, s.err
+}
+
+func (s *stubProvider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	return nil, s.err
+}
+
+func TestHTTPMiddleware_LabelsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(HTTPMiddleware())
+	router.GET("/api/beer-styles/:beerUUID/playlists", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, uuid := range []string{"a", "b"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/beer-styles/"+uuid+"/playlists", nil))
+	}
+
+	count := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/api/beer-styles/:beerUUID/playlists", "200"))
+	if count != 2 {
+		t.Errorf("Expected 2 requests for the route template, got %v", count)
+	}
+}
+
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestObserveDBQuery_Outcomes(t *testing.T) {
+	ObserveDBQuery("test", "Get", time.Now(), nil)
+	ObserveDBQuery("test", "Get", time.Now(), fmt.Errorf("lookup: %w", sql.ErrNoRows))
+	ObserveDBQuery("test", "Get", time.Now(), errors.New("connection reset"))
+
+	body := scrape(t)
+	for _, outcome := range []string{"success", "not_found", "error"} {
+		series := fmt.Sprintf(`beer_api_db_query_duration_seconds_count{method="Get",outcome="%s",repository="test"} 1`, outcome)
+		if !strings.Contains(body, series) {
+			t.Errorf("Expected series %s", series)
+		}
+	}
+}
+
+func TestInstrumentProvider_CountsErrors(t *testing.T) {
+	notFound := InstrumentProvider(&stubProvider{err: music.ErrPlaylistNotFound})
+	failing := InstrumentProvider(&stubProvider{err: music.ErrProviderUnavailable})
+
+	notFound.SearchPlaylists(context.Background(), "IPA", 1)
+	failing.GetPlaylist(context.Background(), "1001")
+
+	if got := testutil.ToFloat64(musicRequestErrors.WithLabelValues("stub", "search_playlists")); got != 0 {
+		t.Errorf("Expected searches without results not to count as errors, got %v", got)
+	}
+	if got := testutil.ToFloat64(musicRequestErrors.WithLabelValues("stub", "get_playlist")); got != 1 {
+		t.Errorf("Expected 1 get_playlist error, got %v", got)
+	}
+	if !strings.Contains(scrape(t), `beer_api_music_provider_request_duration_seconds_count{operation="search_playlists",provider="stub"} 1`) {
+		t.Error("Expected search latency to be observed")
+	}
+}
+
+func TestPoolCollector_SkipsClosedPool(t *testing.T) {
+	collector := &poolCollector{stat: func() *pgxpool.Stat { return nil }}
+
+	if got := testutil.CollectAndCount(collector); got != 0 {
+		t.Errorf("Expected no pool metrics while the pool is closed, got %d", got)
+	}
+}
+
+func TestCountRecommendation(t *testing.T) {
+	CountRecommendation("IPA", "deezer")
+	CountRecommendation("IPA", "deezer")
+
+	if got := testutil.ToFloat64(recommendations.WithLabelValues("IPA", "deezer")); got != 2 {
+		t.Errorf("Expected 2 IPA recommendations, got %v", got)
+	}
+}
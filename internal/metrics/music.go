@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"backend-test/external/music"
+	"context"
+	"errors"
+	"time"
+)
+
+// instrumentedProvider records latency and errors of every call made to the
+// wrapped music provider.
+type instrumentedProvider struct {
+	next music.Provider
+}
+
+// InstrumentProvider wraps provider so its calls are exported as metrics.
+func InstrumentProvider(provider music.Provider) music.Provider {
+	return &instrumentedProvider{next: provider}
+}
+
+func (p *instrumentedProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *instrumentedProvider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	start := time.Now()
+	playlists, err := p.next.SearchPlaylists(ctx, query, limit)
+	ObserveMusicRequest(p.next.Name(), "search_playlists", start, ignoreNotFound(err))
+	return playlists, err
+}
+
+func (p *instrumentedProvider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	start := time.Now()
+	playlist, err := p.next.GetPlaylist(ctx, playlistID)
+	ObserveMusicRequest(p.next.Name(), "get_playlist", start, ignoreNotFound(err))
+	return playlist, err
+}
+
+// ignoreNotFound keeps searches without results out of the error count; they
+// are a normal answer, not a provider failure.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, music.ErrPlaylistNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolMaxConns = prometheus.NewDesc(namespace+"_db_pool_max_connections",
+		"Maximum size of the database connection pool.", nil, nil)
+	poolOpenConns = prometheus.NewDesc(namespace+"_db_pool_open_connections",
+		"Open connections in the database pool.", nil, nil)
+	poolInUseConns = prometheus.NewDesc(namespace+"_db_pool_in_use_connections",
+		"Connections currently acquired from the database pool.", nil, nil)
+	poolIdleConns = prometheus.NewDesc(namespace+"_db_pool_idle_connections",
+		"Idle connections in the database pool.", nil, nil)
+	poolWaitCount = prometheus.NewDesc(namespace+"_db_pool_wait_count_total",
+		"Acquires that had to wait for a connection.", nil, nil)
+	poolWaitSeconds = prometheus.NewDesc(namespace+"_db_pool_acquire_duration_seconds_total",
+		"Total time spent acquiring connections.", nil, nil)
+)
+
+// poolCollector reads the pool stats at scrape time. The stat function
+// returns nil while the pool is not open, in which case nothing is reported.
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+}
+
+// RegisterDBPool exposes the stats of the database connection pool until the
+// returned function unregisters them.
+func RegisterDBPool(stat func() *pgxpool.Stat) func() {
+	collector := &poolCollector{stat: stat}
+	Registry.MustRegister(collector)
+	return func() { Registry.Unregister(collector) }
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolMaxConns
+	ch <- poolOpenConns
+	ch <- poolInUseConns
+	ch <- poolIdleConns
+	ch <- poolWaitCount
+	ch <- poolWaitSeconds
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := p.stat()
+	if stat == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolOpenConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolInUseConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolWaitCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"backend-test/internal/auth"
+	"backend-test/internal/logging"
+	"backend-test/internal/metrics"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers are the response headers set by the middleware, exposed to
+// browsers through CORS.
+var Headers = []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"}
+
+// Limiter builds the rate limiting middleware of each policy over a shared
+// store.
+type Limiter struct {
+	store  Store
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// NewLimiter returns a Limiter over store. A nil now uses time.Now.
+func NewLimiter(store Store, now func() time.Time, logger *slog.Logger) *Limiter {
+	if now == nil {
+		now = time.Now
+	}
+	return &Limiter{store: store, now: now, logger: logger.With("component", "ratelimit")}
+}
+
+// Take removes a token from the bucket of client under policy. It fails
+// open: when the store fails the request is allowed and ok is false, so no
+// RateLimit headers can be reported.
+func (l *Limiter) Take(ctx context.Context, policy, client string, limit Limit) (result Result, ok bool) {
+	result, err := l.store.Take(ctx, policy+":"+client, limit, l.now())
+	if err != nil {
+		metrics.CountRateLimit(policy, "error")
+		l.logger.WarnContext(ctx, "rate limit store failed, allowing request", "policy", policy, "err", err)
+		return Result{Allowed: true}, false
+	}
+
+	if result.Allowed {
+		metrics.CountRateLimit(policy, "allowed")
+	} else {
+		metrics.CountRateLimit(policy, "limited")
+	}
+	return result, true
+}
+
+// Middleware limits the requests of each client to limit. Clients are keyed
+// by the authenticated principal, so it must run after the authentication
+// middleware, and by IP address otherwise. Every response carries the
+// RateLimit-* headers; rejected requests get 429 with Retry-After and a
+// problem+json body. When the store fails the request is let through.
+func (l *Limiter) Middleware(policy string, limit Limit) gin.HandlerFunc {
+	policyHeader := Policy(limit)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		result, ok := l.Take(ctx, policy, ClientKey(ctx, c.ClientIP()), limit)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(CeilSeconds(result.Reset)))
+		header.Set("RateLimit-Policy", policyHeader)
+
+		if result.Allowed {
+			c.Next()
+			return
+		}
+
+		retryAfter := CeilSeconds(result.RetryAfter)
+		header.Set("Retry-After", strconv.Itoa(retryAfter))
+		header.Set("Content-Type", "application/problem+json")
+
+		problem := gin.H{
+			"type":     "about:blank",
+			"title":    http.StatusText(http.StatusTooManyRequests),
+			"status":   http.StatusTooManyRequests,
+			"detail":   Detail(policy, result),
+			"instance": c.Request.URL.Path,
+		}
+		if requestID := logging.RequestID(ctx); requestID != "" {
+			problem["request_id"] = requestID
+		}
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, problem)
+	}
+}
+
+// Policy describes limit as the RateLimit-Policy header does.
+func Policy(limit Limit) string {
+	return fmt.Sprintf("%d;w=%d", limit.Burst, CeilSeconds(limit.Window()))
+}
+
+// Detail explains a rejection to the client.
+func Detail(policy string, result Result) string {
+	return fmt.Sprintf("rate limit exceeded for %s requests, retry in %d seconds", policy, CeilSeconds(result.RetryAfter))
+}
+
+// ClientKey identifies the caller: the principal of ctx when authenticated,
+// the client IP otherwise.
+func ClientKey(ctx context.Context, ip string) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return "principal:" + principal.Method + "/" + principal.Subject
+	}
+	return "ip:" + ip
+}
+
+// CeilSeconds rounds up so clients never retry too early.
+func CeilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
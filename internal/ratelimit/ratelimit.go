@@ -0,0 +1,135 @@
+// Package ratelimit throttles API clients with token buckets kept in memory
+// or in Redis, so a single client cannot exhaust the music provider quotas.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst requests and refills at
+// Rate requests per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns the Limit of requestsPerMinute with the given burst.
+func PerMinute(requestsPerMinute, burst int) Limit {
+	return Limit{Rate: float64(requestsPerMinute) / 60, Burst: burst}
+}
+
+// Window is the time an empty bucket takes to fill up again.
+func (l Limit) Window() time.Duration {
+	return secondsToDuration(float64(l.Burst) / l.Rate)
+}
+
+// Result is the state of a bucket after a request took, or failed to take,
+// a token.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is the wait until the next token when the request was not
+	// allowed.
+	RetryAfter time.Duration
+	// Reset is the wait until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps the buckets. Take removes a token from the bucket of key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// result derives the Result of a bucket holding tokens after the request.
+func result(allowed bool, tokens float64, limit Limit) Result {
+	r := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		r.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	return r
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled, after which it can be
+	// dropped.
+	full time.Time
+}
+
+// sweepInterval is how often Memory drops the buckets that have refilled.
+const sweepInterval = time.Minute
+
+// Memory keeps the buckets of this instance only. Refilled buckets are
+// dropped on a periodic sweep so idle clients do not accumulate.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket)}
+}
+
+func (m *Memory) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = refill(b.tokens, now.Sub(b.updated), limit)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	r := result(allowed, b.tokens, limit)
+	b.full = now.Add(r.Reset)
+	return r, nil
+}
+
+// sweep drops the buckets that have refilled, at most once per
+// sweepInterval. A dropped bucket starts full again, so nothing changes for
+// its client.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * limit.Rate
+	}
+	return math.Min(tokens, float64(limit.Burst))
+}
@@ -0,0 +1,211 @@
+package ratelimit
+
+import (
+	"backend-test/internal/auth"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+var testStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// testBucket checks a store against a bucket of 2 requests refilled at one
+// request per second.
+func testBucket(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	limit := PerMinute(60, 2)
+
+	take := func(key string, at time.Duration) Result {
+		t.Helper()
+		result, err := store.Take(ctx, key, limit, testStart.Add(at))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return result
+	}
+
+	if r := take("a", 0); !r.Allowed || r.Remaining != 1 {
+		t.Errorf("expected the first request with 1 remaining, got %+v", r)
+	}
+	if r := take("a", 0); !r.Allowed || r.Remaining != 0 || r.Reset != 2*time.Second {
+		t.Errorf("expected the burst to be used up, got %+v", r)
+	}
+
+	r := take("a", 250*time.Millisecond)
+	if r.Allowed || r.RetryAfter != 750*time.Millisecond {
+		t.Errorf("expected a rejection with 750ms to wait, got %+v", r)
+	}
+
+	if r := take("b", 250*time.Millisecond); !r.Allowed {
+		t.Errorf("expected another client to have its own bucket, got %+v", r)
+	}
+
+	if r := take("a", time.Second); !r.Allowed || r.Remaining != 0 {
+		t.Errorf("expected one token after a second, got %+v", r)
+	}
+	if r := take("a", time.Hour); !r.Allowed || r.Remaining != 1 {
+		t.Errorf("expected the bucket to refill up to the burst only, got %+v", r)
+	}
+}
+
+func TestMemory_Take(t *testing.T) {
+	testBucket(t, NewMemory())
+}
+
+func TestMemory_SweepsRefilledBuckets(t *testing.T) {
+	store := NewMemory()
+	ctx := context.Background()
+
+	store.Take(ctx, "idle", PerMinute(60, 2), testStart)
+	for i := 0; i < 5; i++ {
+		store.Take(ctx, "busy", PerMinute(1, 5), testStart)
+	}
+	store.Take(ctx, "trigger", PerMinute(60, 2), testStart.Add(2*sweepInterval))
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("expected the refilled bucket to be dropped")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("expected the bucket still refilling to be kept")
+	}
+}
+
+func TestRedis_Take(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	testBucket(t, redisAt{NewRedis(client), server})
+
+	if ttl := server.TTL("ratelimit:b"); ttl <= 0 || ttl > 3*time.Second {
+		t.Errorf("expected the bucket to expire once refilled, got TTL %s", ttl)
+	}
+}
+
+// redisAt moves the clock of miniredis to the now of each Take, since the
+// Redis store reads the time from the server.
+type redisAt struct {
+	*Redis
+	server *miniredis.Miniredis
+}
+
+func (r redisAt) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	r.server.SetTime(now)
+	return r.Redis.Take(ctx, key, limit, now)
+}
+
+func TestRedis_TakeUsesServerClock(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(testStart)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	store := NewRedis(client)
+	ctx := context.Background()
+	limit := PerMinute(60, 1)
+
+	if r, err := store.Take(ctx, "a", limit, testStart); err != nil || !r.Allowed {
+		t.Fatalf("expected the first request to be allowed, got %+v, %v", r, err)
+	}
+	// An instance whose clock runs an hour ahead must not refill the bucket.
+	if r, err := store.Take(ctx, "a", limit, testStart.Add(time.Hour)); err != nil || r.Allowed {
+		t.Errorf("expected a rejection on the server clock, got %+v, %v", r, err)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	return Result{}, errors.New("redis: connection refused")
+}
+
+func setupRouter(store Store, now func() time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewLimiter(store, now, logging.Discard())
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if key := c.GetHeader("X-Test-Principal"); key != "" {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), domain.Principal{Subject: key, Role: domain.RoleReader, Method: "api_key"}))
+		}
+	})
+	router.GET("/suggest", limiter.Middleware("recommendation", PerMinute(30, 1)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func get(router *gin.Engine, remoteAddr, principal string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/suggest", nil)
+	req.RemoteAddr = remoteAddr
+	if principal != "" {
+		req.Header.Set("X-Test-Principal", principal)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_LimitsPerClient(t *testing.T) {
+	router := setupRouter(NewMemory(), func() time.Time { return testStart })
+
+	w := get(router, "10.0.0.1:1234", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", w.Code)
+	}
+	for header, expected := range map[string]string{
+		"RateLimit-Limit":     "1",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "1;w=2",
+	} {
+		if got := w.Header().Get(header); got != expected {
+			t.Errorf("expected %s %q, got %q", header, expected, got)
+		}
+	}
+
+	w = get(router, "10.0.0.1:5678", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for the same IP, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "2" || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("unexpected headers: %v", w.Header())
+	}
+	var problem map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("invalid problem body: %v", err)
+	}
+	if problem["status"] != float64(http.StatusTooManyRequests) || problem["title"] != "Too Many Requests" || problem["instance"] != "/suggest" {
+		t.Errorf("unexpected problem body: %v", problem)
+	}
+
+	if w := get(router, "10.0.0.2:1234", ""); w.Code != http.StatusOK {
+		t.Errorf("expected another IP to pass, got %d", w.Code)
+	}
+	if w := get(router, "10.0.0.1:1234", "key-1"); w.Code != http.StatusOK {
+		t.Errorf("expected an API key to have its own bucket, got %d", w.Code)
+	}
+	if w := get(router, "10.0.0.3:1234", "key-1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the API key bucket to follow the key across IPs, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_FailsOpen(t *testing.T) {
+	router := setupRouter(failingStore{}, nil)
+
+	w := get(router, "10.0.0.1:1234", "")
+	if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("expected the request to pass without headers, got %d %v", w.Code, w.Header())
+	}
+}
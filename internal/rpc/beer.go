@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/internal/service"
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BeerStyleServer serves the beer style CRUD with the checks of the REST
+// controller.
+type BeerStyleServer struct {
+	beerv1.UnimplementedBeerStyleServiceServer
+
+	BeerService       service.BeerServiceInterface
+	ValidationService service.ValidationServiceInterface
+	UpdateService     service.UpdateServiceInterface
+	Logger            *slog.Logger
+}
+
+func NewBeerStyleServer(beerService service.BeerServiceInterface, validationService service.ValidationServiceInterface, updateService service.UpdateServiceInterface, logger *slog.Logger) *BeerStyleServer {
+	return &BeerStyleServer{
+		BeerService:       beerService,
+		ValidationService: validationService,
+		UpdateService:     updateService,
+		Logger:            logger.With("server", "BeerStyleServer"),
+	}
+}
+
+func (s *BeerStyleServer) ListBeerStyles(ctx context.Context, request *beerv1.ListBeerStylesRequest) (*beerv1.ListBeerStylesResponse, error) {
+	beerStyles, err := s.BeerService.ListAllBeerStyles(ctx)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ListBeerStyles failed", "err", err)
+		if s.ValidationService.IsNoRowsError(err) {
+			return nil, status.Error(codes.NotFound, "no beer styles found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	response := &beerv1.ListBeerStylesResponse{BeerStyles: make([]*beerv1.BeerStyle, 0, len(beerStyles))}
+	for _, beerStyle := range beerStyles {
+		response.BeerStyles = append(response.BeerStyles, beerStyleMessage(beerStyle))
+	}
+	return response, nil
+}
+
+func (s *BeerStyleServer) GetBeerStyle(ctx context.Context, request *beerv1.GetBeerStyleRequest) (*beerv1.GetBeerStyleResponse, error) {
+	if err := s.validateUUID(request.GetUuid()); err != nil {
+		return nil, err
+	}
+
+	beerStyle, err := s.BeerService.GetBeerStyleByUUID(ctx, request.GetUuid())
+	if err != nil {
+		return nil, s.lookupError(ctx, "GetBeerStyle", request.GetUuid(), err)
+	}
+	return &beerv1.GetBeerStyleResponse{BeerStyle: beerStyleMessage(beerStyle)}, nil
+}
+
+func (s *BeerStyleServer) CreateBeerStyle(ctx context.Context, request *beerv1.CreateBeerStyleRequest) (*beerv1.CreateBeerStyleResponse, error) {
+	inputStyle := beerStyleFromCreate(request)
+	if inputStyle.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := s.ValidationService.ValidateUniqueNameForCreate(ctx, inputStyle.Name); err != nil {
+		s.Logger.WarnContext(ctx, "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, nameError(err)
+	}
+	if err := s.ValidationService.ValidateTemperatureRange(inputStyle); err != nil {
+		s.Logger.WarnContext(ctx, "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.ValidationService.ValidateABV(inputStyle.ABV); err != nil {
+		s.Logger.WarnContext(ctx, "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	newBeerStyle, err := s.BeerService.CreateBeerStyle(ctx, inputStyle)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CreateBeerStyle failed", "name", inputStyle.Name, "err", err)
+		return nil, status.Error(codes.Internal, "failed to create beer style")
+	}
+	return &beerv1.CreateBeerStyleResponse{BeerStyle: beerStyleMessage(newBeerStyle)}, nil
+}
+
+func (s *BeerStyleServer) UpdateBeerStyle(ctx context.Context, request *beerv1.UpdateBeerStyleRequest) (*beerv1.UpdateBeerStyleResponse, error) {
+	beerUUID := request.GetUuid()
+	if err := s.validateUUID(beerUUID); err != nil {
+		return nil, err
+	}
+	updates := beerStyleUpdate(request)
+
+	currentBeerStyle, err := s.BeerService.GetBeerStyleByUUID(ctx, beerUUID)
+	if err != nil {
+		return nil, s.lookupError(ctx, "UpdateBeerStyle", beerUUID, err)
+	}
+
+	if updates.Name != nil && *updates.Name != "" && *updates.Name != currentBeerStyle.Name {
+		if err := s.ValidationService.ValidateUniqueNameForUpdate(ctx, *updates.Name, currentBeerStyle.UUID); err != nil {
+			s.Logger.WarnContext(ctx, "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+			return nil, nameError(err)
+		}
+	}
+
+	if !s.UpdateService.ApplyBeerStyleUpdates(&currentBeerStyle, updates) {
+		return &beerv1.UpdateBeerStyleResponse{BeerStyle: beerStyleMessage(currentBeerStyle)}, nil
+	}
+
+	if err := s.ValidationService.ValidateTemperatureRange(currentBeerStyle); err != nil {
+		s.Logger.WarnContext(ctx, "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.ValidationService.ValidateABV(currentBeerStyle.ABV); err != nil {
+		s.Logger.WarnContext(ctx, "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	updatedBeerStyle, err := s.BeerService.UpdateBeerStyle(ctx, currentBeerStyle)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "UpdateBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, status.Error(codes.Internal, "failed to update beer style")
+	}
+	return &beerv1.UpdateBeerStyleResponse{BeerStyle: beerStyleMessage(updatedBeerStyle), Changed: true}, nil
+}
+
+func (s *BeerStyleServer) DeleteBeerStyle(ctx context.Context, request *beerv1.DeleteBeerStyleRequest) (*beerv1.DeleteBeerStyleResponse, error) {
+	beerUUID := request.GetUuid()
+	if err := s.validateUUID(beerUUID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.BeerService.GetBeerStyleByUUID(ctx, beerUUID); err != nil {
+		return nil, s.lookupError(ctx, "DeleteBeerStyle", beerUUID, err)
+	}
+
+	if err := s.BeerService.DeleteBeerStyle(ctx, beerUUID); err != nil {
+		s.Logger.ErrorContext(ctx, "DeleteBeerStyle failed", "beerUUID", beerUUID, "err", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &beerv1.DeleteBeerStyleResponse{}, nil
+}
+
+// validateUUID rejects a missing or malformed beer style UUID before it
+// reaches the database.
+func (s *BeerStyleServer) validateUUID(beerUUID string) error {
+	if beerUUID == "" {
+		return status.Error(codes.InvalidArgument, "uuid is required")
+	}
+	if err := s.ValidationService.ValidateUUID(beerUUID); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// lookupError maps the failure to load the beer style of a request.
+func (s *BeerStyleServer) lookupError(ctx context.Context, operation, beerUUID string, err error) error {
+	s.Logger.ErrorContext(ctx, operation+" failed", "beerUUID", beerUUID, "err", err)
+	if s.ValidationService.IsNoRowsError(err) {
+		return status.Error(codes.NotFound, "beer style not found")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/internal/domain"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func beerStyleMessage(beerStyle domain.BeerStyle) *beerv1.BeerStyle {
+	return &beerv1.BeerStyle{
+		Uuid:      beerStyle.UUID,
+		Name:      beerStyle.Name,
+		TempMin:   beerStyle.TempMin,
+		TempMax:   beerStyle.TempMax,
+		Category:  beerStyle.Category,
+		Abv:       beerStyle.ABV,
+		Aliases:   beerStyle.Aliases,
+		CreatedAt: timestamp(beerStyle.CreatedAt),
+		UpdatedAt: timestamp(beerStyle.UpdatedAt),
+	}
+}
+
+// timestamp leaves unset times out of the message.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func beerStyleFromCreate(request *beerv1.CreateBeerStyleRequest) domain.BeerStyle {
+	return domain.BeerStyle{
+		Name:     request.GetName(),
+		TempMin:  request.GetTempMin(),
+		TempMax:  request.GetTempMax(),
+		Category: request.GetCategory(),
+		ABV:      request.Abv,
+		Aliases:  request.GetAliases(),
+	}
+}
+
+func beerStyleUpdate(request *beerv1.UpdateBeerStyleRequest) domain.BeerStyleUpdateRequest {
+	update := domain.BeerStyleUpdateRequest{
+		Name:     request.Name,
+		TempMin:  request.TempMin,
+		TempMax:  request.TempMax,
+		Category: request.Category,
+		ABV:      request.Abv,
+	}
+	if request.Aliases != nil {
+		aliases := request.Aliases.GetValues()
+		if aliases == nil {
+			aliases = []string{}
+		}
+		update.Aliases = &aliases
+	}
+	return update
+}
+
+func temperatureRequest(request *beerv1.RecommendRequest) domain.TemperatureRequest {
+	return domain.TemperatureRequest{
+		Temperature:     request.GetTemperature(),
+		ExcludeStyles:   request.GetExcludeStyles(),
+		IncludeOnly:     request.GetIncludeOnly(),
+		MaxABV:          request.MaxAbv,
+		Categories:      request.GetCategories(),
+		MinTracks:       int(request.GetMinTracks()),
+		TrackLimit:      int(request.GetTrackLimit()),
+		Shuffle:         request.GetShuffle(),
+		ShuffleSeed:     request.ShuffleSeed,
+		DistinctArtists: request.GetDistinctArtists(),
+		Provider:        request.GetProvider(),
+	}
+}
+
+func recommendationMessage(recommendation *domain.RecommendationResponse) *beerv1.RecommendResponse {
+	playlist := recommendation.Playlist
+	tracks := make([]*beerv1.Track, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		tracks = append(tracks, &beerv1.Track{
+			Name:        track.Name,
+			Artist:      track.Artist,
+			Link:        track.Link,
+			Artists:     track.Artists,
+			Album:       track.Album,
+			DurationMs:  int32(track.DurationMs),
+			Explicit:    track.Explicit,
+			Popularity:  int32(track.Popularity),
+			PreviewUrl:  track.PreviewURL,
+			Isrc:        track.ISRC,
+			AlbumArtUrl: track.AlbumArtURL,
+		})
+	}
+
+	response := &beerv1.RecommendResponse{
+		Id:        recommendation.ID,
+		BeerStyle: recommendation.BeerStyle,
+		Playlist: &beerv1.Playlist{
+			Id:          playlist.ID,
+			Provider:    playlist.Provider,
+			Name:        playlist.Name,
+			Tracks:      tracks,
+			ShuffleSeed: playlist.ShuffleSeed,
+		},
+	}
+	if recommendation.Fallback != nil {
+		response.Fallback = &beerv1.Fallback{
+			Step:  recommendation.Fallback.Step,
+			Query: recommendation.Fallback.Query,
+		}
+	}
+	return response
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"backend-test/internal/service"
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain qualifies the reasons of the ErrorInfo details.
+const ErrorDomain = "beer.v1"
+
+// nameError maps a failed unique name check.
+func nameError(err error) error {
+	if strings.Contains(err.Error(), "already exists") {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to validate beer style name")
+}
+
+// recommendationError maps the recommendation failures as the REST
+// controller does: unsatisfiable preferences and missing playlists are
+// NotFound, provider outages Unavailable. The constraint that could not be
+// met travels in an ErrorInfo detail.
+func recommendationError(err error) error {
+	var code codes.Code
+	var message string
+
+	errorMsg := err.Error()
+	switch {
+	case strings.Contains(errorMsg, "no beer style satisfies constraint"):
+		code = codes.NotFound
+		message = errorMsg
+	case strings.Contains(errorMsg, "no playlist found"):
+		code = codes.NotFound
+		message = errorMsg
+	case strings.Contains(errorMsg, "spotify service unavailable"):
+		code = codes.Unavailable
+		message = "Spotify service is temporarily unavailable"
+	case strings.Contains(errorMsg, "service unavailable"):
+		code = codes.Unavailable
+		message = "Music provider is temporarily unavailable"
+	case strings.Contains(errorMsg, "failed to find best beer style"):
+		code = codes.Internal
+		message = "Unable to determine suitable beer style"
+	default:
+		code = codes.Internal
+		message = "Internal server error"
+	}
+
+	st := status.New(code, message)
+
+	var constraintErr *service.ConstraintError
+	if errors.As(err, &constraintErr) {
+		if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   "UNSATISFIABLE_CONSTRAINT",
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{"constraint": constraintErr.Constraint},
+		}); detailErr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
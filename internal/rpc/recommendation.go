@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/internal/service"
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecommendationServer serves the recommendations with the checks of the
+// REST controller.
+type RecommendationServer struct {
+	beerv1.UnimplementedRecommendationServiceServer
+
+	RecommendationService service.RecommendationServiceInterface
+	ValidationService     service.ValidationServiceInterface
+	Logger                *slog.Logger
+}
+
+func NewRecommendationServer(recommendationService service.RecommendationServiceInterface, validationService service.ValidationServiceInterface, logger *slog.Logger) *RecommendationServer {
+	return &RecommendationServer{
+		RecommendationService: recommendationService,
+		ValidationService:     validationService,
+		Logger:                logger.With("server", "RecommendationServer"),
+	}
+}
+
+func (s *RecommendationServer) Recommend(ctx context.Context, request *beerv1.RecommendRequest) (*beerv1.RecommendResponse, error) {
+	temperatureRequest := temperatureRequest(request)
+
+	if err := s.ValidationService.ValidateTemperatureInput(temperatureRequest.Temperature); err != nil {
+		s.Logger.WarnContext(ctx, "Recommend failed", "temperature", temperatureRequest.Temperature, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.ValidationService.ValidateRecommendationPreferences(temperatureRequest); err != nil {
+		s.Logger.WarnContext(ctx, "Recommend failed", "temperature", temperatureRequest.Temperature, "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	recommendation, err := s.RecommendationService.GetRecommendationForTemperature(ctx, temperatureRequest)
+	if err != nil {
+		s.Logger.WarnContext(ctx, "Recommend failed", "temperature", temperatureRequest.Temperature, "err", err)
+		return nil, recommendationError(err)
+	}
+	return recommendationMessage(recommendation), nil
+}
@@ -0,0 +1,374 @@
+package rpc
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/internal/access"
+	"backend-test/internal/auth"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/ratelimit"
+	"backend-test/internal/service"
+	"backend-test/internal/storage/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type memoryBeerRepository struct {
+	styles []domain.BeerStyle
+}
+
+func (m *memoryBeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return m.styles, nil
+}
+
+func (m *memoryBeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	for _, style := range m.styles {
+		if style.UUID == beerUUID {
+			return style, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	beerStyle.UUID = fmt.Sprintf("00000000-0000-4000-8000-%012d", len(m.styles)+1)
+	beerStyle.CreatedAt = testNow
+	beerStyle.UpdatedAt = testNow
+	m.styles = append(m.styles, beerStyle)
+	return beerStyle, nil
+}
+
+func (m *memoryBeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	for i := range m.styles {
+		if m.styles[i].UUID == beerStyle.UUID {
+			m.styles[i] = beerStyle
+			return beerStyle, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	for i := range m.styles {
+		if m.styles[i].UUID == beerUUID {
+			m.styles = append(m.styles[:i], m.styles[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+var _ repository.BeerRepositoryInterface = (*memoryBeerRepository)(nil)
+
+type mockRecommendationService struct {
+	request  domain.TemperatureRequest
+	response *domain.RecommendationResponse
+	err      error
+}
+
+func (m *mockRecommendationService) GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
+	m.request = request
+	return m.response, m.err
+}
+
+func (m *mockRecommendationService) ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	return nil, m.err
+}
+
+// apiKeys authenticates the keys named after their role.
+type apiKeys struct{}
+
+func (apiKeys) AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error) {
+	role := domain.Role(key)
+	if !role.Valid() {
+		return domain.Principal{}, service.ErrInvalidAPIKey
+	}
+	return domain.Principal{Subject: key, Role: role, Method: "api_key"}, nil
+}
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type testClients struct {
+	beerStyles      beerv1.BeerStyleServiceClient
+	recommendations beerv1.RecommendationServiceClient
+	conn            *grpc.ClientConn
+}
+
+func setupServer(t *testing.T, recommendationService service.RecommendationServiceInterface, configure func(*Access)) testClients {
+	t.Helper()
+	logger := logging.Discard()
+
+	beerService := service.NewBeerService(&memoryBeerRepository{styles: []domain.BeerStyle{
+		{UUID: "style-ipa", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale", CreatedAt: testNow, UpdatedAt: testNow},
+	}})
+	validationService := service.NewValidationService(beerService)
+
+	rules := Access{
+		Authenticator: auth.NewAuthenticator(apiKeys{}, nil, logger),
+		Rules: access.Rules{
+			Read:      access.Rule{Policy: "read"},
+			Write:     access.Rule{Role: domain.RoleEditor, Policy: "write"},
+			Recommend: access.Rule{Policy: "recommendation"},
+		},
+	}
+	if configure != nil {
+		configure(&rules)
+	}
+
+	server := NewServer(Servers{
+		BeerStyle:      NewBeerStyleServer(beerService, validationService, service.NewUpdateService(), logger),
+		Recommendation: NewRecommendationServer(recommendationService, validationService, logger),
+	}, rules, true, logger)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return testClients{
+		beerStyles:      beerv1.NewBeerStyleServiceClient(conn),
+		recommendations: beerv1.NewRecommendationServiceClient(conn),
+		conn:            conn,
+	}
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func expectCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestBeerStyleServer_CRUD(t *testing.T) {
+	clients := setupServer(t, &mockRecommendationService{}, nil)
+	editor := withKey("editor")
+
+	var header metadata.MD
+	list, err := clients.beerStyles.ListBeerStyles(context.Background(), &beerv1.ListBeerStylesRequest{}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("expected anonymous reads to pass, got %v", err)
+	}
+	if len(list.BeerStyles) != 1 || list.BeerStyles[0].Name != "IPA" || !list.BeerStyles[0].CreatedAt.AsTime().Equal(testNow) {
+		t.Errorf("unexpected list %v", list.BeerStyles)
+	}
+	if len(header.Get("x-request-id")) != 1 {
+		t.Errorf("expected a request ID header, got %v", header)
+	}
+
+	abv := 8.5
+	created, err := clients.beerStyles.CreateBeerStyle(editor, &beerv1.CreateBeerStyleRequest{
+		Name: "Dubbel", TempMin: 8, TempMax: 12, Abv: &abv, Aliases: []string{"Abbey Dubbel"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	uuid := created.BeerStyle.Uuid
+	if uuid == "" || created.BeerStyle.GetAbv() != 8.5 || len(created.BeerStyle.Aliases) != 1 {
+		t.Errorf("unexpected created style %v", created.BeerStyle)
+	}
+
+	_, err = clients.beerStyles.CreateBeerStyle(editor, &beerv1.CreateBeerStyleRequest{Name: "Dubbel", TempMin: 8, TempMax: 12})
+	expectCode(t, err, codes.AlreadyExists)
+	_, err = clients.beerStyles.CreateBeerStyle(editor, &beerv1.CreateBeerStyleRequest{Name: "Tripel", TempMin: 12, TempMax: 8})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = clients.beerStyles.CreateBeerStyle(editor, &beerv1.CreateBeerStyleRequest{TempMin: 8, TempMax: 12})
+	expectCode(t, err, codes.InvalidArgument)
+
+	tempMax := 14.0
+	updated, err := clients.beerStyles.UpdateBeerStyle(editor, &beerv1.UpdateBeerStyleRequest{
+		Uuid: uuid, TempMax: &tempMax, Aliases: &beerv1.Aliases{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !updated.Changed || updated.BeerStyle.TempMax != 14 || updated.BeerStyle.TempMin != 8 || len(updated.BeerStyle.Aliases) != 0 {
+		t.Errorf("expected only the set fields to change, got %v", updated)
+	}
+
+	unchanged, err := clients.beerStyles.UpdateBeerStyle(editor, &beerv1.UpdateBeerStyleRequest{Uuid: uuid, TempMax: &tempMax})
+	if err != nil || unchanged.Changed {
+		t.Errorf("expected no change, got %v %v", unchanged, err)
+	}
+
+	if _, err := clients.beerStyles.DeleteBeerStyle(editor, &beerv1.DeleteBeerStyleRequest{Uuid: uuid}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err = clients.beerStyles.GetBeerStyle(context.Background(), &beerv1.GetBeerStyleRequest{Uuid: uuid})
+	expectCode(t, err, codes.NotFound)
+	_, err = clients.beerStyles.GetBeerStyle(context.Background(), &beerv1.GetBeerStyleRequest{})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = clients.beerStyles.GetBeerStyle(context.Background(), &beerv1.GetBeerStyleRequest{Uuid: "not-a-uuid"})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = clients.beerStyles.UpdateBeerStyle(editor, &beerv1.UpdateBeerStyleRequest{Uuid: "not-a-uuid", TempMax: &tempMax})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = clients.beerStyles.DeleteBeerStyle(editor, &beerv1.DeleteBeerStyleRequest{Uuid: "not-a-uuid"})
+	expectCode(t, err, codes.InvalidArgument)
+}
+
+func TestAccess(t *testing.T) {
+	clients := setupServer(t, &mockRecommendationService{}, func(a *Access) {
+		a.Rules.Read.Role = domain.RoleReader
+	})
+	request := &beerv1.DeleteBeerStyleRequest{Uuid: "style-ipa"}
+
+	_, err := clients.beerStyles.DeleteBeerStyle(context.Background(), request)
+	expectCode(t, err, codes.Unauthenticated)
+	_, err = clients.beerStyles.DeleteBeerStyle(withKey("not-a-key"), request)
+	expectCode(t, err, codes.Unauthenticated)
+	_, err = clients.beerStyles.DeleteBeerStyle(withKey("reader"), request)
+	expectCode(t, err, codes.PermissionDenied)
+
+	_, err = clients.beerStyles.ListBeerStyles(context.Background(), &beerv1.ListBeerStylesRequest{})
+	expectCode(t, err, codes.Unauthenticated)
+	bearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer bk_unknown")
+	_, err = clients.beerStyles.ListBeerStyles(bearer, &beerv1.ListBeerStylesRequest{})
+	expectCode(t, err, codes.Unauthenticated)
+	if _, err := clients.beerStyles.ListBeerStyles(withKey("reader"), &beerv1.ListBeerStylesRequest{}); err != nil {
+		t.Errorf("expected the reader role to list, got %v", err)
+	}
+}
+
+func TestAccess_RateLimits(t *testing.T) {
+	clients := setupServer(t, &mockRecommendationService{}, func(a *Access) {
+		a.Checker = access.NewChecker(ratelimit.NewLimiter(ratelimit.NewMemory(), func() time.Time { return testNow }, logging.Discard()))
+		a.Rules.Read.Limit = ratelimit.PerMinute(30, 1)
+	})
+
+	var header metadata.MD
+	if _, err := clients.beerStyles.ListBeerStyles(context.Background(), &beerv1.ListBeerStylesRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("expected the first call to pass, got %v", err)
+	}
+	if got := header.Get("ratelimit-remaining"); len(got) != 1 || got[0] != "0" {
+		t.Errorf("expected the remaining calls in the header, got %v", header)
+	}
+
+	_, err := clients.beerStyles.ListBeerStyles(context.Background(), &beerv1.ListBeerStylesRequest{}, grpc.Header(&header))
+	expectCode(t, err, codes.ResourceExhausted)
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "2" {
+		t.Errorf("expected retry-after 2, got %v", header)
+	}
+
+	if _, err := clients.beerStyles.ListBeerStyles(withKey("reader"), &beerv1.ListBeerStylesRequest{}); err != nil {
+		t.Errorf("expected an API key to have its own bucket, got %v", err)
+	}
+}
+
+func TestRecommendationServer_Recommend(t *testing.T) {
+	seed := int64(42)
+	recommendations := &mockRecommendationService{response: &domain.RecommendationResponse{
+		ID:        "4f0e8a52-0000-4000-8000-000000000001",
+		BeerStyle: "IPA",
+		Playlist: domain.PlaylistInfo{
+			ID: "p1", Provider: "deezer", Name: "Hoppy", ShuffleSeed: &seed,
+			Tracks: []domain.TrackInfo{{Name: "Song", Artist: "Band", Link: "https://example.com/song", DurationMs: 180000}},
+		},
+		Fallback: &domain.FallbackInfo{Step: "alias", Query: "India Pale Ale"},
+	}}
+	clients := setupServer(t, recommendations, nil)
+
+	maxABV := 6.0
+	response, err := clients.recommendations.Recommend(context.Background(), &beerv1.RecommendRequest{
+		Temperature: 8, MaxAbv: &maxABV, TrackLimit: 5, Shuffle: true, ShuffleSeed: &seed,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.Id != "4f0e8a52-0000-4000-8000-000000000001" || response.BeerStyle != "IPA" || response.Playlist.GetShuffleSeed() != 42 || len(response.Playlist.Tracks) != 1 ||
+		response.Playlist.Tracks[0].DurationMs != 180000 || response.Fallback.GetStep() != "alias" {
+		t.Errorf("unexpected response %v", response)
+	}
+	request := recommendations.request
+	if request.Temperature != 8 || *request.MaxABV != 6 || request.TrackLimit != 5 || !request.Shuffle || *request.ShuffleSeed != 42 {
+		t.Errorf("unexpected request %+v", request)
+	}
+
+	_, err = clients.recommendations.Recommend(context.Background(), &beerv1.RecommendRequest{Temperature: 100})
+	expectCode(t, err, codes.InvalidArgument)
+}
+
+func TestRecommendationServer_MapsErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		err        error
+		code       codes.Code
+		constraint string
+	}{
+		{"constraint", &service.ConstraintError{Constraint: "max_abv", Reason: "too low"}, codes.NotFound, "max_abv"},
+		{"no playlist", fmt.Errorf("no playlist found for beer style 'IPA'"), codes.NotFound, ""},
+		{"provider down", fmt.Errorf("deezer service unavailable"), codes.Unavailable, ""},
+		{"unexpected", fmt.Errorf("boom"), codes.Internal, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := setupServer(t, &mockRecommendationService{err: tt.err}, nil)
+
+			_, err := clients.recommendations.Recommend(context.Background(), &beerv1.RecommendRequest{Temperature: 8})
+			st := status.Convert(err)
+			if st.Code() != tt.code {
+				t.Fatalf("expected %s, got %v", tt.code, err)
+			}
+
+			var constraint string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+					constraint = info.Metadata["constraint"]
+				}
+			}
+			if constraint != tt.constraint {
+				t.Errorf("expected constraint %q, got %q", tt.constraint, constraint)
+			}
+		})
+	}
+}
+
+func TestNewServer_Reflection(t *testing.T) {
+	clients := setupServer(t, &mockRecommendationService{}, nil)
+
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(clients.conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	services := map[string]bool{}
+	for _, svc := range response.GetListServicesResponse().GetService() {
+		services[svc.Name] = true
+	}
+	if !services["beer.v1.BeerStyleService"] || !services["beer.v1.RecommendationService"] {
+		t.Errorf("expected both services to be listed, got %v", services)
+	}
+}
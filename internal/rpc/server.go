@@ -0,0 +1,234 @@
+// Package rpc serves the beer styles and recommendations over gRPC, next to
+// the REST API, with the same services, authentication and rate limits.
+package rpc
+
+import (
+	beerv1 "backend-test/api/beer/v1"
+	"backend-test/internal/access"
+	"backend-test/internal/auth"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/logging"
+	"backend-test/internal/ratelimit"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Servers are the services registered by NewServer.
+type Servers struct {
+	BeerStyle      *BeerStyleServer
+	Recommendation *RecommendationServer
+}
+
+// Access authenticates the callers and holds the rules of reads, writes and
+// recommendations.
+type Access struct {
+	Authenticator *auth.Authenticator
+	Checker       *access.Checker
+	Rules         access.Rules
+}
+
+// NewAccess applies the roles and rate limits of the REST routes, as
+// access.NewRules describes them.
+func NewAccess(authenticator *auth.Authenticator, limiter *ratelimit.Limiter, authCfg config.AuthConfig, rateLimitCfg config.RateLimitConfig) Access {
+	return Access{
+		Authenticator: authenticator,
+		Checker:       access.NewChecker(limiter),
+		Rules:         access.NewRules(authCfg, rateLimitCfg),
+	}
+}
+
+// rules maps each RPC to the Rule guarding it. RPCs missing from it, such as
+// server reflection, are open.
+func (a Access) rules() map[string]access.Rule {
+	return map[string]access.Rule{
+		beerv1.BeerStyleService_ListBeerStyles_FullMethodName:  a.Rules.Read,
+		beerv1.BeerStyleService_GetBeerStyle_FullMethodName:    a.Rules.Read,
+		beerv1.BeerStyleService_CreateBeerStyle_FullMethodName: a.Rules.Write,
+		beerv1.BeerStyleService_UpdateBeerStyle_FullMethodName: a.Rules.Write,
+		beerv1.BeerStyleService_DeleteBeerStyle_FullMethodName: a.Rules.Write,
+		beerv1.RecommendationService_Recommend_FullMethodName:  a.Rules.Recommend,
+	}
+}
+
+// NewServer returns the gRPC server with the services registered and, when
+// enabled, server reflection. Every call gets a request ID, an access log
+// line and panic recovery before the access checks run.
+func NewServer(servers Servers, access Access, reflectionEnabled bool, logger *slog.Logger) *grpc.Server {
+	logger = logger.With("component", "grpc")
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		requestLog(logger),
+		recovery(logger),
+		access.interceptor(),
+	))
+	beerv1.RegisterBeerStyleServiceServer(server, servers.BeerStyle)
+	beerv1.RegisterRecommendationServiceServer(server, servers.Recommendation)
+	if reflectionEnabled {
+		reflection.Register(server)
+	}
+	return server
+}
+
+// Shutdown stops the server gracefully and cancels the calls still running
+// once ctx is done.
+func Shutdown(server *grpc.Server) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// validRequestID bounds the IDs accepted from clients, as the HTTP
+// middleware does.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDKey is the metadata key of the request ID, lowercase as gRPC
+// requires.
+const requestIDKey = "x-request-id"
+
+// requestLog propagates or generates the request ID, echoes it in the
+// response header and logs one line per call.
+func requestLog(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		requestID := firstValue(ctx, requestIDKey)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx = logging.WithRequestID(ctx, requestID)
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+		response, err := handler(ctx, request)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		logger.Log(ctx, level, "rpc completed",
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("client_ip", clientIP(ctx)),
+		)
+		return response, err
+	}
+}
+
+// recovery turns a panicking call into Internal instead of crashing the
+// process.
+func recovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.ErrorContext(ctx, "rpc panicked", "method", info.FullMethod, "panic", recovered)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, request)
+	}
+}
+
+// interceptor authenticates the x-api-key or authorization metadata, then
+// enforces the role and the rate limit of the RPC. Like the HTTP middleware,
+// invalid credentials are always rejected, and rate limited calls get the
+// RateLimit-* values as header metadata.
+func (a Access) interceptor() grpc.UnaryServerInterceptor {
+	rules := a.rules()
+
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method, credential := auth.Credentials(firstValue(ctx, "x-api-key"), firstValue(ctx, "authorization"))
+		if credential != "" {
+			principal, err := a.Authenticator.Authenticate(ctx, method, credential)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidCredentials) {
+					return nil, status.Error(codes.Internal, "failed to authenticate")
+				}
+				return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+			}
+			ctx = auth.WithPrincipal(ctx, principal)
+		}
+
+		rule, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(ctx, request)
+		}
+
+		quota, err := a.Checker.Check(ctx, rule, clientIP(ctx))
+		if quota != nil {
+			header := metadata.Pairs(
+				"ratelimit-limit", strconv.Itoa(quota.Limit.Burst),
+				"ratelimit-remaining", strconv.Itoa(quota.Result.Remaining),
+				"ratelimit-reset", strconv.Itoa(ratelimit.CeilSeconds(quota.Result.Reset)),
+				"ratelimit-policy", ratelimit.Policy(quota.Limit),
+			)
+			if !quota.Result.Allowed {
+				header.Set("retry-after", strconv.Itoa(ratelimit.CeilSeconds(quota.Result.RetryAfter)))
+			}
+			grpc.SetHeader(ctx, header)
+		}
+		var limitErr *access.LimitError
+		var roleErr *access.RoleError
+		switch {
+		case errors.As(err, &limitErr):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, access.ErrAuthenticationRequired):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.As(err, &roleErr):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// clientIP is the host of the peer address, or the whole address when it
+// has no port, as with in-memory listeners.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
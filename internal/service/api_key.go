@@ -0,0 +1,112 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/storage/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// apiKeyPrefix marks the keys issued by the API, so leaked keys are easy to
+// recognize in scanners and logs.
+const apiKeyPrefix = "bk_"
+
+// ErrInvalidAPIKey is returned for unknown and revoked keys.
+var ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+
+type APIKeyService struct {
+	apiKeyRepository repository.APIKeyRepositoryInterface
+	bootstrapKeyHash string
+	logger           *slog.Logger
+}
+
+// NewAPIKeyService returns the API key service. bootstrapKey, when set, is
+// accepted as an admin key without being stored.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepositoryInterface, bootstrapKey string, logger *slog.Logger) *APIKeyService {
+	service := &APIKeyService{
+		apiKeyRepository: apiKeyRepo,
+		logger:           logger.With("service", "APIKeyService"),
+	}
+	if bootstrapKey != "" {
+		service.bootstrapKeyHash = hashAPIKey(bootstrapKey)
+	}
+	return service
+}
+
+// CreateAPIKey issues a random key for request. The plain key is only part
+// of the returned value.
+func (as *APIKeyService) CreateAPIKey(ctx context.Context, request domain.APIKeyRequest) (domain.CreatedAPIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.CreatedAPIKey{}, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	created, err := as.apiKeyRepository.CreateAPIKey(ctx, domain.APIKey{
+		Name:    strings.TrimSpace(request.Name),
+		Prefix:  key[:len(apiKeyPrefix)+8],
+		KeyHash: hashAPIKey(key),
+		Role:    string(request.Role),
+	})
+	if err != nil {
+		return domain.CreatedAPIKey{}, err
+	}
+
+	as.logger.InfoContext(ctx, "API key created", "uuid", created.UUID, "prefix", created.Prefix, "role", created.Role)
+	return domain.CreatedAPIKey{APIKey: created, Key: key}, nil
+}
+
+func (as *APIKeyService) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	apiKeys, err := as.apiKeyRepository.ListAPIKeys(ctx)
+	if err != nil {
+		return []domain.APIKey{}, err
+	}
+	return apiKeys, nil
+}
+
+func (as *APIKeyService) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	if err := as.apiKeyRepository.RevokeAPIKey(ctx, keyUUID); err != nil {
+		return err
+	}
+
+	as.logger.InfoContext(ctx, "API key revoked", "uuid", keyUUID)
+	return nil
+}
+
+// AuthenticateAPIKey returns the principal of an active key, or
+// ErrInvalidAPIKey.
+func (as *APIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error) {
+	keyHash := hashAPIKey(key)
+
+	if as.bootstrapKeyHash != "" && subtle.ConstantTimeCompare([]byte(keyHash), []byte(as.bootstrapKeyHash)) == 1 {
+		return domain.Principal{Subject: "bootstrap", Role: domain.RoleAdmin, Method: "api_key"}, nil
+	}
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return domain.Principal{}, ErrInvalidAPIKey
+	}
+
+	apiKey, err := as.apiKeyRepository.GetActiveAPIKeyByHash(ctx, keyHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Principal{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	return domain.Principal{Subject: "api_key:" + apiKey.UUID, Role: domain.Role(apiKey.Role), Method: "api_key"}, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubAPIKeyRepository struct {
+	keys []domain.APIKey
+}
+
+func (s *stubAPIKeyRepository) CreateAPIKey(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	apiKey.UUID = "key-uuid"
+	s.keys = append(s.keys, apiKey)
+	return apiKey, nil
+}
+
+func (s *stubAPIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	for _, apiKey := range s.keys {
+		if apiKey.KeyHash == keyHash {
+			return apiKey, nil
+		}
+	}
+	return domain.APIKey{}, sql.ErrNoRows
+}
+
+func (s *stubAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return s.keys, nil
+}
+
+func (s *stubAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	for i, apiKey := range s.keys {
+		if apiKey.UUID == keyUUID {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	repo := &stubAPIKeyRepository{}
+	service := NewAPIKeyService(repo, "", logging.Discard())
+	ctx := context.Background()
+
+	created, err := service.CreateAPIKey(ctx, domain.APIKeyRequest{Name: " ci ", Role: domain.RoleEditor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(created.Key, "bk_") || !strings.HasPrefix(created.Key, created.Prefix) {
+		t.Errorf("Expected a bk_ key starting with its prefix, got %s / %s", created.Key, created.Prefix)
+	}
+	if created.Name != "ci" {
+		t.Errorf("Expected the trimmed name, got '%s'", created.Name)
+	}
+	if strings.Contains(repo.keys[0].KeyHash, created.Key) || len(repo.keys[0].KeyHash) != 64 {
+		t.Errorf("Expected only the SHA-256 hash to be stored, got '%s'", repo.keys[0].KeyHash)
+	}
+
+	principal, err := service.AuthenticateAPIKey(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if principal.Role != domain.RoleEditor || principal.Subject != "api_key:key-uuid" {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+
+	if err := service.RevokeAPIKey(ctx, created.UUID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.AuthenticateAPIKey(ctx, created.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey after revocation, got %v", err)
+	}
+}
+
+func TestAPIKeyService_BootstrapKey(t *testing.T) {
+	service := NewAPIKeyService(&stubAPIKeyRepository{}, "bootstrap-admin-key-with-32-characters", logging.Discard())
+
+	principal, err := service.AuthenticateAPIKey(context.Background(), "bootstrap-admin-key-with-32-characters")
+	if err != nil || principal.Role != domain.RoleAdmin {
+		t.Errorf("Expected the bootstrap key to be an admin, got %+v, %v", principal, err)
+	}
+
+	if _, err := service.AuthenticateAPIKey(context.Background(), "bootstrap"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"backend-test/internal/domain"
 	"backend-test/internal/storage/repository"
+	"context"
 )
 
 type BeerService struct {
@@ -15,40 +16,40 @@ func NewBeerService(beerRepo repository.BeerRepositoryInterface) *BeerService {
 	}
 }
 
-func (bs BeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
-	beerStyles, err := bs.beerRepository.ListAllBeerStyles()
+func (bs BeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	beerStyles, err := bs.beerRepository.ListAllBeerStyles(ctx)
 	if err != nil {
 		return []domain.BeerStyle{}, err
 	}
 	return beerStyles, nil
 }
 
-func (bs BeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
-	beerStyle, err := bs.beerRepository.GetBeerStyleByUUID(beerUUID)
+func (bs BeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	beerStyle, err := bs.beerRepository.GetBeerStyleByUUID(ctx, beerUUID)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return beerStyle, nil
 }
 
-func (bs BeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	updatedBeerStyle, err := bs.beerRepository.UpdateBeerStyle(beerStyle)
+func (bs BeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	updatedBeerStyle, err := bs.beerRepository.UpdateBeerStyle(ctx, beerStyle)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return updatedBeerStyle, nil
 }
 
-func (bs BeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	createdBeerStyle, err := bs.beerRepository.CreateBeerStyle(beerStyle)
+func (bs BeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	createdBeerStyle, err := bs.beerRepository.CreateBeerStyle(ctx, beerStyle)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return createdBeerStyle, nil
 }
 
-func (bs BeerService) DeleteBeerStyle(beerUUID string) error {
-	err := bs.beerRepository.DeleteBeerStyle(beerUUID)
+func (bs BeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	err := bs.beerRepository.DeleteBeerStyle(ctx, beerUUID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"fmt"
+	"strconv"
+)
+
+// rankingStrategy is a ranking replayed by EvaluateRankings: the distance
+// ranking has no weight.
+type rankingStrategy struct {
+	name   string
+	weight float64
+}
+
+// rankingScore accumulates the metrics of a strategy.
+type rankingScore struct {
+	evaluation  domain.RankingEvaluation
+	reciprocal  float64
+	rankedWants int
+}
+
+// EvaluateRankings replays ratings, oldest first, against the distance
+// ranking of styles and the feedback ranking with each of weights. Each
+// rating is ranked with the feedback given before it in its temperature
+// bucket of bucketSize degrees, as the service would have ranked it then,
+// and then added to the tallies.
+//
+// Styles are matched by UUID. The styles are the current catalog: ratings
+// whose wanted or rejected style is unknown or no longer in it are skipped.
+func EvaluateRankings(styles []domain.BeerStyle, ratings []domain.RatedRecommendation, weights []float64, bucketSize float64) []domain.RankingEvaluation {
+	strategies := []rankingStrategy{{name: RankingDistance}}
+	for _, weight := range weights {
+		strategies = append(strategies, rankingStrategy{
+			name:   fmt.Sprintf("%s (weight %s)", RankingFeedback, strconv.FormatFloat(weight, 'g', -1, 64)),
+			weight: weight,
+		})
+	}
+	scores := make([]rankingScore, len(strategies))
+
+	catalog := make(map[string]bool, len(styles))
+	for _, style := range styles {
+		catalog[style.UUID] = true
+	}
+
+	tallies := map[float64]map[string]domain.FeedbackTally{}
+	for _, rating := range ratings {
+		bucket := TemperatureBucket(rating.Temperature, bucketSize)
+		if tallies[bucket] == nil {
+			tallies[bucket] = map[string]domain.FeedbackTally{}
+		}
+
+		wanted, rejected := ratingTarget(rating)
+		if !catalog[wanted] && !catalog[rejected] {
+			for i := range scores {
+				scores[i].evaluation.Skipped++
+			}
+		} else {
+			feedback := make(map[string]float64, len(tallies[bucket]))
+			for uuid, tally := range tallies[bucket] {
+				feedback[uuid] = FeedbackScore(tally)
+			}
+
+			for i, strategy := range strategies {
+				var ranked []domain.RankedBeerStyle
+				if strategy.weight == 0 {
+					ranked = RankBeerStyles(styles, rating.Temperature)
+				} else {
+					ranked = RankBeerStylesWithFeedback(styles, rating.Temperature, feedback, strategy.weight)
+				}
+				scores[i].add(ranked, wanted, rejected)
+			}
+		}
+
+		if recommended := rating.BeerStyleUUID; recommended != "" {
+			tally := tallies[bucket][recommended]
+			if rating.Vote == domain.VoteUp {
+				tally.Up++
+			} else {
+				tally.Down++
+			}
+			tallies[bucket][recommended] = tally
+		}
+		if preferred := rating.PreferredStyleUUID; preferred != "" {
+			tally := tallies[bucket][preferred]
+			tally.Preferred++
+			tallies[bucket][preferred] = tally
+		}
+	}
+
+	evaluations := make([]domain.RankingEvaluation, 0, len(strategies))
+	for i, strategy := range strategies {
+		evaluation := scores[i].evaluation
+		evaluation.Strategy = strategy.name
+		if evaluation.Evaluated > 0 {
+			evaluation.Accuracy = float64(evaluation.Hits) / float64(evaluation.Evaluated)
+		}
+		if scores[i].rankedWants > 0 {
+			evaluation.MeanReciprocalRank = scores[i].reciprocal / float64(scores[i].rankedWants)
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations
+}
+
+// ratingTarget returns, by UUID, the style the drinker wanted or, for a down
+// vote without a preferred style, the one rejected.
+func ratingTarget(rating domain.RatedRecommendation) (wanted, rejected string) {
+	switch {
+	case rating.Vote == domain.VoteUp:
+		return rating.BeerStyleUUID, ""
+	case rating.PreferredStyleUUID != "":
+		return rating.PreferredStyleUUID, ""
+	default:
+		return "", rating.BeerStyleUUID
+	}
+}
+
+func (s *rankingScore) add(ranked []domain.RankedBeerStyle, wanted, rejected string) {
+	s.evaluation.Evaluated++
+	if rejected != "" {
+		if ranked[0].Style.UUID != rejected {
+			s.evaluation.Hits++
+		}
+		return
+	}
+
+	for _, candidate := range ranked {
+		if candidate.Style.UUID == wanted {
+			if candidate.Rank == 1 {
+				s.evaluation.Hits++
+			}
+			s.reciprocal += 1 / float64(candidate.Rank)
+			s.rankedWants++
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"math"
+	"testing"
+)
+
+func TestRankBeerStylesWithFeedback(t *testing.T) {
+	styles := []domain.BeerStyle{
+		{UUID: "stout-uuid", Name: "Stout", TempMin: 10, TempMax: 13},
+		{UUID: "ipa-uuid", Name: "IPA", TempMin: 7, TempMax: 10},
+		{UUID: "lager-uuid", Name: "Lager", TempMin: -2, TempMax: 4},
+	}
+
+	// Scores are keyed by UUID, never by name.
+	ranked := RankBeerStylesWithFeedback(styles, 10, map[string]float64{"stout-uuid": 0.25, "lager-uuid": 1, "ipa": 1}, 2)
+
+	var names []string
+	for _, candidate := range ranked {
+		names = append(names, candidate.Style.Name)
+	}
+	if names[0] != "Stout" || names[1] != "IPA" || names[2] != "Lager" {
+		t.Fatalf("expected Stout, IPA, Lager, got %v", names)
+	}
+	if ranked[0].Distance != 1.5 || ranked[0].FeedbackScore != 0.25 || ranked[0].AdjustedDistance != 1 || ranked[0].Rank != 1 {
+		t.Errorf("unexpected first candidate: %+v", ranked[0])
+	}
+	if ranked[1].AdjustedDistance != ranked[1].Distance {
+		t.Errorf("expected no adjustment without a score, got %+v", ranked[1])
+	}
+}
+
+func TestEvaluateRankings(t *testing.T) {
+	styles := []domain.BeerStyle{
+		{UUID: "ipa-uuid", Name: "IPA", TempMin: 7, TempMax: 10},
+		{UUID: "stout-uuid", Name: "Stout", TempMin: 10, TempMax: 13},
+	}
+	// At 10°C the distance ranking always puts IPA first, while drinkers
+	// keep asking for Stout, recorded under its former name "Dry Stout" at
+	// first.
+	ratings := []domain.RatedRecommendation{
+		{Temperature: 10, BeerStyleUUID: "ipa-uuid", BeerStyle: "IPA", Vote: domain.VoteDown, PreferredStyleUUID: "stout-uuid", PreferredStyle: "Dry Stout"},
+		{Temperature: 10, BeerStyleUUID: "ipa-uuid", BeerStyle: "IPA", Vote: domain.VoteDown, PreferredStyleUUID: "stout-uuid", PreferredStyle: "Stout"},
+		{Temperature: 10, BeerStyleUUID: "stout-uuid", BeerStyle: "Stout", Vote: domain.VoteUp},
+		{Temperature: 10, BeerStyleUUID: "ipa-uuid", BeerStyle: "IPA", Vote: domain.VoteDown},
+		{Temperature: 10, BeerStyleUUID: "ipa-uuid", BeerStyle: "IPA", Vote: domain.VoteDown, PreferredStyleUUID: "mead-uuid", PreferredStyle: "Mead"},
+	}
+
+	evaluations := EvaluateRankings(styles, ratings, []float64{2}, 5)
+	if len(evaluations) != 2 {
+		t.Fatalf("expected the distance and one feedback strategy, got %+v", evaluations)
+	}
+
+	distance, feedback := evaluations[0], evaluations[1]
+	if distance.Strategy != "distance" || distance.Evaluated != 4 || distance.Skipped != 1 || distance.Hits != 0 || distance.MeanReciprocalRank != 0.5 {
+		t.Errorf("unexpected distance evaluation: %+v", distance)
+	}
+	// The first rating is ranked without feedback and misses.
+	if feedback.Strategy != "feedback (weight 2)" || feedback.Hits != 3 || feedback.Accuracy != 0.75 || math.Abs(feedback.MeanReciprocalRank-2.5/3) > 1e-9 {
+		t.Errorf("unexpected feedback evaluation: %+v", feedback)
+	}
+}
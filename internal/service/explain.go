@@ -0,0 +1,155 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExplainRecommendation reports how GetRecommendationForTemperature decides
+// for request: every candidate with its midpoint, distance and rank, the
+// styles the preferences leave out and the playlist search. The music
+// provider is only called when resolvePlaylist is set; otherwise the
+// search is the plan of pinned playlists and queries the chain would try.
+//
+// A request that no style satisfies is explained rather than failed, with
+// the constraint that emptied the candidates.
+func (rs *RecommendationService) ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (explanation *domain.RecommendationExplanation, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RecommendationService.ExplainRecommendation",
+		trace.WithAttributes(
+			attribute.Float64("beer.temperature", request.Temperature),
+			attribute.Bool("playlist.resolve", resolvePlaylist),
+		))
+	defer func() { tracing.End(span, err) }()
+
+	allBeerStyles, err := rs.beerService.ListAllBeerStyles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get beer styles: %w", err)
+	}
+
+	explanation = &domain.RecommendationExplanation{
+		Temperature: request.Temperature,
+		Ranking:     rs.ranking,
+		Candidates:  []domain.CandidateExplanation{},
+		Excluded:    []domain.ExcludedBeerStyle{},
+	}
+	if len(allBeerStyles) == 0 {
+		explanation.Error = "no beer styles found"
+		return explanation, nil
+	}
+
+	candidates, excluded, err := filterPreferences(allBeerStyles, request)
+	explanation.Excluded = append(explanation.Excluded, excluded...)
+	var constraintErr *ConstraintError
+	if errors.As(err, &constraintErr) {
+		explanation.Constraint = constraintErr.Constraint
+		explanation.Error = constraintErr.Error()
+		return explanation, nil
+	}
+
+	ranked, ranking := rs.rank(ctx, candidates, request.Temperature)
+	explanation.Ranking = ranking
+	explanation.Candidates = explainRanking(ranked, ranking)
+	explanation.BeerStyle = ranked[0].Style.Name
+	var resolved *resolvedPlaylist
+	explanation.Playlist, resolved = rs.explainPlaylist(ctx, ranked, request, resolvePlaylist)
+	if resolved != nil {
+		// next_style may recommend a lower-ranked style.
+		explanation.BeerStyle = resolved.style.Name
+	}
+
+	span.SetAttributes(attribute.Int("beer.candidates", len(ranked)), attribute.String("beer.style", explanation.BeerStyle))
+	return explanation, nil
+}
+
+// explainRanking describes the ranking, naming for each candidate the
+// others at the same distance, or adjusted distance under the feedback
+// ranking, that the name order separates.
+func explainRanking(ranked []domain.RankedBeerStyle, ranking string) []domain.CandidateExplanation {
+	compared := "distance"
+	if ranking == RankingFeedback {
+		compared = "adjusted distance"
+	}
+
+	candidates := make([]domain.CandidateExplanation, 0, len(ranked))
+	for i, candidate := range ranked {
+		var tied []string
+		for j, other := range ranked {
+			if i != j && other.AdjustedDistance == candidate.AdjustedDistance {
+				tied = append(tied, other.Style.Name)
+			}
+		}
+
+		explained := domain.CandidateExplanation{
+			UUID:     candidate.Style.UUID,
+			Name:     candidate.Style.Name,
+			TempMin:  candidate.Style.TempMin,
+			TempMax:  candidate.Style.TempMax,
+			Midpoint: candidate.Midpoint,
+			Distance: candidate.Distance,
+			Rank:     candidate.Rank,
+		}
+		if ranking == RankingFeedback {
+			score, adjusted := candidate.FeedbackScore, candidate.AdjustedDistance
+			explained.FeedbackScore = &score
+			explained.AdjustedDistance = &adjusted
+		}
+		if len(tied) > 0 {
+			explained.TieBreak = fmt.Sprintf("same %s as %s; ranked by name, alphabetically", compared, strings.Join(tied, ", "))
+		}
+		candidates = append(candidates, explained)
+	}
+	return candidates
+}
+
+// explainPlaylist walks the fallback chain recording its attempts, for
+// real when resolve is set and as a dry run otherwise. The playlist found
+// is returned too.
+func (rs *RecommendationService) explainPlaylist(ctx context.Context, ranked []domain.RankedBeerStyle, request domain.TemperatureRequest, resolve bool) (*domain.PlaylistSearchExplanation, *resolvedPlaylist) {
+	explanation := &domain.PlaylistSearchExplanation{
+		Provider: normalizeName(request.Provider),
+		Resolved: resolve,
+		Attempts: []domain.PlaylistAttempt{},
+	}
+	if explanation.Provider == "" {
+		explanation.Provider = rs.musicProviders.DefaultProvider()
+	}
+
+	search := &playlistSearch{
+		ctx:          ctx,
+		rs:           rs,
+		providerName: explanation.Provider,
+		request:      request,
+		attempts:     &explanation.Attempts,
+		dryRun:       !resolve,
+	}
+	if request.ShuffleSeed != nil {
+		search.seed = *request.ShuffleSeed
+	}
+	if resolve {
+		provider, err := rs.musicProviders.Get(request.Provider)
+		if err != nil {
+			explanation.Error = err.Error()
+			return explanation, nil
+		}
+		search.provider = provider
+	}
+
+	resolved, err := search.resolve(ranked)
+	if !resolve {
+		return explanation, nil
+	}
+	if err != nil {
+		explanation.Error = err.Error()
+		return explanation, nil
+	}
+	explanation.PlaylistID = resolved.id
+	explanation.Fallback = resolved.fallbackInfo()
+	return explanation, resolved
+}
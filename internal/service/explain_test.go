@@ -0,0 +1,165 @@
+package service
+
+import (
+	"backend-test/external/deezer"
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// setupExplainService is setupRecommendationService with a count of the
+// calls to the music provider.
+func setupExplainService(t *testing.T, styles []domain.BeerStyle) (*RecommendationService, *atomic.Int32) {
+	catalog := musictest.NewDeezerServer(musictest.SampleCatalog())
+	t.Cleanup(catalog.Close)
+
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		catalog.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	registry := music.NewRegistry(music.ProviderDeezer, deezer.NewProvider(server.URL, server.Client()))
+	service := NewRecommendationService(&stubBeerService{styles: styles}, nil, registry, nil, config.RecommendationConfig{}, nil, logging.Discard())
+	service.fallbackChain = parseFallbackChain([]string{"alias", "style_beer", "category", "next_style"}, logging.Discard())
+	return service, calls
+}
+
+func TestRecommendationService_ExplainRecommendation_Ranking(t *testing.T) {
+	service, calls := setupExplainService(t, []domain.BeerStyle{
+		{Name: "Pale Ale", TempMin: 7, TempMax: 10},
+		{Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale"},
+		{Name: "Lager", TempMin: 2, TempMax: 6},
+		{Name: "Stout", TempMin: 10, TempMax: 13},
+	})
+
+	explanation, err := service.ExplainRecommendation(context.Background(), domain.TemperatureRequest{Temperature: 8, ExcludeStyles: []string{"stout"}}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if explanation.BeerStyle != "IPA" || len(explanation.Candidates) != 3 {
+		t.Fatalf("Expected IPA among three candidates, got %+v", explanation)
+	}
+	first, second, third := explanation.Candidates[0], explanation.Candidates[1], explanation.Candidates[2]
+	if first.Name != "IPA" || first.Rank != 1 || first.Midpoint != 8.5 || first.Distance != 0.5 || !strings.Contains(first.TieBreak, "Pale Ale") {
+		t.Errorf("Expected IPA first by name over Pale Ale, got %+v", first)
+	}
+	if second.Name != "Pale Ale" || second.Rank != 2 || !strings.Contains(second.TieBreak, "IPA") {
+		t.Errorf("Expected Pale Ale second, tied with IPA, got %+v", second)
+	}
+	if third.Name != "Lager" || third.Distance != 4 || third.TieBreak != "" {
+		t.Errorf("Expected Lager third without a tie, got %+v", third)
+	}
+	if len(explanation.Excluded) != 1 || explanation.Excluded[0].Name != "Stout" || explanation.Excluded[0].Constraint != "exclude_styles" {
+		t.Errorf("Expected Stout excluded by exclude_styles, got %+v", explanation.Excluded)
+	}
+
+	playlist := explanation.Playlist
+	if playlist.Resolved || playlist.Provider != music.ProviderDeezer || playlist.PlaylistID != "" {
+		t.Errorf("Expected an unresolved deezer search, got %+v", playlist)
+	}
+	if first := playlist.Attempts[0]; first.BeerStyle != "IPA" || first.Step != FallbackStepStyleName || first.Query != "IPA" || first.Outcome != domain.AttemptPlanned {
+		t.Errorf("Expected the IPA query planned first, got %+v", first)
+	}
+	if last := playlist.Attempts[len(playlist.Attempts)-1]; last.BeerStyle != "Lager" || last.Step != FallbackStepNextStyle {
+		t.Errorf("Expected the plan to end with the next styles, got %+v", last)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected no call to the music provider, got %d", calls.Load())
+	}
+}
+
+func TestRecommendationService_ExplainRecommendation_FeedbackRanking(t *testing.T) {
+	service, _ := setupExplainService(t, []domain.BeerStyle{
+		{UUID: "ipa-uuid", Name: "IPA", TempMin: 7, TempMax: 10},
+		{UUID: "pale-ale-uuid", Name: "Pale Ale", TempMin: 7, TempMax: 10},
+	})
+	service.history = &stubRecorder{scores: map[string]float64{"pale-ale-uuid": 0.2}}
+	service.ranking = RankingFeedback
+	service.feedbackWeight = 1
+
+	explanation, err := service.ExplainRecommendation(context.Background(), domain.TemperatureRequest{Temperature: 8}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if explanation.Ranking != RankingFeedback || explanation.BeerStyle != "Pale Ale" {
+		t.Fatalf("Expected Pale Ale under the feedback ranking, got %+v", explanation)
+	}
+	first, second := explanation.Candidates[0], explanation.Candidates[1]
+	if first.FeedbackScore == nil || *first.FeedbackScore != 0.2 || *first.AdjustedDistance != 0.3 || first.TieBreak != "" {
+		t.Errorf("Expected Pale Ale ahead of the tie by its score, got %+v", first)
+	}
+	if second.Name != "IPA" || second.FeedbackScore == nil || *second.FeedbackScore != 0 {
+		t.Errorf("Expected IPA second without feedback, got %+v", second)
+	}
+}
+
+func TestRecommendationService_ExplainRecommendation_ResolvePlaylist(t *testing.T) {
+	service, calls := setupExplainService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10},
+		{Name: "Stout", TempMin: 10, TempMax: 13},
+	})
+
+	explanation, err := service.ExplainRecommendation(context.Background(), domain.TemperatureRequest{Temperature: 12, MinTracks: 2}, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	playlist := explanation.Playlist
+	if !playlist.Resolved || playlist.PlaylistID != "1001" || playlist.Fallback == nil || playlist.Fallback.Step != FallbackStepNextStyle {
+		t.Fatalf("Expected IPA Session through next_style, got %+v", playlist)
+	}
+	if explanation.BeerStyle != "IPA" || explanation.Candidates[0].Name != "Stout" {
+		t.Errorf("Expected IPA recommended although Stout ranks first, got %+v", explanation)
+	}
+
+	stout := playlist.Attempts[0]
+	if stout.Query != "Stout" || stout.Outcome != domain.AttemptRejected || len(stout.ResultIDs) != 1 || stout.ResultIDs[0] != "1002" {
+		t.Errorf("Expected Stout Nights found and rejected, got %+v", stout)
+	}
+	if len(stout.Rejected) != 1 || !strings.Contains(stout.Rejected[0].Reason, "min_tracks") {
+		t.Errorf("Expected the rejection to name min_tracks, got %+v", stout.Rejected)
+	}
+	if last := playlist.Attempts[len(playlist.Attempts)-1]; last.BeerStyle != "IPA" || last.Outcome != domain.AttemptSelected || last.PlaylistID != "1001" {
+		t.Errorf("Expected the IPA query selected last, got %+v", last)
+	}
+	if calls.Load() == 0 {
+		t.Error("Expected the music provider to be called")
+	}
+}
+
+func TestRecommendationService_ExplainRecommendation_Constraint(t *testing.T) {
+	abv := 5.0
+	service, _ := setupExplainService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10, ABV: &abv},
+		{Name: "Stout", TempMin: 10, TempMax: 13},
+	})
+
+	maxABV := 4.0
+	explanation, err := service.ExplainRecommendation(context.Background(), domain.TemperatureRequest{Temperature: 8, MaxABV: &maxABV}, true)
+	if err != nil {
+		t.Fatalf("Expected the constraint to be explained, got %v", err)
+	}
+
+	if explanation.Constraint != "max_abv" || len(explanation.Candidates) != 0 || explanation.Playlist != nil {
+		t.Errorf("Expected max_abv to leave no candidate, got %+v", explanation)
+	}
+	reasons := map[string]string{}
+	for _, excluded := range explanation.Excluded {
+		reasons[excluded.Name] = excluded.Reason
+	}
+	if reasons["IPA"] != "ABV 5.0% is above 4.0%" || reasons["Stout"] != "ABV unknown" {
+		t.Errorf("Unexpected exclusion reasons %v", reasons)
+	}
+}
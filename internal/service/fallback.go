@@ -0,0 +1,345 @@
+package service
+
+import (
+	"backend-test/external/music"
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+)
+
+// Steps of the playlist fallback chain. Pinned playlists and the style name
+// are always tried first; the remaining steps run in the configured order.
+const (
+	FallbackStepPinned          = "pinned_playlist"
+	FallbackStepStyleName       = "style_name"
+	FallbackStepAlias           = "alias"
+	FallbackStepStyleBeer       = "style_beer"
+	FallbackStepCategory        = "category"
+	FallbackStepNextStyle       = "next_style"
+	FallbackStepDefaultPlaylist = "default_playlist"
+)
+
+const (
+	// searchHitsPerQuery is how many search results are inspected per query.
+	searchHitsPerQuery = 3
+	// nextStyleCandidates is how many lower-ranked styles the next_style step tries.
+	nextStyleCandidates = 3
+)
+
+type playlistQuery struct {
+	step  string
+	query string
+}
+
+type resolvedPlaylist struct {
+	style    domain.BeerStyle
+	id       string
+	name     string
+	tracks   []domain.TrackInfo
+	fallback domain.FallbackInfo
+}
+
+func parseFallbackChain(steps []string, logger *slog.Logger) []string {
+	chain := make([]string, 0)
+	for _, step := range steps {
+		step = strings.TrimSpace(step)
+		switch step {
+		case "":
+			continue
+		case FallbackStepAlias, FallbackStepStyleBeer, FallbackStepCategory, FallbackStepNextStyle, FallbackStepDefaultPlaylist:
+			chain = append(chain, step)
+		default:
+			logger.Warn("ignoring unknown playlist fallback step", "step", step)
+		}
+	}
+	return chain
+}
+
+func (rs *RecommendationService) hasFallbackStep(step string) bool {
+	for _, configured := range rs.fallbackChain {
+		if configured == step {
+			return true
+		}
+	}
+	return false
+}
+
+// styleQueries lists the search queries tried for a single beer style: its
+// name followed by the query-based steps of the chain.
+func (rs *RecommendationService) styleQueries(style domain.BeerStyle) []playlistQuery {
+	queries := []playlistQuery{{step: FallbackStepStyleName, query: style.Name}}
+
+	for _, step := range rs.fallbackChain {
+		switch step {
+		case FallbackStepAlias:
+			for _, alias := range style.Aliases {
+				queries = append(queries, playlistQuery{step: step, query: alias})
+			}
+		case FallbackStepStyleBeer:
+			queries = append(queries, playlistQuery{step: step, query: style.Name + " beer"})
+		case FallbackStepCategory:
+			if style.Category != "" {
+				queries = append(queries, playlistQuery{step: step, query: style.Category})
+			}
+		}
+	}
+
+	return queries
+}
+
+// playlistSearch holds the state of one walk through the fallback chain.
+type playlistSearch struct {
+	ctx          context.Context
+	rs           *RecommendationService
+	provider     music.Provider
+	providerName string
+	request      domain.TemperatureRequest
+	seed         int64
+	// tooFewTracks records that a playlist was rejected only because it had
+	// fewer tracks than min_tracks.
+	tooFewTracks bool
+	// providerErr stops the walk once the provider is found unavailable.
+	providerErr error
+	// attempts, when not nil, records the pinned playlists, queries and
+	// default playlist tried. A dryRun records them without calling the
+	// provider, which walks the whole chain.
+	attempts *[]domain.PlaylistAttempt
+	dryRun   bool
+}
+
+// resolvePlaylist walks the fallback chain until a playlist with enough
+// tracks is found.
+func (rs *RecommendationService) resolvePlaylist(ctx context.Context, provider music.Provider, ranked []domain.RankedBeerStyle, request domain.TemperatureRequest, seed int64) (*resolvedPlaylist, error) {
+	search := &playlistSearch{ctx: ctx, rs: rs, provider: provider, providerName: provider.Name(), request: request, seed: seed}
+	return search.resolve(ranked)
+}
+
+// resolve tries pinned playlists and queries for the best style, then the
+// same for the next-ranked styles, then the curated default playlist.
+func (s *playlistSearch) resolve(ranked []domain.RankedBeerStyle) (*resolvedPlaylist, error) {
+	rs := s.rs
+	best := ranked[0].Style
+
+	if resolved := s.pinned(best, FallbackStepPinned); resolved != nil {
+		return resolved.from(best, FallbackStepPinned, ""), nil
+	}
+
+	for _, query := range rs.styleQueries(best) {
+		if resolved := s.query(best, query.step, query.query); resolved != nil {
+			return resolved.from(best, query.step, query.query), nil
+		}
+	}
+
+	if rs.hasFallbackStep(FallbackStepNextStyle) {
+		for i := 1; i < len(ranked) && i <= nextStyleCandidates; i++ {
+			style := ranked[i].Style
+			if resolved := s.pinned(style, FallbackStepNextStyle); resolved != nil {
+				return resolved.from(style, FallbackStepNextStyle, ""), nil
+			}
+
+			for _, query := range rs.styleQueries(style) {
+				if resolved := s.query(style, FallbackStepNextStyle, query.query); resolved != nil {
+					return resolved.from(style, FallbackStepNextStyle, query.query), nil
+				}
+			}
+		}
+	}
+
+	defaultPlaylistID := rs.defaultPlaylistIDs[s.providerName]
+	if rs.hasFallbackStep(FallbackStepDefaultPlaylist) && defaultPlaylistID != "" {
+		if resolved := s.byID(best, FallbackStepDefaultPlaylist, defaultPlaylistID); resolved != nil {
+			return resolved.from(best, FallbackStepDefaultPlaylist, ""), nil
+		}
+	}
+
+	if s.providerErr != nil {
+		return nil, s.providerErr
+	}
+
+	if s.tooFewTracks {
+		return nil, &ConstraintError{
+			Constraint: "min_tracks",
+			Reason:     fmt.Sprintf("no playlist found for beer style '%s' with at least %d tracks", best.Name, s.request.MinTracks),
+		}
+	}
+
+	return nil, fmt.Errorf("no playlist found for beer style '%s'", best.Name)
+}
+
+func (r *resolvedPlaylist) from(style domain.BeerStyle, step string, query string) *resolvedPlaylist {
+	r.style = style
+	r.fallback = domain.FallbackInfo{Step: step, Query: query}
+	return r
+}
+
+// fallbackInfo returns the fallback step that produced the playlist, or nil
+// when a primary step did: a playlist pinned to the best style or the
+// search by its name.
+func (r *resolvedPlaylist) fallbackInfo() *domain.FallbackInfo {
+	switch r.fallback.Step {
+	case FallbackStepPinned, FallbackStepStyleName:
+		return nil
+	}
+	fallback := r.fallback
+	return &fallback
+}
+
+// pinned tries the playlists pinned to the style, picking them in a
+// weighted random order so heavier pins are chosen more often.
+func (s *playlistSearch) pinned(style domain.BeerStyle, step string) *resolvedPlaylist {
+	if s.providerErr != nil || s.rs.playlistMappingService == nil || style.UUID == "" {
+		return nil
+	}
+
+	pinned, err := s.rs.playlistMappingService.ListPlaylistsForBeerStyle(s.ctx, style.UUID)
+	if err != nil {
+		s.rs.logger.WarnContext(s.ctx, "failed to list pinned playlists", "beer_style", style.Name, "err", err)
+		return nil
+	}
+
+	for _, mapping := range weightedOrder(pinned) {
+		if mapping.Provider != s.providerName {
+			continue
+		}
+		if resolved := s.byID(style, step, mapping.PlaylistID); resolved != nil {
+			return resolved
+		}
+	}
+
+	return nil
+}
+
+// byID tries a pinned or default playlist.
+func (s *playlistSearch) byID(style domain.BeerStyle, step string, playlistID string) *resolvedPlaylist {
+	if s.providerErr != nil {
+		return nil
+	}
+
+	attempt := domain.PlaylistAttempt{BeerStyle: style.Name, Step: step, PlaylistID: playlistID, Outcome: domain.AttemptPlanned}
+	if s.dryRun {
+		s.record(attempt)
+		return nil
+	}
+
+	resolved, reason := s.fetch(playlistID)
+	if resolved != nil {
+		attempt.Outcome = domain.AttemptSelected
+	} else {
+		attempt.Outcome = domain.AttemptRejected
+		attempt.Rejected = []domain.PlaylistRejection{{PlaylistID: playlistID, Reason: reason}}
+	}
+	s.record(attempt)
+	return resolved
+}
+
+// query returns the first search hit for the query that yields enough tracks.
+func (s *playlistSearch) query(style domain.BeerStyle, step string, query string) *resolvedPlaylist {
+	if s.providerErr != nil {
+		return nil
+	}
+
+	attempt := domain.PlaylistAttempt{BeerStyle: style.Name, Step: step, Query: query, Outcome: domain.AttemptPlanned}
+	if s.dryRun {
+		s.record(attempt)
+		return nil
+	}
+
+	hits, err := s.provider.SearchPlaylists(s.ctx, query, searchHitsPerQuery)
+	if err != nil {
+		s.fail(err, "search "+query)
+		attempt.Outcome, attempt.Error = domain.AttemptFailed, err.Error()
+		s.record(attempt)
+		return nil
+	}
+
+	attempt.Outcome = domain.AttemptNoResults
+	for _, hit := range hits {
+		attempt.ResultIDs = append(attempt.ResultIDs, hit.ID)
+	}
+	for _, hit := range hits {
+		resolved, reason := s.fetch(hit.ID)
+		if resolved != nil {
+			attempt.Outcome, attempt.PlaylistID = domain.AttemptSelected, hit.ID
+			s.record(attempt)
+			return resolved
+		}
+		attempt.Outcome = domain.AttemptRejected
+		attempt.Rejected = append(attempt.Rejected, domain.PlaylistRejection{PlaylistID: hit.ID, Reason: reason})
+	}
+
+	s.record(attempt)
+	return nil
+}
+
+// fetch loads a playlist by ID and accepts it when it has enough tracks.
+// Otherwise it returns why the playlist was rejected.
+func (s *playlistSearch) fetch(playlistID string) (*resolvedPlaylist, string) {
+	if s.providerErr != nil {
+		return nil, "not fetched: the provider is unavailable"
+	}
+
+	playlist, err := s.provider.GetPlaylist(s.ctx, playlistID)
+	if err != nil {
+		s.fail(err, "fetch playlist "+playlistID)
+		return nil, "fetch failed: " + err.Error()
+	}
+
+	tracks := selectTracks(convertTracks(playlist), s.request, s.seed)
+	if len(tracks) == 0 {
+		return nil, "no tracks"
+	}
+	if len(tracks) < s.request.MinTracks {
+		s.tooFewTracks = true
+		return nil, fmt.Sprintf("%d tracks, fewer than min_tracks (%d)", len(tracks), s.request.MinTracks)
+	}
+
+	return &resolvedPlaylist{id: playlist.ID, name: playlist.Name, tracks: tracks}, ""
+}
+
+func (s *playlistSearch) record(attempt domain.PlaylistAttempt) {
+	if s.attempts != nil {
+		*s.attempts = append(*s.attempts, attempt)
+	}
+}
+
+func (s *playlistSearch) fail(err error, action string) {
+	s.rs.logger.WarnContext(s.ctx, "music provider call failed", "action", action, "provider", s.providerName, "err", err)
+	if errors.Is(err, music.ErrProviderUnavailable) {
+		s.providerErr = fmt.Errorf("%s service unavailable: %w", s.providerName, err)
+	}
+}
+
+// weightedOrder returns the pinned playlists in a random order where each
+// remaining playlist is drawn with probability proportional to its weight.
+func weightedOrder(pinned []domain.BeerStylePlaylist) []domain.BeerStylePlaylist {
+	remaining := append([]domain.BeerStylePlaylist{}, pinned...)
+	ordered := make([]domain.BeerStylePlaylist, 0, len(pinned))
+
+	for len(remaining) > 0 {
+		total := 0
+		for _, mapping := range remaining {
+			total += mapping.Weight
+		}
+
+		pick := 0
+		if total > 0 {
+			target := rand.Intn(total)
+			for i, mapping := range remaining {
+				target -= mapping.Weight
+				if target < 0 {
+					pick = i
+					break
+				}
+			}
+		}
+
+		ordered = append(ordered, remaining[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+
+	return ordered
+}
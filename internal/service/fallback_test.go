@@ -0,0 +1,172 @@
+package service
+
+import (
+	"backend-test/external/music"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// stubProvider serves playlists by ID and answers only the searches in hits,
+// recording every search and fetch.
+type stubProvider struct {
+	hits      map[string]string
+	playlists map[string]int
+	err       error
+	calls     []string
+}
+
+func (p *stubProvider) Name() string {
+	return music.ProviderDeezer
+}
+
+func (p *stubProvider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	p.calls = append(p.calls, "search "+query)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if id, ok := p.hits[query]; ok {
+		return []music.PlaylistSummary{{ID: id, Name: query}}, nil
+	}
+	return nil, nil
+}
+
+func (p *stubProvider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	p.calls = append(p.calls, "fetch "+playlistID)
+	trackCount, ok := p.playlists[playlistID]
+	if !ok {
+		return nil, fmt.Errorf("playlist %s not found", playlistID)
+	}
+
+	playlist := &music.Playlist{ID: playlistID, Name: "Playlist " + playlistID}
+	for i := 0; i < trackCount; i++ {
+		playlist.Tracks = append(playlist.Tracks, music.Track{Name: fmt.Sprintf("Track %d", i), Artists: []string{fmt.Sprintf("Artist %d", i)}})
+	}
+	return playlist, nil
+}
+
+func fallbackRanking() []domain.RankedBeerStyle {
+	return []domain.RankedBeerStyle{
+		{Rank: 1, Style: domain.BeerStyle{Name: "IPA", Aliases: []string{"India Pale Ale"}, Category: "Ale"}},
+		{Rank: 2, Style: domain.BeerStyle{Name: "Stout", Category: "Dark"}},
+	}
+}
+
+func setupFallbackService(chain []string) *RecommendationService {
+	return NewRecommendationService(nil, nil, nil, nil, config.RecommendationConfig{
+		FallbackChain:      chain,
+		DefaultPlaylistIDs: map[string]string{music.ProviderDeezer: "default"},
+	}, nil, logging.Discard())
+}
+
+func TestResolvePlaylist_ChainOrder(t *testing.T) {
+	provider := &stubProvider{playlists: map[string]int{"default": 3}}
+	service := setupFallbackService([]string{"alias", "style_beer", "category", "next_style", "default_playlist"})
+
+	resolved, err := service.resolvePlaylist(context.Background(), provider, fallbackRanking(), domain.TemperatureRequest{}, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"search IPA",
+		"search India Pale Ale",
+		"search IPA beer",
+		"search Ale",
+		"search Stout",
+		"search Stout beer",
+		"search Dark",
+		"fetch default",
+	}
+	if !reflect.DeepEqual(provider.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, provider.calls)
+	}
+	if resolved.fallback.Step != FallbackStepDefaultPlaylist || resolved.style.Name != "IPA" {
+		t.Errorf("Expected the default playlist for IPA, got %+v for %s", resolved.fallback, resolved.style.Name)
+	}
+}
+
+func TestResolvePlaylist_Steps(t *testing.T) {
+	tests := []struct {
+		name      string
+		chain     []string
+		hits      map[string]string
+		wantStep  string
+		wantQuery string
+		wantStyle string
+	}{
+		{name: "style name", chain: []string{"alias"}, hits: map[string]string{"IPA": "1"}, wantStep: FallbackStepStyleName, wantQuery: "IPA", wantStyle: "IPA"},
+		{name: "alias", chain: []string{"alias"}, hits: map[string]string{"India Pale Ale": "1"}, wantStep: FallbackStepAlias, wantQuery: "India Pale Ale", wantStyle: "IPA"},
+		{name: "style beer", chain: []string{"style_beer"}, hits: map[string]string{"IPA beer": "1"}, wantStep: FallbackStepStyleBeer, wantQuery: "IPA beer", wantStyle: "IPA"},
+		{name: "category", chain: []string{"category"}, hits: map[string]string{"Ale": "1"}, wantStep: FallbackStepCategory, wantQuery: "Ale", wantStyle: "IPA"},
+		{name: "next style", chain: []string{"next_style"}, hits: map[string]string{"Stout": "1"}, wantStep: FallbackStepNextStyle, wantQuery: "Stout", wantStyle: "Stout"},
+		{name: "earlier step wins", chain: []string{"category", "alias"}, hits: map[string]string{"Ale": "1", "India Pale Ale": "2"}, wantStep: FallbackStepCategory, wantQuery: "Ale", wantStyle: "IPA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &stubProvider{hits: tt.hits, playlists: map[string]int{"1": 3, "2": 3}}
+			service := setupFallbackService(tt.chain)
+
+			resolved, err := service.resolvePlaylist(context.Background(), provider, fallbackRanking(), domain.TemperatureRequest{}, 0)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resolved.fallback.Step != tt.wantStep || resolved.fallback.Query != tt.wantQuery || resolved.style.Name != tt.wantStyle {
+				t.Errorf("Expected %s/%s for %s, got %+v for %s", tt.wantStep, tt.wantQuery, tt.wantStyle, resolved.fallback, resolved.style.Name)
+			}
+		})
+	}
+}
+
+func TestResolvePlaylist_Exhausted(t *testing.T) {
+	t.Run("no playlist", func(t *testing.T) {
+		provider := &stubProvider{}
+		service := setupFallbackService([]string{"alias", "next_style"})
+
+		_, err := service.resolvePlaylist(context.Background(), provider, fallbackRanking(), domain.TemperatureRequest{}, 0)
+		if err == nil || err.Error() != "no playlist found for beer style 'IPA'" {
+			t.Errorf("Expected no playlist error, got %v", err)
+		}
+		if len(provider.calls) != 3 {
+			t.Errorf("Expected the chain to be walked once (3 searches), got %v", provider.calls)
+		}
+	})
+
+	t.Run("too few tracks", func(t *testing.T) {
+		provider := &stubProvider{hits: map[string]string{"IPA": "1"}, playlists: map[string]int{"1": 2, "default": 2}}
+		service := setupFallbackService([]string{"default_playlist"})
+
+		_, err := service.resolvePlaylist(context.Background(), provider, fallbackRanking(), domain.TemperatureRequest{MinTracks: 3}, 0)
+		var constraintErr *ConstraintError
+		if !errors.As(err, &constraintErr) || constraintErr.Constraint != "min_tracks" {
+			t.Errorf("Expected min_tracks constraint error, got %v", err)
+		}
+	})
+
+	t.Run("provider unavailable", func(t *testing.T) {
+		provider := &stubProvider{err: music.ErrProviderUnavailable}
+		service := setupFallbackService([]string{"alias", "style_beer", "next_style", "default_playlist"})
+
+		_, err := service.resolvePlaylist(context.Background(), provider, fallbackRanking(), domain.TemperatureRequest{}, 0)
+		if !errors.Is(err, music.ErrProviderUnavailable) {
+			t.Errorf("Expected provider unavailable error, got %v", err)
+		}
+		if len(provider.calls) != 1 {
+			t.Errorf("Expected the walk to stop after the first failure, got %v", provider.calls)
+		}
+	})
+}
+
+func TestParseFallbackChain(t *testing.T) {
+	chain := parseFallbackChain([]string{" alias ", "", "unknown", "default_playlist"}, logging.Discard())
+
+	if !reflect.DeepEqual(chain, []string{FallbackStepAlias, FallbackStepDefaultPlaylist}) {
+		t.Errorf("Expected [alias default_playlist], got %v", chain)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultHealthCheckTimeout bounds a dependency check without its own timeout.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheck pings one dependency. A failing critical check makes the
+// instance down; a failing non-critical check only degrades it.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration
+	Ping     func(ctx context.Context) error
+	// Details, when set, adds dependency specific information (e.g. pool
+	// stats) to the check result.
+	Details func() map[string]interface{}
+}
+
+type HealthService struct {
+	checks []HealthCheck
+}
+
+func NewHealthService(checks ...HealthCheck) *HealthService {
+	return &HealthService{checks: checks}
+}
+
+// Check runs every dependency check concurrently, each under its own
+// timeout, and aggregates the overall status.
+func (hs *HealthService) Check(ctx context.Context) domain.HealthReport {
+	results := make([]domain.HealthCheckResult, len(hs.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range hs.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := domain.HealthStatusUp
+	for _, result := range results {
+		if result.Status != domain.HealthStatusDown {
+			continue
+		}
+		if result.Critical {
+			status = domain.HealthStatusDown
+			break
+		}
+		status = domain.HealthStatusDegraded
+	}
+
+	return domain.HealthReport{
+		Status: status,
+		Checks: results,
+	}
+}
+
+// CachedPing returns ping with its outcome reused for ttl, for checks too
+// costly to run on every probe. Probes arriving while ping runs share its
+// outcome instead of starting another. Context errors are not cached: they
+// tell about the probe, not the dependency. A non-positive ttl returns ping
+// unchanged.
+func CachedPing(ping func(ctx context.Context) error, ttl time.Duration, now func() time.Time) func(ctx context.Context) error {
+	if ttl <= 0 {
+		return ping
+	}
+
+	var (
+		group     singleflight.Group
+		mu        sync.Mutex
+		lastErr   error
+		expiresAt time.Time
+	)
+	return func(ctx context.Context) error {
+		mu.Lock()
+		if now().Before(expiresAt) {
+			err := lastErr
+			mu.Unlock()
+			return err
+		}
+		mu.Unlock()
+
+		ch := group.DoChan("ping", func() (interface{}, error) {
+			// The ping is shared, so the caller that started it going away
+			// must not cancel it; its deadline still bounds it.
+			pingCtx := context.WithoutCancel(ctx)
+			if deadline, ok := ctx.Deadline(); ok {
+				var cancel context.CancelFunc
+				pingCtx, cancel = context.WithDeadline(pingCtx, deadline)
+				defer cancel()
+			}
+
+			err := ping(pingCtx)
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				mu.Lock()
+				lastErr, expiresAt = err, now().Add(ttl)
+				mu.Unlock()
+			}
+			return nil, err
+		})
+
+		select {
+		case res := <-ch:
+			return res.Err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func runHealthCheck(ctx context.Context, check HealthCheck) domain.HealthCheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The ping runs apart so a dependency client that ignores the context
+	// cannot hold the probe past its timeout.
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		done <- check.Ping(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+
+	result := domain.HealthCheckResult{
+		Name:      check.Name,
+		Status:    domain.HealthStatusUp,
+		Critical:  check.Critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusDown
+		result.Error = err.Error()
+	}
+	if check.Details != nil {
+		result.Details = check.Details()
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func pingOK(ctx context.Context) error {
+	return nil
+}
+
+func pingFail(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealthService_Check_AllUp(t *testing.T) {
+	service := NewHealthService(
+		HealthCheck{Name: "postgres", Critical: true, Ping: pingOK},
+		HealthCheck{Name: "redis", Ping: pingOK},
+	)
+
+	report := service.Check(context.Background())
+
+	if report.Status != domain.HealthStatusUp {
+		t.Errorf("Expected status up, got %s", report.Status)
+	}
+	if len(report.Checks) != 2 || report.Checks[0].Name != "postgres" {
+		t.Errorf("Expected checks in registration order, got %+v", report.Checks)
+	}
+}
+
+func TestHealthService_Check_NonCriticalDownIsDegraded(t *testing.T) {
+	service := NewHealthService(
+		HealthCheck{Name: "postgres", Critical: true, Ping: pingOK},
+		HealthCheck{Name: "redis", Ping: pingFail},
+	)
+
+	report := service.Check(context.Background())
+
+	if report.Status != domain.HealthStatusDegraded {
+		t.Errorf("Expected status degraded, got %s", report.Status)
+	}
+	if report.Checks[1].Status != domain.HealthStatusDown || report.Checks[1].Error != "connection refused" {
+		t.Errorf("Expected redis down with error, got %+v", report.Checks[1])
+	}
+}
+
+func TestHealthService_Check_CriticalDownIsDown(t *testing.T) {
+	service := NewHealthService(
+		HealthCheck{Name: "postgres", Critical: true, Ping: pingFail},
+		HealthCheck{Name: "redis", Ping: pingFail},
+	)
+
+	report := service.Check(context.Background())
+
+	if report.Status != domain.HealthStatusDown {
+		t.Errorf("Expected status down, got %s", report.Status)
+	}
+}
+
+func TestHealthService_Check_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	service := NewHealthService(HealthCheck{
+		Name:     "music_provider",
+		Critical: true,
+		Timeout:  20 * time.Millisecond,
+		Ping: func(ctx context.Context) error {
+			<-block
+			return nil
+		},
+	})
+
+	start := time.Now()
+	report := service.Check(context.Background())
+
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected check to stop at its timeout, took %s", time.Since(start))
+	}
+	if report.Status != domain.HealthStatusDown || report.Checks[0].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected timed out check to be down, got %+v", report.Checks[0])
+	}
+}
+
+func TestHealthService_Check_Details(t *testing.T) {
+	service := NewHealthService(HealthCheck{
+		Name: "postgres",
+		Ping: pingOK,
+		Details: func() map[string]interface{} {
+			return map[string]interface{}{"in_use": 3}
+		},
+	})
+
+	report := service.Check(context.Background())
+
+	if report.Checks[0].Details["in_use"] != 3 {
+		t.Errorf("Expected pool details in the result, got %+v", report.Checks[0].Details)
+	}
+}
+
+func TestCachedPing(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	ping := CachedPing(func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, 30*time.Second, func() time.Time { return now })
+
+	if err := ping(context.Background()); err == nil {
+		t.Fatal("Expected the first ping to fail")
+	}
+	now = now.Add(29 * time.Second)
+	if err := ping(context.Background()); err == nil || calls != 1 {
+		t.Errorf("Expected the cached failure without a new ping, got %v after %d calls", err, calls)
+	}
+	now = now.Add(time.Second)
+	if err := ping(context.Background()); err != nil || calls != 2 {
+		t.Errorf("Expected a new ping once the TTL elapsed, got %v after %d calls", err, calls)
+	}
+}
+
+func TestCachedPing_DoesNotCacheContextErrors(t *testing.T) {
+	calls := 0
+	ping := CachedPing(func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}, time.Minute, time.Now)
+
+	if err := ping(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline error, got %v", err)
+	}
+	if err := ping(context.Background()); err != nil || calls != 2 {
+		t.Errorf("Expected a new ping after a context error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestCachedPing_SharesRunningPing(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var calls atomic.Int32
+	ping := CachedPing(func(ctx context.Context) error {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		return nil
+	}, time.Minute, time.Now)
+
+	// The first caller goes away while the ping runs.
+	cancelled, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() { first <- ping(cancelled) }()
+	<-started
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancelled caller to return, got %v", err)
+	}
+
+	second := make(chan error, 1)
+	go func() { second <- ping(context.Background()) }()
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Expected the shared ping to succeed, got %v", err)
+	}
+	if err := ping(context.Background()); err != nil || calls.Load() != 1 {
+		t.Errorf("Expected one ping shared and cached, got %v after %d calls", err, calls.Load())
+	}
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/metrics"
+	"backend-test/internal/storage/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultAnalyticsWindow is the period aggregated when the analytics
+	// query does not set one.
+	DefaultAnalyticsWindow = 30 * 24 * time.Hour
+	// DefaultTemperatureBucket is the width, in degrees, of the temperature
+	// buckets of the analytics.
+	DefaultTemperatureBucket = 5.0
+
+	// saveAttempts is how many times the writer tries to store a batch
+	// before dropping it.
+	saveAttempts = 3
+	// saveBackoff is the wait before the second attempt, doubled on each
+	// following one.
+	saveBackoff = 200 * time.Millisecond
+	// pendingWait bounds how long feedback waits for the writer to store
+	// the recommendation it rates.
+	pendingWait = 5 * time.Second
+)
+
+// ErrUnknownBeerStyle is returned for feedback preferring a style that is
+// not in the catalog.
+var ErrUnknownBeerStyle = errors.New("unknown beer style")
+
+// ErrForeignRecommendation is returned for feedback from a caller other
+// than the client the recommendation was served to.
+var ErrForeignRecommendation = errors.New("recommendation served to another client")
+
+// ErrRecommendationPending is returned for feedback on a recommendation the
+// writer did not store in time. The client may retry.
+var ErrRecommendationPending = errors.New("recommendation not stored yet")
+
+// RecommendationHistoryService keeps the history of served recommendations
+// and aggregates it. Record never blocks the request: records are queued in
+// a buffered channel that a background writer drains in batches. Once Start
+// is called, a purge job also deletes the records older than the retention.
+type RecommendationHistoryService struct {
+	historyRepository repository.RecommendationHistoryRepositoryInterface
+	beerService       BeerServiceInterface
+	cfg               config.HistoryConfig
+	clientID          func(context.Context) string
+	now               func() time.Time
+	logger            *slog.Logger
+	saveBackoff       time.Duration
+
+	// feedbackCache holds the feedback scores by temperature bucket for
+	// cfg.FeedbackCacheTTL.
+	feedbackMu    sync.Mutex
+	feedbackCache map[feedbackBucket]cachedFeedback
+
+	// pending holds the IDs of the records queued or being written; each
+	// channel is closed once its record is stored or dropped. flush asks
+	// the writer to store its batch early.
+	pendingMu sync.Mutex
+	pending   map[string]chan struct{}
+	flush     chan struct{}
+
+	records   chan domain.RecommendationRecord
+	stop      chan struct{}
+	stopped   atomic.Bool
+	workers   sync.WaitGroup
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+type feedbackBucket struct {
+	bucket, size float64
+}
+
+type cachedFeedback struct {
+	scores    map[string]float64
+	expiresAt time.Time
+}
+
+// NewRecommendationHistoryService returns the history service. clientID
+// names the caller of a request, or returns "" for anonymous ones; it may be
+// nil.
+func NewRecommendationHistoryService(historyRepo repository.RecommendationHistoryRepositoryInterface, beerService BeerServiceInterface, cfg config.HistoryConfig, clientID func(context.Context) string, now func() time.Time, logger *slog.Logger) *RecommendationHistoryService {
+	if now == nil {
+		now = time.Now
+	}
+	if clientID == nil {
+		clientID = func(context.Context) string { return "" }
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &RecommendationHistoryService{
+		historyRepository: historyRepo,
+		beerService:       beerService,
+		cfg:               cfg,
+		clientID:          clientID,
+		now:               now,
+		logger:            logger.With("service", "RecommendationHistoryService"),
+		saveBackoff:       saveBackoff,
+		feedbackCache:     map[feedbackBucket]cachedFeedback{},
+		pending:           map[string]chan struct{}{},
+		flush:             make(chan struct{}, 1),
+		records:           make(chan domain.RecommendationRecord, bufferSize),
+		stop:              make(chan struct{}),
+	}
+}
+
+// Start launches the writer and, when a retention is set, the purge job.
+// Both run until Close. It does nothing when the history is disabled.
+func (hs *RecommendationHistoryService) Start() {
+	if !hs.cfg.Enabled {
+		return
+	}
+
+	hs.startOnce.Do(func() {
+		hs.workers.Add(1)
+		go hs.write()
+
+		if hs.cfg.Retention > 0 {
+			hs.workers.Add(1)
+			go hs.purge()
+		}
+	})
+}
+
+// Close stops the purge job and writes the records still buffered. It is
+// safe to call more than once.
+func (hs *RecommendationHistoryService) Close() error {
+	hs.closeOnce.Do(func() {
+		hs.stopped.Store(true)
+		close(hs.stop)
+		hs.workers.Wait()
+	})
+	return nil
+}
+
+// Record queues a served recommendation and returns the ID given to it. It
+// returns "" without waiting when the history is disabled, closed or its
+// buffer is full.
+func (hs *RecommendationHistoryService) Record(ctx context.Context, record domain.RecommendationRecord) string {
+	if !hs.cfg.Enabled || hs.stopped.Load() {
+		return ""
+	}
+
+	record.ID = uuid.NewString()
+	record.ClientID = hs.clientID(ctx)
+	record.CreatedAt = hs.now().UTC()
+
+	// The record is pending before the writer can see it, so it is never
+	// settled before being added.
+	hs.pendingMu.Lock()
+	hs.pending[record.ID] = make(chan struct{})
+	hs.pendingMu.Unlock()
+
+	select {
+	case hs.records <- record:
+		return record.ID
+	default:
+		hs.settle([]domain.RecommendationRecord{record})
+		metrics.CountHistoryRecords("dropped", 1)
+		hs.logger.WarnContext(ctx, "recommendation history buffer full, record dropped", "buffer_size", cap(hs.records))
+		return ""
+	}
+}
+
+// write stores the queued records in batches of up to BatchSize, writing a
+// partial batch every FlushInterval or when asked through flush. On Close it
+// drains the buffer.
+func (hs *RecommendationHistoryService) write() {
+	defer hs.workers.Done()
+
+	ticker := time.NewTicker(hs.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.RecommendationRecord, 0, hs.cfg.BatchSize)
+	add := func(record domain.RecommendationRecord) {
+		batch = append(batch, record)
+		if len(batch) >= hs.cfg.BatchSize {
+			hs.save(batch)
+			batch = batch[:0]
+		}
+	}
+
+	drain := func() {
+		for {
+			select {
+			case record := <-hs.records:
+				add(record)
+			default:
+				hs.save(batch)
+				batch = batch[:0]
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case record := <-hs.records:
+			add(record)
+		case <-ticker.C:
+			hs.save(batch)
+			batch = batch[:0]
+		case <-hs.flush:
+			drain()
+		case <-hs.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// save stores batch, retrying with a growing backoff so a brief database
+// outage does not lose it. The batch is dropped after saveAttempts failures.
+func (hs *RecommendationHistoryService) save(batch []domain.RecommendationRecord) {
+	if len(batch) == 0 {
+		return
+	}
+	defer hs.settle(batch)
+
+	backoff := hs.saveBackoff
+	for attempt := 1; ; attempt++ {
+		err := hs.historyRepository.SaveRecommendations(context.Background(), batch)
+		if err == nil {
+			metrics.CountHistoryRecords("stored", len(batch))
+			return
+		}
+		if attempt == saveAttempts {
+			metrics.CountHistoryRecords("failed", len(batch))
+			hs.logger.Error("failed to store recommendation history, records dropped", "records", len(batch), "attempts", attempt, "err", err)
+			return
+		}
+
+		hs.logger.Warn("failed to store recommendation history, retrying", "records", len(batch), "attempt", attempt, "retry_in", backoff, "err", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// settle marks the records of batch as no longer pending.
+func (hs *RecommendationHistoryService) settle(batch []domain.RecommendationRecord) {
+	hs.pendingMu.Lock()
+	defer hs.pendingMu.Unlock()
+
+	for _, record := range batch {
+		if done, ok := hs.pending[record.ID]; ok {
+			close(done)
+			delete(hs.pending, record.ID)
+		}
+	}
+}
+
+// awaitRecord waits for the writer to store or drop the record id when it
+// is still pending, asking for an early flush. It fails with
+// ErrRecommendationPending after pendingWait.
+func (hs *RecommendationHistoryService) awaitRecord(ctx context.Context, id string) error {
+	hs.pendingMu.Lock()
+	done, ok := hs.pending[id]
+	hs.pendingMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case hs.flush <- struct{}{}:
+	default:
+	}
+
+	timer := time.NewTimer(pendingWait)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return ErrRecommendationPending
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrRecommendationPending, ctx.Err())
+	}
+}
+
+// purge deletes the expired records at start and every PurgeInterval.
+func (hs *RecommendationHistoryService) purge() {
+	defer hs.workers.Done()
+
+	ticker := time.NewTicker(hs.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := hs.Purge(context.Background()); err != nil {
+			hs.logger.Error("failed to purge recommendation history", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-hs.stop:
+			return
+		}
+	}
+}
+
+// Purge deletes the records older than the retention and returns how many
+// were deleted. It does nothing when the retention is zero.
+func (hs *RecommendationHistoryService) Purge(ctx context.Context) (int64, error) {
+	if hs.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	before := hs.now().UTC().Add(-hs.cfg.Retention)
+	purged, err := hs.historyRepository.PurgeRecommendations(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.CountHistoryRecords("purged", int(purged))
+	if purged > 0 {
+		hs.logger.InfoContext(ctx, "purged recommendation history", "records", purged, "before", before)
+	}
+	return purged, nil
+}
+
+// GetRecommendationAnalytics aggregates the history of query. Without a
+// period it covers the DefaultAnalyticsWindow up to now; without a bucket
+// size temperatures are grouped by DefaultTemperatureBucket degrees.
+func (hs *RecommendationHistoryService) GetRecommendationAnalytics(ctx context.Context, query domain.AnalyticsQuery) (*domain.RecommendationAnalytics, error) {
+	if query.To.IsZero() {
+		query.To = hs.now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-DefaultAnalyticsWindow)
+	}
+	if query.BucketSize <= 0 {
+		query.BucketSize = DefaultTemperatureBucket
+	}
+	from, to := query.From.UTC(), query.To.UTC()
+
+	byBeerStyle, err := hs.historyRepository.CountByBeerStyle(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recommendations by beer style: %w", err)
+	}
+	byTemperature, err := hs.historyRepository.CountByTemperature(ctx, from, to, query.BucketSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recommendations by temperature: %w", err)
+	}
+	byDay, err := hs.historyRepository.CountByDay(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recommendations by day: %w", err)
+	}
+
+	analytics := &domain.RecommendationAnalytics{
+		From:          from,
+		To:            to,
+		BucketSize:    query.BucketSize,
+		ByBeerStyle:   make([]domain.BeerStyleCount, 0, len(byBeerStyle)),
+		ByTemperature: make([]domain.TemperatureBucketCount, 0, len(byTemperature)),
+		ByDay:         make([]domain.DayCount, 0, len(byDay)),
+	}
+	for _, count := range byBeerStyle {
+		analytics.Total += count.Count
+		analytics.ByBeerStyle = append(analytics.ByBeerStyle, count)
+	}
+	for _, bucket := range byTemperature {
+		bucket.Max = bucket.Min + query.BucketSize
+		analytics.ByTemperature = append(analytics.ByTemperature, bucket)
+	}
+	analytics.ByDay = append(analytics.ByDay, byDay...)
+
+	return analytics, nil
+}
+
+// SubmitFeedback rates the recorded recommendation id, replacing a previous
+// vote. Only the authenticated client the recommendation was served to may
+// rate it; others get ErrForeignRecommendation. A preferred style is stored by UUID, with its catalog name for
+// display; it fails with ErrUnknownBeerStyle when the catalog has no such
+// style. Feedback on a
+// recommendation still buffered waits for the writer to store it, and fails
+// with ErrRecommendationPending when that takes too long.
+func (hs *RecommendationHistoryService) SubmitFeedback(ctx context.Context, id string, request domain.FeedbackRequest) (domain.RecommendationFeedback, error) {
+	if err := hs.awaitRecord(ctx, id); err != nil {
+		return domain.RecommendationFeedback{}, err
+	}
+	record, err := hs.historyRepository.GetRecommendation(ctx, id)
+	if err != nil {
+		return domain.RecommendationFeedback{}, fmt.Errorf("failed to get recommendation: %w", err)
+	}
+	if caller := hs.clientID(ctx); caller == "" || caller != record.ClientID {
+		return domain.RecommendationFeedback{}, ErrForeignRecommendation
+	}
+
+	feedback := domain.RecommendationFeedback{
+		RecommendationID: id,
+		Vote:             normalizeName(request.Vote),
+	}
+	if preferred := strings.TrimSpace(request.PreferredStyle); preferred != "" {
+		beerStyle, err := hs.catalogStyle(ctx, preferred)
+		if err != nil {
+			return domain.RecommendationFeedback{}, err
+		}
+		feedback.PreferredStyleUUID = beerStyle.UUID
+		feedback.PreferredStyle = beerStyle.Name
+	}
+
+	saved, err := hs.historyRepository.SaveFeedback(ctx, feedback)
+	if err != nil {
+		return domain.RecommendationFeedback{}, fmt.Errorf("failed to save feedback: %w", err)
+	}
+
+	metrics.CountFeedback(saved.Vote)
+	return saved, nil
+}
+
+// catalogStyle returns the style called name, ignoring case.
+func (hs *RecommendationHistoryService) catalogStyle(ctx context.Context, name string) (domain.BeerStyle, error) {
+	beerStyles, err := hs.beerService.ListAllBeerStyles(ctx)
+	if err != nil {
+		return domain.BeerStyle{}, fmt.Errorf("failed to get beer styles: %w", err)
+	}
+
+	for _, beerStyle := range beerStyles {
+		if normalizeName(beerStyle.Name) == normalizeName(name) {
+			return beerStyle, nil
+		}
+	}
+	return domain.BeerStyle{}, fmt.Errorf("%w: '%s'", ErrUnknownBeerStyle, name)
+}
+
+// FeedbackScores scores the styles from the feedback on the recommendations
+// in the temperature bucket of bucketSize degrees holding temperature. The
+// count cannot use an index, so the scores of each bucket are reused for
+// cfg.FeedbackCacheTTL and new votes show up once they expire. The returned
+// map is shared and must not be modified.
+func (hs *RecommendationHistoryService) FeedbackScores(ctx context.Context, temperature, bucketSize float64) (map[string]float64, error) {
+	key := feedbackBucket{bucket: TemperatureBucket(temperature, bucketSize), size: bucketSize}
+	if scores, ok := hs.cachedFeedbackScores(key); ok {
+		return scores, nil
+	}
+
+	tallies, err := hs.historyRepository.CountFeedback(ctx, temperature, bucketSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count feedback: %w", err)
+	}
+	scores := ScoreFeedback(tallies)
+
+	if hs.cfg.FeedbackCacheTTL > 0 {
+		hs.feedbackMu.Lock()
+		now := hs.now()
+		for cached, entry := range hs.feedbackCache {
+			if !now.Before(entry.expiresAt) {
+				delete(hs.feedbackCache, cached)
+			}
+		}
+		hs.feedbackCache[key] = cachedFeedback{scores: scores, expiresAt: now.Add(hs.cfg.FeedbackCacheTTL)}
+		hs.feedbackMu.Unlock()
+	}
+	return scores, nil
+}
+
+func (hs *RecommendationHistoryService) cachedFeedbackScores(key feedbackBucket) (map[string]float64, bool) {
+	hs.feedbackMu.Lock()
+	defer hs.feedbackMu.Unlock()
+
+	entry, ok := hs.feedbackCache[key]
+	if !ok || !hs.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.scores, true
+}
+
+// EvaluateRankings replays the feedback on the recommendations created in
+// [from, to) against the distance ranking and the feedback ranking with
+// each of weights. See EvaluateRankings for the metrics.
+func (hs *RecommendationHistoryService) EvaluateRankings(ctx context.Context, from, to time.Time, weights []float64, bucketSize float64) ([]domain.RankingEvaluation, error) {
+	beerStyles, err := hs.beerService.ListAllBeerStyles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get beer styles: %w", err)
+	}
+	if len(beerStyles) == 0 {
+		return nil, fmt.Errorf("no beer styles found")
+	}
+
+	ratings, err := hs.historyRepository.ListRatedRecommendations(ctx, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rated recommendations: %w", err)
+	}
+
+	return EvaluateRankings(beerStyles, ratings, weights, bucketSize), nil
+}
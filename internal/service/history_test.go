@@ -0,0 +1,317 @@
+package service
+
+import (
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeHistoryRepository struct {
+	mu              sync.Mutex
+	batches         [][]domain.RecommendationRecord
+	purgedBefore    time.Time
+	buckets         []domain.TemperatureBucketCount
+	lastFrom        time.Time
+	lastTo          time.Time
+	lastBucket      float64
+	feedback        []domain.RecommendationFeedback
+	countedFeedback int
+	// saveFailures fails as many SaveRecommendations calls before the next
+	// one succeeds.
+	saveFailures int
+	saveCalls    int
+}
+
+func (f *fakeHistoryRepository) SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.saveCalls++
+	if f.saveFailures > 0 {
+		f.saveFailures--
+		return errors.New("connection refused")
+	}
+	f.batches = append(f.batches, append([]domain.RecommendationRecord(nil), records...))
+	return nil
+}
+
+func (f *fakeHistoryRepository) CountByBeerStyle(ctx context.Context, from, to time.Time) ([]domain.BeerStyleCount, error) {
+	f.lastFrom, f.lastTo = from, to
+	return []domain.BeerStyleCount{{BeerStyle: "IPA", Count: 3}, {BeerStyle: "Stout", Count: 2}}, nil
+}
+
+func (f *fakeHistoryRepository) CountByTemperature(ctx context.Context, from, to time.Time, bucketSize float64) ([]domain.TemperatureBucketCount, error) {
+	f.lastBucket = bucketSize
+	return f.buckets, nil
+}
+
+func (f *fakeHistoryRepository) CountByDay(ctx context.Context, from, to time.Time) ([]domain.DayCount, error) {
+	return nil, nil
+}
+
+func (f *fakeHistoryRepository) PurgeRecommendations(ctx context.Context, before time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.purgedBefore = before
+	return 4, nil
+}
+
+func (f *fakeHistoryRepository) GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, batch := range f.batches {
+		for _, record := range batch {
+			if record.ID == id {
+				return record, nil
+			}
+		}
+	}
+	if id != "4f0e8a52-0000-4000-8000-000000000001" {
+		return domain.RecommendationRecord{}, sql.ErrNoRows
+	}
+	return domain.RecommendationRecord{ID: id, Temperature: 8, BeerStyle: "IPA", ClientID: "api_key:ci"}, nil
+}
+
+func (f *fakeHistoryRepository) SaveFeedback(ctx context.Context, feedback domain.RecommendationFeedback) (domain.RecommendationFeedback, error) {
+	f.feedback = append(f.feedback, feedback)
+	return feedback, nil
+}
+
+func (f *fakeHistoryRepository) CountFeedback(ctx context.Context, temperature, bucketSize float64) ([]domain.FeedbackTally, error) {
+	f.countedFeedback++
+	return []domain.FeedbackTally{{BeerStyleUUID: "ipa-uuid", Up: 3}, {BeerStyleUUID: "stout-uuid", Down: 5}, {BeerStyleUUID: "stout-uuid", Preferred: 5}}, nil
+}
+
+func (f *fakeHistoryRepository) ListRatedRecommendations(ctx context.Context, from, to time.Time) ([]domain.RatedRecommendation, error) {
+	return nil, nil
+}
+
+var historyNow = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+var historyStyles = []domain.BeerStyle{
+	{UUID: "ipa-uuid", Name: "IPA", TempMin: 7, TempMax: 10},
+	{UUID: "stout-uuid", Name: "Stout", TempMin: 10, TempMax: 13},
+}
+
+func setupHistoryService(repo *fakeHistoryRepository, cfg config.HistoryConfig) *RecommendationHistoryService {
+	return NewRecommendationHistoryService(repo, &stubBeerService{styles: historyStyles}, cfg, func(context.Context) string { return "api_key:ci" }, func() time.Time { return historyNow }, logging.Discard())
+}
+
+func TestRecommendationHistoryService_WritesBatchesOnClose(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+	history.Start()
+
+	var ids []string
+	for _, style := range []string{"IPA", "Stout", "Lager"} {
+		ids = append(ids, history.Record(context.Background(), domain.RecommendationRecord{BeerStyle: style}))
+	}
+	history.Close()
+
+	if len(repo.batches) != 2 || len(repo.batches[0]) != 2 || len(repo.batches[1]) != 1 {
+		t.Fatalf("expected a full batch and the rest on close, got %v", repo.batches)
+	}
+	first := repo.batches[0][0]
+	if first.ID != ids[0] || first.ID == "" || first.ClientID != "api_key:ci" || !first.CreatedAt.Equal(historyNow) {
+		t.Errorf("unexpected record: %+v", first)
+	}
+	if id := history.Record(context.Background(), domain.RecommendationRecord{}); id != "" {
+		t.Errorf("expected no record after Close, got %q", id)
+	}
+}
+
+func TestRecommendationHistoryService_RetriesFailedBatches(t *testing.T) {
+	repo := &fakeHistoryRepository{saveFailures: saveAttempts - 1}
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	history.saveBackoff = time.Millisecond
+
+	history.save([]domain.RecommendationRecord{{BeerStyle: "IPA"}})
+	if len(repo.batches) != 1 || repo.saveCalls != saveAttempts {
+		t.Fatalf("expected the batch stored on the last attempt, got %d batches after %d calls", len(repo.batches), repo.saveCalls)
+	}
+
+	repo.saveFailures, repo.saveCalls = saveAttempts, 0
+	history.save([]domain.RecommendationRecord{{BeerStyle: "Stout"}})
+	if len(repo.batches) != 1 || repo.saveCalls != saveAttempts {
+		t.Errorf("expected the batch dropped after %d attempts, got %d batches after %d calls", saveAttempts, len(repo.batches), repo.saveCalls)
+	}
+}
+
+func TestRecommendationHistoryService_DropsWhenBufferIsFull(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	// Not started: nothing drains the buffer.
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 1, BatchSize: 10, FlushInterval: time.Hour})
+
+	if id := history.Record(context.Background(), domain.RecommendationRecord{}); id == "" {
+		t.Fatal("expected the first record to be queued")
+	}
+	if id := history.Record(context.Background(), domain.RecommendationRecord{}); id != "" {
+		t.Errorf("expected the record to be dropped, got %q", id)
+	}
+
+	disabled := setupHistoryService(repo, config.HistoryConfig{BufferSize: 1})
+	if id := disabled.Record(context.Background(), domain.RecommendationRecord{}); id != "" {
+		t.Errorf("expected no record when disabled, got %q", id)
+	}
+}
+
+func TestRecommendationHistoryService_Purge(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 1, Retention: 48 * time.Hour})
+
+	purged, err := history.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != 4 || !repo.purgedBefore.Equal(historyNow.Add(-48*time.Hour)) {
+		t.Errorf("expected the records before %s to be purged, got %d before %s", historyNow.Add(-48*time.Hour), purged, repo.purgedBefore)
+	}
+}
+
+func TestRecommendationHistoryService_GetRecommendationAnalytics(t *testing.T) {
+	repo := &fakeHistoryRepository{buckets: []domain.TemperatureBucketCount{{Min: 5, Count: 4}, {Min: 10, Count: 1}}}
+	history := setupHistoryService(repo, config.HistoryConfig{})
+
+	analytics, err := history.GetRecommendationAnalytics(context.Background(), domain.AnalyticsQuery{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !repo.lastTo.Equal(historyNow) || !repo.lastFrom.Equal(historyNow.Add(-DefaultAnalyticsWindow)) || repo.lastBucket != DefaultTemperatureBucket {
+		t.Errorf("expected the default window and bucket, got %s - %s by %v", repo.lastFrom, repo.lastTo, repo.lastBucket)
+	}
+	if analytics.Total != 5 {
+		t.Errorf("expected 5 recommendations, got %d", analytics.Total)
+	}
+	if analytics.ByTemperature[0].Max != 10 || analytics.ByTemperature[1].Max != 15 {
+		t.Errorf("expected the bucket upper bounds, got %+v", analytics.ByTemperature)
+	}
+	if analytics.ByDay == nil {
+		t.Error("expected an empty list of days rather than null")
+	}
+}
+
+func TestRecommendationHistoryService_SubmitFeedback(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	history := setupHistoryService(repo, config.HistoryConfig{})
+	ctx := context.Background()
+
+	feedback, err := history.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-000000000001", domain.FeedbackRequest{Vote: " Down", PreferredStyle: "stout "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if feedback.Vote != domain.VoteDown || feedback.PreferredStyleUUID != "stout-uuid" || feedback.PreferredStyle != "Stout" || len(repo.feedback) != 1 {
+		t.Errorf("expected a down vote preferring Stout, got %+v", feedback)
+	}
+
+	if _, err := history.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-000000000001", domain.FeedbackRequest{Vote: "up", PreferredStyle: "Mead"}); !errors.Is(err, ErrUnknownBeerStyle) {
+		t.Errorf("expected ErrUnknownBeerStyle, got %v", err)
+	}
+	if _, err := history.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-00000000ffff", domain.FeedbackRequest{Vote: "up"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+	if len(repo.feedback) != 1 {
+		t.Errorf("expected only the first feedback to be saved, got %+v", repo.feedback)
+	}
+}
+
+func TestRecommendationHistoryService_SubmitFeedback_OnlyFromServedClient(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	ctx := context.Background()
+
+	for _, caller := range []string{"api_key:other", ""} {
+		history := NewRecommendationHistoryService(repo, &stubBeerService{styles: historyStyles}, config.HistoryConfig{}, func(context.Context) string { return caller }, nil, logging.Discard())
+		if _, err := history.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-000000000001", domain.FeedbackRequest{Vote: "up"}); !errors.Is(err, ErrForeignRecommendation) {
+			t.Errorf("%q: expected ErrForeignRecommendation, got %v", caller, err)
+		}
+	}
+	if len(repo.feedback) != 0 {
+		t.Errorf("expected no feedback to be saved, got %+v", repo.feedback)
+	}
+}
+
+func TestRecommendationHistoryService_SubmitFeedback_WaitsForWriter(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	history.Start()
+	defer history.Close()
+
+	recommendations := setupRecommendationService(t, historyStyles)
+	recommendations.history = history
+	response, err := recommendations.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8})
+	if err != nil || response.ID == "" {
+		t.Fatalf("Expected a recorded recommendation, got %+v, %v", response, err)
+	}
+
+	// The flush interval is an hour: the feedback must not wait for it.
+	if _, err := history.SubmitFeedback(context.Background(), response.ID, domain.FeedbackRequest{Vote: domain.VoteUp}); err != nil {
+		t.Fatalf("Expected the feedback on the buffered recommendation to be saved, got %v", err)
+	}
+	if len(repo.batches) != 1 || len(repo.feedback) != 1 {
+		t.Errorf("Expected the recommendation flushed and rated, got %d batches and %v", len(repo.batches), repo.feedback)
+	}
+}
+
+func TestRecommendationHistoryService_SubmitFeedback_Pending(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	// Not started: the record stays buffered.
+	history := setupHistoryService(repo, config.HistoryConfig{Enabled: true, BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	id := history.Record(context.Background(), domain.RecommendationRecord{BeerStyle: "IPA"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := history.SubmitFeedback(ctx, id, domain.FeedbackRequest{Vote: domain.VoteUp}); !errors.Is(err, ErrRecommendationPending) {
+		t.Errorf("Expected ErrRecommendationPending, got %v", err)
+	}
+}
+
+func TestRecommendationHistoryService_FeedbackScores(t *testing.T) {
+	history := setupHistoryService(&fakeHistoryRepository{}, config.HistoryConfig{})
+
+	scores, err := history.FeedbackScores(context.Background(), 8, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// IPA: 3 up over 3 votes and the prior of 5. Stout: the tallies of
+	// its UUID merge and 5 preferred cancel 5 down.
+	if scores["ipa-uuid"] != 3.0/8 || scores["stout-uuid"] != 0 || len(scores) != 2 {
+		t.Errorf("unexpected scores: %v", scores)
+	}
+}
+
+func TestRecommendationHistoryService_FeedbackScores_Cached(t *testing.T) {
+	repo := &fakeHistoryRepository{}
+	now := historyNow
+	history := NewRecommendationHistoryService(repo, &stubBeerService{styles: historyStyles}, config.HistoryConfig{FeedbackCacheTTL: time.Minute}, nil, func() time.Time { return now }, logging.Discard())
+
+	score := func(temperature, bucketSize float64) {
+		t.Helper()
+		if _, err := history.FeedbackScores(context.Background(), temperature, bucketSize); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	score(8, 5)
+	score(6, 5)
+	if repo.countedFeedback != 1 {
+		t.Errorf("expected one count for the same bucket, got %d", repo.countedFeedback)
+	}
+
+	score(11, 5)
+	score(8, 2)
+	if repo.countedFeedback != 3 {
+		t.Errorf("expected a count per bucket and bucket size, got %d", repo.countedFeedback)
+	}
+
+	now = now.Add(time.Minute)
+	score(8, 5)
+	if repo.countedFeedback != 4 {
+		t.Errorf("expected a new count once the TTL elapsed, got %d", repo.countedFeedback)
+	}
+}
@@ -1,20 +1,28 @@
 package service
 
-import "backend-test/internal/domain"
+import (
+	"backend-test/internal/domain"
+	"context"
+)
 
 type BeerServiceInterface interface {
-	ListAllBeerStyles() ([]domain.BeerStyle, error)
-	GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error)
-	CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	DeleteBeerStyle(beerUUID string) error
+	ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error)
+	GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	DeleteBeerStyle(ctx context.Context, beerUUID string) error
 }
 
 type ValidationServiceInterface interface {
 	ValidateTemperatureRange(beerStyle domain.BeerStyle) error
 	ValidateTemperatureInput(temperature float64) error
-	ValidateUniqueNameForCreate(name string) error
-	ValidateUniqueNameForUpdate(name string, excludeUUID string) error
+	ValidateABV(abv *float64) error
+	ValidateRecommendationPreferences(request domain.TemperatureRequest) error
+	ValidatePlaylistMapping(request domain.BeerStylePlaylistRequest) error
+	ValidateAPIKeyRequest(request domain.APIKeyRequest) error
+	ValidateFeedbackRequest(request domain.FeedbackRequest) error
+	ValidateUniqueNameForCreate(ctx context.Context, name string) error
+	ValidateUniqueNameForUpdate(ctx context.Context, name string, excludeUUID string) error
 	IsNoRowsError(err error) bool
 	ValidateUUID(uuidStr string) error
 }
@@ -24,5 +32,52 @@ type UpdateServiceInterface interface {
 }
 
 type RecommendationServiceInterface interface {
-	GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error)
+	GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error)
+	ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error)
+}
+
+// RecommendationRecorder stores served recommendations. Record returns the
+// ID of the stored record, or "" when it was not kept.
+type RecommendationRecorder interface {
+	Record(ctx context.Context, record domain.RecommendationRecord) string
+}
+
+// FeedbackScorer scores the beer styles, by normalized name, from the
+// feedback on the recommendations in the temperature bucket of bucketSize
+// degrees holding temperature.
+type FeedbackScorer interface {
+	FeedbackScores(ctx context.Context, temperature, bucketSize float64) (map[string]float64, error)
+}
+
+// RecommendationHistory records the recommendations and scores the styles
+// from their feedback.
+type RecommendationHistory interface {
+	RecommendationRecorder
+	FeedbackScorer
+}
+
+type RecommendationHistoryServiceInterface interface {
+	GetRecommendationAnalytics(ctx context.Context, query domain.AnalyticsQuery) (*domain.RecommendationAnalytics, error)
+}
+
+type FeedbackServiceInterface interface {
+	SubmitFeedback(ctx context.Context, id string, request domain.FeedbackRequest) (domain.RecommendationFeedback, error)
+}
+
+type PlaylistMappingServiceInterface interface {
+	ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error)
+	ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) (map[string][]domain.BeerStylePlaylist, error)
+	PinPlaylist(ctx context.Context, beerStyleUUID string, request domain.BeerStylePlaylistRequest) (domain.BeerStylePlaylist, error)
+	UnpinPlaylist(ctx context.Context, beerStyleUUID string, playlistUUID string) error
+}
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, request domain.APIKeyRequest) (domain.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyUUID string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (domain.Principal, error)
+}
+
+type HealthServiceInterface interface {
+	Check(ctx context.Context) domain.HealthReport
 }
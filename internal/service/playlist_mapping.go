@@ -0,0 +1,109 @@
+package service
+
+import (
+	"backend-test/external/music"
+	"backend-test/internal/domain"
+	"backend-test/internal/storage/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// MaxPlaylistWeight is the largest weight of a pinned playlist, matching the
+// beer_style_playlists weight constraint.
+const MaxPlaylistWeight = 100
+
+type PlaylistMappingService struct {
+	playlistRepository repository.PlaylistMappingRepositoryInterface
+	musicProviders     *music.Registry
+	logger             *slog.Logger
+}
+
+func NewPlaylistMappingService(playlistRepo repository.PlaylistMappingRepositoryInterface, musicProviders *music.Registry, logger *slog.Logger) *PlaylistMappingService {
+	return &PlaylistMappingService{
+		playlistRepository: playlistRepo,
+		musicProviders:     musicProviders,
+		logger:             logger.With("service", "PlaylistMappingService"),
+	}
+}
+
+func (ps *PlaylistMappingService) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	playlists, err := ps.playlistRepository.ListPlaylistsForBeerStyle(ctx, beerStyleUUID)
+	if err != nil {
+		return []domain.BeerStylePlaylist{}, err
+	}
+	return playlists, nil
+}
+
+// ListPlaylistsForBeerStyles returns the pinned playlists of each beer style
+// with a single query. Styles without playlists have no entry.
+func (ps *PlaylistMappingService) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) (map[string][]domain.BeerStylePlaylist, error) {
+	byStyle := make(map[string][]domain.BeerStylePlaylist, len(beerStyleUUIDs))
+	if len(beerStyleUUIDs) == 0 {
+		return byStyle, nil
+	}
+
+	playlists, err := ps.playlistRepository.ListPlaylistsForBeerStyles(ctx, beerStyleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, playlist := range playlists {
+		byStyle[playlist.BeerStyleUUID] = append(byStyle[playlist.BeerStyleUUID], playlist)
+	}
+	return byStyle, nil
+}
+
+func (ps *PlaylistMappingService) PinPlaylist(ctx context.Context, beerStyleUUID string, request domain.BeerStylePlaylistRequest) (domain.BeerStylePlaylist, error) {
+	provider := strings.ToLower(strings.TrimSpace(request.Provider))
+	if provider == "" {
+		provider = ps.musicProviders.DefaultProvider()
+	}
+
+	weight := request.Weight
+	if weight == 0 {
+		weight = 1
+	}
+
+	if err := ps.validatePlaylistExists(ctx, provider, request.PlaylistID); err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+
+	pinned, err := ps.playlistRepository.CreatePlaylistMapping(ctx, domain.BeerStylePlaylist{
+		BeerStyleUUID: beerStyleUUID,
+		Provider:      provider,
+		PlaylistID:    request.PlaylistID,
+		Weight:        weight,
+	})
+	if err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+	return pinned, nil
+}
+
+func (ps *PlaylistMappingService) UnpinPlaylist(ctx context.Context, beerStyleUUID string, playlistUUID string) error {
+	return ps.playlistRepository.DeletePlaylistMapping(ctx, beerStyleUUID, playlistUUID)
+}
+
+// validatePlaylistExists checks the playlist against the provider. When the
+// provider cannot be reached the playlist is accepted unchecked.
+func (ps *PlaylistMappingService) validatePlaylistExists(ctx context.Context, providerName string, playlistID string) error {
+	provider, err := ps.musicProviders.Get(providerName)
+	if err != nil {
+		ps.logger.WarnContext(ctx, "pinning playlist without verification", "provider", providerName, "playlist_id", playlistID, "err", err)
+		return nil
+	}
+
+	_, err = provider.GetPlaylist(ctx, playlistID)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, music.ErrPlaylistNotFound) {
+		return fmt.Errorf("playlist '%s' not found on provider '%s'", playlistID, providerName)
+	}
+
+	ps.logger.WarnContext(ctx, "could not verify playlist, pinning without verification", "provider", providerName, "playlist_id", playlistID, "err", err)
+	return nil
+}
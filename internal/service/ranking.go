@@ -0,0 +1,84 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"math"
+	"sort"
+)
+
+const (
+	// RankingDistance ranks the styles by the distance between the
+	// temperature and the midpoint of their range.
+	RankingDistance = "distance"
+	// RankingFeedback subtracts from that distance the weighted feedback
+	// score of each style in the temperature bucket.
+	RankingFeedback = "feedback"
+)
+
+// feedbackPrior is the number of neutral votes every style starts with, so
+// that a couple of votes cannot swing the ranking on their own.
+const feedbackPrior = 5
+
+// FeedbackScore is between -1 and 1: positive when the style was approved
+// or preferred more often than rejected. Styles with few votes stay close
+// to 0.
+func FeedbackScore(tally domain.FeedbackTally) float64 {
+	positive := float64(tally.Up + tally.Preferred)
+	negative := float64(tally.Down)
+	return (positive - negative) / (positive + negative + feedbackPrior)
+}
+
+// ScoreFeedback returns the feedback score of each tallied style, by UUID.
+func ScoreFeedback(tallies []domain.FeedbackTally) map[string]float64 {
+	merged := make(map[string]domain.FeedbackTally, len(tallies))
+	for _, tally := range tallies {
+		total := merged[tally.BeerStyleUUID]
+		total.Up += tally.Up
+		total.Down += tally.Down
+		total.Preferred += tally.Preferred
+		merged[tally.BeerStyleUUID] = total
+	}
+
+	scores := make(map[string]float64, len(merged))
+	for uuid, tally := range merged {
+		scores[uuid] = FeedbackScore(tally)
+	}
+	return scores
+}
+
+// RankBeerStylesWithFeedback orders styles by their distance to
+// temperature less weight degrees times their score, keyed by UUID; styles
+// without a score count as 0. Ties are broken by name,
+// alphabetically.
+func RankBeerStylesWithFeedback(styles []domain.BeerStyle, temperature float64, scores map[string]float64, weight float64) []domain.RankedBeerStyle {
+	ranked := make([]domain.RankedBeerStyle, 0, len(styles))
+	for _, beerStyle := range styles {
+		midpoint := (beerStyle.TempMin + beerStyle.TempMax) / 2
+		distance := abs(temperature - midpoint)
+		score := scores[beerStyle.UUID]
+		ranked = append(ranked, domain.RankedBeerStyle{
+			Style:            beerStyle,
+			Midpoint:         midpoint,
+			Distance:         distance,
+			FeedbackScore:    score,
+			AdjustedDistance: distance - weight*score,
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].AdjustedDistance != ranked[j].AdjustedDistance {
+			return ranked[i].AdjustedDistance < ranked[j].AdjustedDistance
+		}
+		return ranked[i].Style.Name < ranked[j].Style.Name
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+// TemperatureBucket numbers the bucket of size degrees holding
+// temperature, as the feedback is aggregated in the database.
+func TemperatureBucket(temperature, size float64) float64 {
+	return math.Floor(temperature / size)
+}
@@ -1,29 +1,102 @@
 package service
 
 import (
-	"backend-test/external/spotify"
+	"backend-test/external/music"
 	config "backend-test/internal/cmd/server"
 	"backend-test/internal/domain"
+	"backend-test/internal/metrics"
+	"backend-test/internal/tracing"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
 
-	spotifyapi "github.com/zmb3/spotify/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	// DefaultTrackLimit is the number of tracks returned when the request
+	// does not set track_limit.
+	DefaultTrackLimit = 10
+	// MaxPlaylistTracks is the maximum number of tracks returned for a playlist.
+	MaxPlaylistTracks = 50
+)
+
+// ConstraintError reports which recommendation preference eliminated every
+// candidate beer style (or playlist).
+type ConstraintError struct {
+	Constraint string
+	Reason     string
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("no beer style satisfies constraint '%s': %s", e.Constraint, e.Reason)
+}
+
 type RecommendationService struct {
-	beerService    BeerServiceInterface
-	spotifyService *spotify.SpotifyService
+	beerService            BeerServiceInterface
+	playlistMappingService PlaylistMappingServiceInterface
+	musicProviders         *music.Registry
+	history                RecommendationHistory
+	fallbackChain          []string
+	defaultPlaylistIDs     map[string]string
+	ranking                string
+	feedbackWeight         float64
+	feedbackBucketSize     float64
+	now                    func() time.Time
+	logger                 *slog.Logger
 }
 
-func NewRecommendationService(beerService BeerServiceInterface, spotifyService *spotify.SpotifyService) *RecommendationService {
+// NewRecommendationService returns the recommendation service. Served
+// recommendations are recorded in history, which may be nil; without it the
+// styles are ranked by distance whatever cfg.Ranking.
+func NewRecommendationService(beerService BeerServiceInterface, playlistMappingService PlaylistMappingServiceInterface, musicProviders *music.Registry, history RecommendationHistory, cfg config.RecommendationConfig, now func() time.Time, logger *slog.Logger) *RecommendationService {
+	if now == nil {
+		now = time.Now
+	}
+	logger = logger.With("service", "RecommendationService")
+
+	ranking := normalizeName(cfg.Ranking)
+	if ranking != RankingFeedback || history == nil {
+		ranking = RankingDistance
+	}
+	feedbackBucketSize := cfg.FeedbackBucketSize
+	if feedbackBucketSize <= 0 {
+		feedbackBucketSize = DefaultTemperatureBucket
+	}
+
 	return &RecommendationService{
-		beerService:    beerService,
-		spotifyService: spotifyService,
+		beerService:            beerService,
+		playlistMappingService: playlistMappingService,
+		musicProviders:         musicProviders,
+		history:                history,
+		fallbackChain:          parseFallbackChain(cfg.FallbackChain, logger),
+		defaultPlaylistIDs:     cfg.DefaultPlaylistIDs,
+		ranking:                ranking,
+		feedbackWeight:         cfg.FeedbackWeight,
+		feedbackBucketSize:     feedbackBucketSize,
+		now:                    now,
+		logger:                 logger,
+	}
+}
+
+func (rs *RecommendationService) FindBestBeerStyleForTemperature(ctx context.Context, temperature float64) (*domain.BeerStyle, error) {
+	ranked, err := rs.rankBeerStyles(ctx, domain.TemperatureRequest{Temperature: temperature})
+	if err != nil {
+		return nil, err
 	}
+	return &ranked[0].Style, nil
 }
 
-func (rs *RecommendationService) FindBestBeerStyleForTemperature(temperature float64) (*domain.BeerStyle, error) {
-	allBeerStyles, err := rs.beerService.ListAllBeerStyles()
+// rankBeerStyles ranks the styles that satisfy the request preferences.
+func (rs *RecommendationService) rankBeerStyles(ctx context.Context, request domain.TemperatureRequest) ([]domain.RankedBeerStyle, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RecommendationService.rankBeerStyles")
+	defer span.End()
+
+	allBeerStyles, err := rs.beerService.ListAllBeerStyles(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get beer styles: %w", err)
 	}
@@ -32,122 +105,286 @@ func (rs *RecommendationService) FindBestBeerStyleForTemperature(temperature flo
 		return nil, fmt.Errorf("no beer styles found")
 	}
 
-	type candidateStyle struct {
-		style    *domain.BeerStyle
-		distance float64
-		average  float64
-	}
-
-	var candidates []candidateStyle
-	var minDistance float64 = 999999
-
-	for i := range allBeerStyles {
-		beerStyle := &allBeerStyles[i]
-		average := (beerStyle.TempMin + beerStyle.TempMax) / 2
-		distance := abs(temperature - average)
-
-		if distance < minDistance {
-			minDistance = distance
-			candidates = []candidateStyle{{
-				style:    beerStyle,
-				distance: distance,
-				average:  average,
-			}}
-		} else if distance == minDistance {
-			candidates = append(candidates, candidateStyle{
-				style:    beerStyle,
-				distance: distance,
-				average:  average,
-			})
-		}
+	allBeerStyles, err = applyPreferences(allBeerStyles, request)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no suitable beer style found for temperature %.1f°C", temperature)
-	}
+	ranked, ranking := rs.rank(ctx, allBeerStyles, request.Temperature)
+	span.SetAttributes(attribute.Int("beer.candidates", len(ranked)), attribute.String("beer.ranking", ranking))
+
+	return ranked, nil
+}
 
-	if len(candidates) == 1 {
-		return candidates[0].style, nil
+// rank orders the styles with the configured strategy and returns the one
+// used: when the feedback cannot be read the ranking falls back to distance
+// rather than failing the recommendation.
+func (rs *RecommendationService) rank(ctx context.Context, styles []domain.BeerStyle, temperature float64) ([]domain.RankedBeerStyle, string) {
+	if rs.ranking != RankingFeedback {
+		return RankBeerStyles(styles, temperature), RankingDistance
 	}
 
-	for i := 0; i < len(candidates)-1; i++ {
-		for j := i + 1; j < len(candidates); j++ {
-			if candidates[i].style.Name > candidates[j].style.Name {
-				candidates[i], candidates[j] = candidates[j], candidates[i]
-			}
-		}
+	scores, err := rs.history.FeedbackScores(ctx, temperature, rs.feedbackBucketSize)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "feedback not available, ranking by distance", "err", err)
+		return RankBeerStyles(styles, temperature), RankingDistance
 	}
+	return RankBeerStylesWithFeedback(styles, temperature, scores, rs.feedbackWeight), RankingFeedback
+}
 
-	bestMatch := candidates[0].style
-	return bestMatch, nil
+// RankBeerStyles orders styles by the distance between temperature and the
+// midpoint of each style range. Ties are broken by name, alphabetically.
+func RankBeerStyles(styles []domain.BeerStyle, temperature float64) []domain.RankedBeerStyle {
+	return RankBeerStylesWithFeedback(styles, temperature, nil, 0)
 }
-func (rs *RecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
-	beerStyle, err := rs.FindBestBeerStyleForTemperature(temperature)
+
+func (rs *RecommendationService) GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (response *domain.RecommendationResponse, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RecommendationService.GetRecommendationForTemperature",
+		trace.WithAttributes(attribute.Float64("beer.temperature", request.Temperature)))
+	defer func() { tracing.End(span, err) }()
+	start := time.Now()
+
+	ranked, err := rs.rankBeerStyles(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	var tracks []domain.TrackInfo
-	var playlistName string
-
-	spotifyService := config.GetSpotifyService()
+	provider, err := rs.musicProviders.Get(request.Provider)
+	if err != nil {
+		rs.logger.WarnContext(ctx, "music provider not available", "provider", request.Provider, "err", err)
+		return nil, err
+	}
 
-	if spotifyService != nil {
-		playlist, err := spotifyService.SearchPlaylistByName(beerStyle.Name)
-		if err != nil {
-			log.Printf("Failed to find Spotify playlist for %s: %v", beerStyle.Name, err)
-			return nil, fmt.Errorf("no playlist found for beer style '%s'", beerStyle.Name)
+	var shuffleSeed *int64
+	var seed int64
+	if request.Shuffle {
+		seed = rs.now().UnixNano()
+		if request.ShuffleSeed != nil {
+			seed = *request.ShuffleSeed
 		}
+		shuffleSeed = &seed
+	}
 
-		playlistName = playlist.Name
-		tracks = rs.convertSpotifyTracks(playlist)
-
-		if len(tracks) == 0 {
-			return nil, fmt.Errorf("playlist '%s' found but contains no valid tracks", playlistName)
-		}
-	} else {
-		log.Println("Spotify service not available")
-		return nil, fmt.Errorf("spotify service unavailable")
+	resolved, err := rs.resolvePlaylist(ctx, provider, ranked, request, seed)
+	if err != nil {
+		return nil, err
 	}
 
-	response := &domain.RecommendationResponse{
-		BeerStyle: beerStyle.Name,
+	response = &domain.RecommendationResponse{
+		BeerStyle: resolved.style.Name,
 		Playlist: domain.PlaylistInfo{
-			Name:   playlistName,
-			Tracks: tracks,
+			ID:          resolved.id,
+			Name:        resolved.name,
+			Provider:    provider.Name(),
+			Tracks:      resolved.tracks,
+			ShuffleSeed: shuffleSeed,
 		},
+		Fallback: resolved.fallbackInfo(),
 	}
 
+	span.SetAttributes(
+		attribute.String("beer.style", response.BeerStyle),
+		attribute.String("music.provider", response.Playlist.Provider),
+		attribute.String("playlist.fallback_step", resolved.fallback.Step),
+	)
+	metrics.CountRecommendation(response.BeerStyle, response.Playlist.Provider)
+
+	if rs.history != nil {
+		response.ID = rs.history.Record(ctx, domain.RecommendationRecord{
+			Temperature:   request.Temperature,
+			BeerStyleUUID: resolved.style.UUID,
+			BeerStyle:     resolved.style.Name,
+			PlaylistID:    resolved.id,
+			Provider:      provider.Name(),
+			FallbackStep:  resolved.fallback.Step,
+			LatencyMs:     time.Since(start).Milliseconds(),
+		})
+	}
 	return response, nil
 }
 
-func (rs *RecommendationService) convertSpotifyTracks(playlist *spotifyapi.FullPlaylist) []domain.TrackInfo {
-	tracks := make([]domain.TrackInfo, 0)
-	if len(playlist.Tracks.Tracks) > 0 {
-		maxTracks := len(playlist.Tracks.Tracks)
-		if maxTracks > 10 {
-			maxTracks = 10
+func convertTracks(playlist *music.Playlist) []domain.TrackInfo {
+	tracks := make([]domain.TrackInfo, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		artistName := "Unknown Artist"
+		if len(track.Artists) > 0 {
+			artistName = track.Artists[0]
 		}
 
-		for i := 0; i < maxTracks; i++ {
-			track := playlist.Tracks.Tracks[i].Track
-			if track.Name != "" {
-				spotifyLink := fmt.Sprintf("https://open.spotify.com/track/%s", track.ID)
+		tracks = append(tracks, domain.TrackInfo{
+			Name:        track.Name,
+			Artist:      artistName,
+			Link:        track.Link,
+			Artists:     track.Artists,
+			Album:       track.Album,
+			DurationMs:  track.DurationMs,
+			Explicit:    track.Explicit,
+			Popularity:  track.Popularity,
+			PreviewURL:  track.PreviewURL,
+			ISRC:        track.ISRC,
+			AlbumArtURL: track.AlbumArtURL,
+		})
+	}
+	return tracks
+}
+
+// selectTracks applies the track selection options of the request: optional
+// seeded shuffle, one track per primary artist and the track limit. Without
+// options it keeps the first DefaultTrackLimit tracks in playlist order.
+func selectTracks(tracks []domain.TrackInfo, request domain.TemperatureRequest, seed int64) []domain.TrackInfo {
+	selected := make([]domain.TrackInfo, len(tracks))
+	copy(selected, tracks)
+
+	if request.Shuffle {
+		random := rand.New(rand.NewSource(seed))
+		random.Shuffle(len(selected), func(i, j int) {
+			selected[i], selected[j] = selected[j], selected[i]
+		})
+	}
+
+	if request.DistinctArtists {
+		seen := make(map[string]bool, len(selected))
+		distinct := selected[:0]
+		for _, track := range selected {
+			artist := normalizeName(track.Artist)
+			if seen[artist] {
+				continue
+			}
+			seen[artist] = true
+			distinct = append(distinct, track)
+		}
+		selected = distinct
+	}
+
+	limit := request.TrackLimit
+	if limit == 0 {
+		limit = DefaultTrackLimit
+	}
+	if len(selected) > limit {
+		selected = selected[:limit]
+	}
+
+	return selected
+}
+
+// applyPreferences narrows the candidate styles using the optional request
+// constraints. Each constraint is applied in turn so the error can name the
+// one that left no candidates.
+func applyPreferences(styles []domain.BeerStyle, request domain.TemperatureRequest) ([]domain.BeerStyle, error) {
+	styles, _, err := filterPreferences(styles, request)
+	return styles, err
+}
+
+// preference is a request constraint. exclude tells why a style is left
+// out, or "" to keep it; empty is the reason reported when none is kept.
+type preference struct {
+	constraint string
+	exclude    func(domain.BeerStyle) string
+	empty      string
+}
+
+func preferences(request domain.TemperatureRequest) []preference {
+	var constraints []preference
 
-				artistName := "Unknown Artist"
-				if len(track.Artists) > 0 {
-					artistName = track.Artists[0].Name
+	if len(request.IncludeOnly) > 0 {
+		included := toNameSet(request.IncludeOnly)
+		constraints = append(constraints, preference{
+			constraint: "include_only",
+			exclude: func(style domain.BeerStyle) string {
+				if included[normalizeName(style.Name)] {
+					return ""
 				}
+				return "not listed in include_only"
+			},
+			empty: fmt.Sprintf("none of [%s] is a known beer style", strings.Join(request.IncludeOnly, ", ")),
+		})
+	}
+
+	if len(request.ExcludeStyles) > 0 {
+		excluded := toNameSet(request.ExcludeStyles)
+		constraints = append(constraints, preference{
+			constraint: "exclude_styles",
+			exclude: func(style domain.BeerStyle) string {
+				if excluded[normalizeName(style.Name)] {
+					return "listed in exclude_styles"
+				}
+				return ""
+			},
+			empty: "every remaining beer style was excluded",
+		})
+	}
+
+	if len(request.Categories) > 0 {
+		categories := toNameSet(request.Categories)
+		constraints = append(constraints, preference{
+			constraint: "categories",
+			exclude: func(style domain.BeerStyle) string {
+				if categories[normalizeName(style.Category)] {
+					return ""
+				}
+				return fmt.Sprintf("category '%s' is not one of [%s]", style.Category, strings.Join(request.Categories, ", "))
+			},
+			empty: fmt.Sprintf("no beer style belongs to [%s]", strings.Join(request.Categories, ", ")),
+		})
+	}
+
+	if request.MaxABV != nil {
+		maxABV := *request.MaxABV
+		constraints = append(constraints, preference{
+			constraint: "max_abv",
+			exclude: func(style domain.BeerStyle) string {
+				switch {
+				case style.ABV == nil:
+					return "ABV unknown"
+				case *style.ABV > maxABV:
+					return fmt.Sprintf("ABV %.1f%% is above %.1f%%", *style.ABV, maxABV)
+				}
+				return ""
+			},
+			empty: fmt.Sprintf("no beer style with a known ABV at or below %.1f%%", maxABV),
+		})
+	}
 
-				tracks = append(tracks, domain.TrackInfo{
-					Name:   track.Name,
-					Artist: artistName,
-					Link:   spotifyLink,
+	return constraints
+}
+
+// filterPreferences applies the preferences and returns, with the styles
+// kept, the ones each constraint left out.
+func filterPreferences(styles []domain.BeerStyle, request domain.TemperatureRequest) ([]domain.BeerStyle, []domain.ExcludedBeerStyle, error) {
+	var excluded []domain.ExcludedBeerStyle
+	for _, constraint := range preferences(request) {
+		kept := make([]domain.BeerStyle, 0, len(styles))
+		for _, style := range styles {
+			if reason := constraint.exclude(style); reason != "" {
+				excluded = append(excluded, domain.ExcludedBeerStyle{
+					UUID:       style.UUID,
+					Name:       style.Name,
+					Constraint: constraint.constraint,
+					Reason:     reason,
 				})
+				continue
 			}
+			kept = append(kept, style)
+		}
+
+		styles = kept
+		if len(styles) == 0 {
+			return nil, excluded, &ConstraintError{Constraint: constraint.constraint, Reason: constraint.empty}
 		}
 	}
-	return tracks
+	return styles, excluded, nil
+}
+
+func toNameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[normalizeName(name)] = true
+	}
+	return set
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
 }
 
 func abs(x float64) float64 {
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"backend-test/external/deezer"
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/tracing/tracingtest"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubBeerService struct {
+	BeerServiceInterface
+	styles []domain.BeerStyle
+}
+
+func (s *stubBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return s.styles, nil
+}
+
+func setupRecommendationService(t *testing.T, styles []domain.BeerStyle) *RecommendationService {
+	server := musictest.NewDeezerServer(musictest.SampleCatalog())
+	t.Cleanup(server.Close)
+
+	registry := music.NewRegistry(music.ProviderDeezer, deezer.NewProvider(server.URL, server.Client()))
+	service := NewRecommendationService(&stubBeerService{styles: styles}, nil, registry, nil, config.RecommendationConfig{}, nil, logging.Discard())
+	service.fallbackChain = parseFallbackChain([]string{"alias", "style_beer", "category", "next_style"}, logging.Discard())
+	return service
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_StyleName(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale"},
+		{Name: "Stout", TempMin: 10, TempMax: 13, Category: "Stout"},
+	})
+
+	response, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.BeerStyle != "IPA" || response.Playlist.Name != "IPA Session" {
+		t.Errorf("Expected IPA Session for IPA, got %s / %s", response.BeerStyle, response.Playlist.Name)
+	}
+	if response.Playlist.Provider != music.ProviderDeezer {
+		t.Errorf("Expected provider deezer, got '%s'", response.Playlist.Provider)
+	}
+	if response.Fallback != nil {
+		t.Errorf("Expected no fallback for the style name, got %+v", response.Fallback)
+	}
+	if !strings.HasPrefix(response.Playlist.Tracks[0].Link, "https://www.deezer.com/track/") {
+		t.Errorf("Expected Deezer track link, got '%s'", response.Playlist.Tracks[0].Link)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_CategoryFallback(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "Imperial Nightcap", TempMin: 12, TempMax: 14, Category: "Stout"},
+	})
+
+	response, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 13})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Fallback.Step != FallbackStepCategory || response.Fallback.Query != "Stout" {
+		t.Errorf("Expected category fallback with query 'Stout', got %+v", response.Fallback)
+	}
+	if response.Playlist.Name != "Stout Nights" {
+		t.Errorf("Expected Stout Nights, got '%s'", response.Playlist.Name)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_ExcludedStyle(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale"},
+		{Name: "Stout", TempMin: 10, TempMax: 13, Category: "Stout"},
+	})
+
+	response, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{
+		Temperature:   8,
+		ExcludeStyles: []string{"ipa"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.BeerStyle != "Stout" {
+		t.Errorf("Expected Stout once IPA is excluded, got '%s'", response.BeerStyle)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_TrackSelection(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10},
+	})
+
+	seed := int64(42)
+	request := domain.TemperatureRequest{
+		Temperature:     8,
+		Shuffle:         true,
+		ShuffleSeed:     &seed,
+		DistinctArtists: true,
+	}
+
+	first, err := service.GetRecommendationForTemperature(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := service.GetRecommendationForTemperature(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(first.Playlist.Tracks) != 2 {
+		t.Fatalf("Expected one track per artist (2), got %d", len(first.Playlist.Tracks))
+	}
+	for i := range first.Playlist.Tracks {
+		if first.Playlist.Tracks[i].Name != second.Playlist.Tracks[i].Name {
+			t.Errorf("Expected the same seed to produce the same order, got %v and %v", first.Playlist.Tracks, second.Playlist.Tracks)
+		}
+	}
+	if first.Playlist.ShuffleSeed == nil || *first.Playlist.ShuffleSeed != seed {
+		t.Errorf("Expected shuffle seed %d to be echoed, got %v", seed, first.Playlist.ShuffleSeed)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_MinTracks(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10},
+	})
+
+	_, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8, MinTracks: 5})
+
+	constraintErr, ok := err.(*ConstraintError)
+	if !ok || constraintErr.Constraint != "min_tracks" {
+		t.Errorf("Expected min_tracks constraint error, got %v", err)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_ProviderUnavailable(t *testing.T) {
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10},
+	})
+
+	_, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8, Provider: music.ProviderAppleMusic})
+	if err == nil || !strings.Contains(err.Error(), "apple_music service unavailable") {
+		t.Errorf("Expected apple_music service unavailable, got %v", err)
+	}
+}
+
+type stubRecorder struct {
+	records  []domain.RecommendationRecord
+	scores   map[string]float64
+	scoreErr error
+}
+
+func (s *stubRecorder) Record(ctx context.Context, record domain.RecommendationRecord) string {
+	s.records = append(s.records, record)
+	return "4f0e8a52-0000-4000-8000-000000000001"
+}
+
+func (s *stubRecorder) FeedbackScores(ctx context.Context, temperature, bucketSize float64) (map[string]float64, error) {
+	return s.scores, s.scoreErr
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_RecordsHistory(t *testing.T) {
+	recorder := &stubRecorder{}
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{UUID: "c8a1f1e2-0000-4000-8000-000000000001", Name: "IPA", TempMin: 7, TempMax: 10},
+	})
+	service.history = recorder
+
+	response, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.ID != "4f0e8a52-0000-4000-8000-000000000001" {
+		t.Errorf("Expected the recorded ID in the response, got '%s'", response.ID)
+	}
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected one record, got %d", len(recorder.records))
+	}
+	record := recorder.records[0]
+	if record.Temperature != 8 || record.BeerStyleUUID != "c8a1f1e2-0000-4000-8000-000000000001" || record.PlaylistID != "1001" ||
+		record.Provider != music.ProviderDeezer || record.FallbackStep != FallbackStepStyleName {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+func TestRecommendationService_FindBestBeerStyleForTemperature_FeedbackRanking(t *testing.T) {
+	// At 10°C both midpoints are 1.5 degrees away: IPA wins by name.
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{UUID: "ipa-uuid", Name: "IPA", TempMin: 7, TempMax: 10},
+		{UUID: "stout-uuid", Name: "Stout", TempMin: 10, TempMax: 13},
+	})
+	service.ranking = RankingFeedback
+	service.feedbackWeight = 2
+
+	tests := []struct {
+		name     string
+		history  *stubRecorder
+		expected string
+	}{
+		{"approved style moves up", &stubRecorder{scores: map[string]float64{"stout-uuid": 0.5}}, "Stout"},
+		{"rejected style moves down", &stubRecorder{scores: map[string]float64{"ipa-uuid": -0.2}}, "Stout"},
+		{"unavailable feedback ranks by distance", &stubRecorder{scoreErr: errors.New("connection refused")}, "IPA"},
+	}
+
+	for _, tt := range tests {
+		service.history = tt.history
+		style, err := service.FindBestBeerStyleForTemperature(context.Background(), 10)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		if style.Name != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, style.Name)
+		}
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_Spans(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	service := setupRecommendationService(t, []domain.BeerStyle{
+		{Name: "IPA", TempMin: 7, TempMax: 10},
+	})
+
+	if _, err := service.GetRecommendationForTemperature(context.Background(), domain.TemperatureRequest{Temperature: 8}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	names := tracingtest.SpanNames(exporter)
+	if len(spans) != 2 || names[0] != "RecommendationService.rankBeerStyles" || names[1] != "RecommendationService.GetRecommendationForTemperature" {
+		t.Fatalf("Expected ranking span inside the recommendation span, got %v", names)
+	}
+	if spans[0].Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Error("Expected rankBeerStyles to be a child of GetRecommendationForTemperature")
+	}
+}
+
+func TestApplyPreferences(t *testing.T) {
+	abv := func(value float64) *float64 { return &value }
+	styles := []domain.BeerStyle{
+		{Name: "IPA", Category: "Ale", ABV: abv(6.5)},
+		{Name: "Pilsner", Category: "Lager", ABV: abv(4.8)},
+		{Name: "Imperial Stout", Category: "Stout", ABV: abv(10)},
+		{Name: "Gose", Category: "Sour"},
+	}
+
+	tests := []struct {
+		name       string
+		request    domain.TemperatureRequest
+		expected   []string
+		constraint string
+	}{
+		{"no preferences", domain.TemperatureRequest{}, []string{"IPA", "Pilsner", "Imperial Stout", "Gose"}, ""},
+		{"include_only ignores case", domain.TemperatureRequest{IncludeOnly: []string{" pilsner", "IPA"}}, []string{"IPA", "Pilsner"}, ""},
+		{"include_only without known styles", domain.TemperatureRequest{IncludeOnly: []string{"Mead"}}, nil, "include_only"},
+		{"exclude_styles", domain.TemperatureRequest{ExcludeStyles: []string{"ipa", "Gose"}}, []string{"Pilsner", "Imperial Stout"}, ""},
+		{"exclude_styles leaving nothing", domain.TemperatureRequest{IncludeOnly: []string{"IPA"}, ExcludeStyles: []string{"IPA"}}, nil, "exclude_styles"},
+		{"categories", domain.TemperatureRequest{Categories: []string{"lager", "Stout"}}, []string{"Pilsner", "Imperial Stout"}, ""},
+		{"categories without styles", domain.TemperatureRequest{Categories: []string{"Porter"}}, nil, "categories"},
+		{"max_abv drops unknown ABV", domain.TemperatureRequest{MaxABV: abv(7)}, []string{"IPA", "Pilsner"}, ""},
+		{"max_abv below every style", domain.TemperatureRequest{MaxABV: abv(3)}, nil, "max_abv"},
+		{"constraints combine", domain.TemperatureRequest{Categories: []string{"Ale", "Stout"}, MaxABV: abv(8)}, []string{"IPA"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, err := applyPreferences(styles, tt.request)
+
+			if tt.constraint != "" {
+				var constraintErr *ConstraintError
+				if !errors.As(err, &constraintErr) || constraintErr.Constraint != tt.constraint {
+					t.Fatalf("expected the %s constraint to leave no style, got %v", tt.constraint, err)
+				}
+				if !strings.Contains(err.Error(), "'"+tt.constraint+"'") {
+					t.Errorf("expected the error to name %s, got %q", tt.constraint, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			var names []string
+			for _, style := range kept {
+				names = append(names, style.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestSelectTracks(t *testing.T) {
+	tracks := make([]domain.TrackInfo, 0, 15)
+	for i := 0; i < 15; i++ {
+		tracks = append(tracks, domain.TrackInfo{Name: string(rune('a' + i)), Artist: string(rune('A' + i%3))})
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		selected := selectTracks(tracks, domain.TemperatureRequest{}, 0)
+		if len(selected) != DefaultTrackLimit {
+			t.Fatalf("Expected %d tracks, got %d", DefaultTrackLimit, len(selected))
+		}
+		for i := range selected {
+			if selected[i].Name != tracks[i].Name {
+				t.Errorf("Expected the order to be kept without shuffle, got %v", selected)
+				break
+			}
+		}
+	})
+
+	t.Run("explicit limit", func(t *testing.T) {
+		selected := selectTracks(tracks, domain.TemperatureRequest{TrackLimit: 12}, 0)
+		if len(selected) != 12 {
+			t.Errorf("Expected 12 tracks, got %d", len(selected))
+		}
+	})
+
+	t.Run("same seed same order", func(t *testing.T) {
+		request := domain.TemperatureRequest{Shuffle: true, TrackLimit: 15}
+		first := selectTracks(tracks, request, 42)
+		second := selectTracks(tracks, request, 42)
+		for i := range first {
+			if first[i].Name != second[i].Name {
+				t.Fatalf("Expected the same seed to produce the same order, got %v and %v", first, second)
+			}
+		}
+		if tracks[0].Name != "a" {
+			t.Errorf("Expected the input not to be shuffled in place, got %v", tracks)
+		}
+	})
+
+	t.Run("distinct artists", func(t *testing.T) {
+		withCase := append([]domain.TrackInfo{{Name: "z", Artist: " a "}}, tracks...)
+		selected := selectTracks(withCase, domain.TemperatureRequest{DistinctArtists: true}, 0)
+		if len(selected) != 3 {
+			t.Fatalf("Expected one track per artist (3), got %v", selected)
+		}
+		if selected[0].Name != "z" {
+			t.Errorf("Expected the first track of each artist to be kept, got %v", selected)
+		}
+	})
+}
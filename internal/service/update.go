@@ -28,6 +28,22 @@ func (us *UpdateService) ApplyBeerStyleUpdates(current *domain.BeerStyle, update
 		changed = true
 	}
 
+	if updates.Category != nil && *updates.Category != current.Category {
+		current.Category = *updates.Category
+		changed = true
+	}
+
+	if updates.ABV != nil && (current.ABV == nil || *updates.ABV != *current.ABV) {
+		abv := *updates.ABV
+		current.ABV = &abv
+		changed = true
+	}
+
+	if updates.Aliases != nil && !equalAliases(*updates.Aliases, current.Aliases) {
+		current.Aliases = append([]string{}, *updates.Aliases...)
+		changed = true
+	}
+
 	return changed
 }
 
@@ -43,6 +59,27 @@ func (us *UpdateService) GetChangedFields(original domain.BeerStyle, updates dom
 	if updates.TempMax != nil && *updates.TempMax != original.TempMax {
 		changedFields = append(changedFields, "TempMax")
 	}
+	if updates.Category != nil && *updates.Category != original.Category {
+		changedFields = append(changedFields, "Category")
+	}
+	if updates.ABV != nil && (original.ABV == nil || *updates.ABV != *original.ABV) {
+		changedFields = append(changedFields, "ABV")
+	}
+	if updates.Aliases != nil && !equalAliases(*updates.Aliases, original.Aliases) {
+		changedFields = append(changedFields, "Aliases")
+	}
 
 	return changedFields
 }
+
+func equalAliases(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"backend-test/external/music"
 	"backend-test/internal/domain"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -57,8 +59,111 @@ func (vs *ValidationService) ValidateTemperatureInput(temperature float64) error
 	return nil
 }
 
-func (vs *ValidationService) ValidateUniqueNameForCreate(name string) error {
-	beerStyles, err := vs.beerService.ListAllBeerStyles()
+func (vs *ValidationService) ValidateABV(abv *float64) error {
+	if abv == nil {
+		return nil
+	}
+
+	if *abv < 0 || *abv > 100 {
+		return fmt.Errorf("abv (%.1f) must be between 0%% and 100%%", *abv)
+	}
+
+	return nil
+}
+
+func (vs *ValidationService) ValidateRecommendationPreferences(request domain.TemperatureRequest) error {
+	if err := vs.ValidateABV(request.MaxABV); err != nil {
+		return fmt.Errorf("max_abv: %w", err)
+	}
+
+	if request.MinTracks < 0 || request.MinTracks > MaxPlaylistTracks {
+		return fmt.Errorf("min_tracks (%d) must be between 0 and %d", request.MinTracks, MaxPlaylistTracks)
+	}
+
+	if request.TrackLimit < 0 || request.TrackLimit > MaxPlaylistTracks {
+		return fmt.Errorf("track_limit (%d) must be between 0 and %d", request.TrackLimit, MaxPlaylistTracks)
+	}
+
+	// Without track_limit, DefaultTrackLimit tracks are returned.
+	if request.TrackLimit == 0 && request.MinTracks > DefaultTrackLimit {
+		return fmt.Errorf("min_tracks (%d) cannot be greater than the default track_limit (%d): set track_limit too", request.MinTracks, DefaultTrackLimit)
+	}
+	if request.TrackLimit > 0 && request.MinTracks > request.TrackLimit {
+		return fmt.Errorf("min_tracks (%d) cannot be greater than track_limit (%d)", request.MinTracks, request.TrackLimit)
+	}
+
+	if request.ShuffleSeed != nil && !request.Shuffle {
+		return fmt.Errorf("shuffle_seed requires shuffle to be enabled")
+	}
+
+	if provider := normalizeName(request.Provider); provider != "" && !music.IsSupported(provider) {
+		return fmt.Errorf("unsupported provider '%s', expected one of: %s", request.Provider, strings.Join(music.SupportedProviders(), ", "))
+	}
+
+	excluded := make(map[string]bool, len(request.ExcludeStyles))
+	for _, name := range request.ExcludeStyles {
+		excluded[normalizeName(name)] = true
+	}
+
+	for _, name := range request.IncludeOnly {
+		if excluded[normalizeName(name)] {
+			return fmt.Errorf("beer style '%s' cannot be in both include_only and exclude_styles", name)
+		}
+	}
+
+	return nil
+}
+
+func (vs *ValidationService) ValidatePlaylistMapping(request domain.BeerStylePlaylistRequest) error {
+	if strings.TrimSpace(request.PlaylistID) == "" {
+		return fmt.Errorf("playlist_id is required")
+	}
+
+	provider := normalizeName(request.Provider)
+	if provider != "" && !music.IsSupported(provider) {
+		return fmt.Errorf("unsupported provider '%s'", request.Provider)
+	}
+
+	// A zero weight means it was omitted; PinPlaylist stores it as 1.
+	if request.Weight < 0 || request.Weight > MaxPlaylistWeight {
+		return fmt.Errorf("weight (%d) must be between 1 and %d, or omitted for 1", request.Weight, MaxPlaylistWeight)
+	}
+
+	return nil
+}
+
+func (vs *ValidationService) ValidateAPIKeyRequest(request domain.APIKeyRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(name) > 100 {
+		return fmt.Errorf("name must have at most 100 characters")
+	}
+
+	if !request.Role.Valid() {
+		return fmt.Errorf("role must be reader, editor or admin, got '%s'", request.Role)
+	}
+
+	return nil
+}
+
+func (vs *ValidationService) ValidateFeedbackRequest(request domain.FeedbackRequest) error {
+	vote := strings.ToLower(strings.TrimSpace(request.Vote))
+	if vote != domain.VoteUp && vote != domain.VoteDown {
+		return fmt.Errorf("vote must be up or down, got '%s'", request.Vote)
+	}
+
+	if len(strings.TrimSpace(request.PreferredStyle)) > 255 {
+		return fmt.Errorf("preferred_style must have at most 255 characters")
+	}
+
+	return nil
+}
+
+func (vs *ValidationService) ValidateUniqueNameForCreate(ctx context.Context, name string) error {
+	beerStyles, err := vs.beerService.ListAllBeerStyles(ctx)
 	if err != nil {
 		if !vs.isNoRowsError(err) {
 			return fmt.Errorf("failed to check beer styles: %w", err)
@@ -75,12 +180,12 @@ func (vs *ValidationService) ValidateUniqueNameForCreate(name string) error {
 	return nil
 }
 
-func (vs *ValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
+func (vs *ValidationService) ValidateUniqueNameForUpdate(ctx context.Context, name string, excludeUUID string) error {
 	if name == "" {
 		return nil
 	}
 
-	beerStyles, err := vs.beerService.ListAllBeerStyles()
+	beerStyles, err := vs.beerService.ListAllBeerStyles(ctx)
 	if err != nil {
 		if !vs.isNoRowsError(err) {
 			return fmt.Errorf("failed to check beer styles: %w", err)
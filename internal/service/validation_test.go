@@ -0,0 +1,67 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestValidationService_ValidateRecommendationPreferences(t *testing.T) {
+	seed := int64(7)
+	tests := []struct {
+		name    string
+		request domain.TemperatureRequest
+		wantErr string
+	}{
+		{name: "no preferences", request: domain.TemperatureRequest{}},
+		{name: "min_tracks within the default limit", request: domain.TemperatureRequest{MinTracks: DefaultTrackLimit}},
+		{name: "min_tracks above the default limit", request: domain.TemperatureRequest{MinTracks: DefaultTrackLimit + 1}, wantErr: "default track_limit"},
+		{name: "min_tracks above the default limit with track_limit", request: domain.TemperatureRequest{MinTracks: 20, TrackLimit: 20}},
+		{name: "min_tracks above track_limit", request: domain.TemperatureRequest{MinTracks: 6, TrackLimit: 5}, wantErr: "cannot be greater than track_limit"},
+		{name: "negative min_tracks", request: domain.TemperatureRequest{MinTracks: -1}, wantErr: "min_tracks (-1) must be between"},
+		{name: "min_tracks above the maximum", request: domain.TemperatureRequest{MinTracks: MaxPlaylistTracks + 1, TrackLimit: MaxPlaylistTracks}, wantErr: "min_tracks"},
+		{name: "track_limit above the maximum", request: domain.TemperatureRequest{TrackLimit: MaxPlaylistTracks + 1}, wantErr: "track_limit"},
+		{name: "shuffle_seed without shuffle", request: domain.TemperatureRequest{ShuffleSeed: &seed}, wantErr: "shuffle_seed requires shuffle"},
+		{name: "style both included and excluded", request: domain.TemperatureRequest{IncludeOnly: []string{"IPA"}, ExcludeStyles: []string{"ipa"}}, wantErr: "both include_only and exclude_styles"},
+	}
+
+	vs := NewValidationService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vs.ValidateRecommendationPreferences(tt.request)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidationService_ValidatePlaylistMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  int
+		wantErr bool
+	}{
+		{name: "omitted weight", weight: 0},
+		{name: "minimum weight", weight: 1},
+		{name: "maximum weight", weight: MaxPlaylistWeight},
+		{name: "negative weight", weight: -1, wantErr: true},
+		{name: "weight above the maximum", weight: MaxPlaylistWeight + 1, wantErr: true},
+	}
+
+	vs := NewValidationService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vs.ValidatePlaylistMapping(domain.BeerStylePlaylistRequest{PlaylistID: "37i9dQZF1DX0XUsuxWHRQd", Weight: tt.weight})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
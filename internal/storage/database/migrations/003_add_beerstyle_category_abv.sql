@@ -0,0 +1,32 @@
+-- Adiciona categoria e teor alcoólico (ABV) aos estilos de cerveja,
+-- usados pelas preferências de recomendação (categories, max_abv)
+ALTER TABLE beer_styles ADD COLUMN IF NOT EXISTS category VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE beer_styles ADD COLUMN IF NOT EXISTS abv DOUBLE PRECISION;
+
+ALTER TABLE beer_styles DROP CONSTRAINT IF EXISTS abv_range_check;
+ALTER TABLE beer_styles ADD CONSTRAINT abv_range_check CHECK (abv IS NULL OR (abv >= 0 AND abv <= 100));
+
+UPDATE beer_styles AS b SET category = s.category, abv = s.abv
+FROM (VALUES
+    ('IPA', 'Ale', 6.5),
+    ('Lager', 'Lager', 4.5),
+    ('Stout', 'Stout', 6.0),
+    ('Pilsner', 'Lager', 4.8),
+    ('Wheat Beer', 'Wheat', 5.0),
+    ('Porter', 'Porter', 5.5),
+    ('Pale Ale', 'Ale', 5.2),
+    ('Belgian Dubbel', 'Belgian', 7.0),
+    ('Saison', 'Belgian', 6.5),
+    ('Barleywine', 'Strong Ale', 10.0),
+    ('Arctic Lager', 'Lager', 4.2),
+    ('Frozen Ale', 'Ale', 5.0),
+    ('Ice Beer', 'Lager', 5.5),
+    ('Winter Porter', 'Porter', 6.0),
+    ('Hot Weather Lager', 'Lager', 4.0),
+    ('Desert Ale', 'Ale', 4.5),
+    ('Tropical IPA', 'Ale', 6.0),
+    ('Extreme Cold', 'Specialty', NULL),
+    ('Warm Climate', 'Specialty', NULL),
+    ('Zero Point', 'Specialty', 0.0)
+) AS s(name, category, abv)
+WHERE b.name = s.name AND b.category = '';
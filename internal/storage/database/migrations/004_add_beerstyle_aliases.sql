@@ -0,0 +1,18 @@
+-- Adiciona nomes alternativos aos estilos de cerveja, usados como consultas
+-- alternativas quando a busca de playlist pelo nome do estilo não encontra nada
+ALTER TABLE beer_styles ADD COLUMN IF NOT EXISTS aliases TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE beer_styles AS b SET aliases = s.aliases
+FROM (VALUES
+    ('IPA', ARRAY['India Pale Ale']),
+    ('Wheat Beer', ARRAY['Weissbier', 'Hefeweizen']),
+    ('Belgian Dubbel', ARRAY['Dubbel', 'Trappist']),
+    ('Saison', ARRAY['Farmhouse Ale']),
+    ('Barleywine', ARRAY['Barley Wine']),
+    ('Ice Beer', ARRAY['Eisbock']),
+    ('Hot Weather Lager', ARRAY['Summer Lager']),
+    ('Desert Ale', ARRAY['Summer Ale']),
+    ('Tropical IPA', ARRAY['Tropical']),
+    ('Zero Point', ARRAY['Non-Alcoholic Beer'])
+) AS s(name, aliases)
+WHERE b.name = s.name AND b.aliases = '{}';
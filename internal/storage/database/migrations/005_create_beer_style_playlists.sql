@@ -0,0 +1,15 @@
+-- Cria a tabela beer_style_playlists com as playlists curadas fixadas a cada
+-- estilo de cerveja; têm prioridade sobre a busca por nome na recomendação
+CREATE TABLE IF NOT EXISTS beer_style_playlists (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    beer_style_uuid UUID NOT NULL REFERENCES beer_styles(uuid) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL DEFAULT 'spotify',
+    playlist_id VARCHAR(255) NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT weight_range_check CHECK (weight BETWEEN 1 AND 100),
+    CONSTRAINT beer_style_playlist_unique UNIQUE (beer_style_uuid, provider, playlist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_beer_style_playlists_style ON beer_style_playlists (beer_style_uuid);
@@ -0,0 +1,12 @@
+-- Cria a tabela api_keys com as chaves de API das integrações; apenas o hash
+-- SHA-256 da chave é armazenado e chaves revogadas são mantidas para auditoria
+CREATE TABLE IF NOT EXISTS api_keys (
+    uuid UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    CONSTRAINT api_key_role_check CHECK (role IN ('reader', 'editor', 'admin'))
+);
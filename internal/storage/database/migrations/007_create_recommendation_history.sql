@@ -0,0 +1,18 @@
+-- Cria a tabela recommendation_history com cada recomendação servida, usada
+-- pelas agregações de /api/analytics/recommendations; não referencia
+-- beer_styles para manter o histórico de estilos removidos. Registros mais
+-- antigos que a retenção configurada são apagados periodicamente
+CREATE TABLE IF NOT EXISTS recommendation_history (
+    id UUID PRIMARY KEY,
+    temperature DOUBLE PRECISION NOT NULL,
+    beer_style_uuid UUID,
+    beer_style VARCHAR(255) NOT NULL,
+    playlist_id VARCHAR(255) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    fallback_step VARCHAR(50) NOT NULL,
+    latency_ms INTEGER NOT NULL,
+    client_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recommendation_history_created_at ON recommendation_history (created_at);
@@ -0,0 +1,14 @@
+-- Cria a tabela recommendation_feedback com a avaliação (up/down) de cada
+-- recomendação do histórico e, opcionalmente, o estilo que o cliente
+-- preferia, pelo UUID (as notas agregam por UUID) e pelo nome, para
+-- exibição; um novo voto substitui o anterior e a avaliação é apagada junto
+-- com a recomendação pela retenção do histórico
+CREATE TABLE IF NOT EXISTS recommendation_feedback (
+    recommendation_id UUID PRIMARY KEY REFERENCES recommendation_history(id) ON DELETE CASCADE,
+    vote VARCHAR(4) NOT NULL,
+    preferred_style_uuid UUID,
+    preferred_style VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT recommendation_feedback_vote_check CHECK (vote IN ('up', 'down'))
+);
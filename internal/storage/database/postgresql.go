@@ -3,75 +3,159 @@ package postgres
 import (
 	config "backend-test/internal/cmd/server"
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"sync"
 
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/vingarcia/ksql"
 	"github.com/vingarcia/ksql/adapters/kpgx"
 )
 
-var (
+var errClosed = errors.New("database connection pool is closed")
+
+// DB is the connection pool shared by the repositories.
+type DB struct {
+	cfg config.DatabaseConfig
+
+	mu   sync.Mutex
 	db   *ksql.DB
-	once sync.Once
-)
+	pool *pgxpool.Pool
+}
 
-func GetDB() *ksql.DB {
-	once.Do(func() {
-		dbConfig := ksql.Config{
-			MaxOpenConns: 10,
-		}
+// New returns a DB for cfg. The pool is opened on first use.
+func New(cfg config.DatabaseConfig) *DB {
+	return &DB{cfg: cfg}
+}
 
-		log.Println("Initializing optimized DB connection pool (max 10 connections)...")
-		dbConnect, err := kpgx.New(context.Background(), config.DATABASE_URL, dbConfig)
-		if err != nil {
-			log.Panic(err)
-		}
+// connect opens the connection pool on first use. Unlike a sync.Once, a
+// failed attempt is retried on the next call, so the API recovers when the
+// database comes up after the process.
+func (d *DB) connect(ctx context.Context) (*ksql.DB, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.db != nil {
+		return d.db, nil
+	}
+
+	slog.InfoContext(ctx, "initializing database connection pool", "max_connections", d.cfg.MaxConnections)
+	pgxConfig, err := pgxpool.ParseConfig(d.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	pgxConfig.MaxConns = int32(d.cfg.MaxConnections)
+
+	pgxPool, err := pgxpool.ConnectConfig(ctx, pgxConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := pgxPool.Ping(ctx); err != nil {
+		pgxPool.Close()
+		return nil, err
+	}
+
+	dbConnect, err := kpgx.NewFromPgxPool(pgxPool)
+	if err != nil {
+		pgxPool.Close()
+		return nil, err
+	}
+
+	dbConnect.Exec(ctx, "set enable_seqscan = off;")
+	slog.InfoContext(ctx, "database connection pool ready")
+	d.db = &dbConnect
+	d.pool = pgxPool
+	return d.db, nil
+}
 
-		dbConnect.Exec(context.Background(), "set enable_seqscan = off;")
-		log.Println("Database connection pool ready with cost optimization")
-		db = &dbConnect
-	})
-	return db
+// Conn returns the ksql handle, opening the pool if needed.
+func (d *DB) Conn(ctx context.Context) (*ksql.DB, error) {
+	return d.connect(ctx)
 }
 
-func CloseDB() error {
-	if db != nil {
-		log.Println("🔄 Closing database connection pool...")
-		return db.Close()
+// Ping checks that the database answers, opening the pool if needed.
+func (d *DB) Ping(ctx context.Context) error {
+	if _, err := d.connect(ctx); err != nil {
+		return err
 	}
-	return nil
+
+	d.mu.Lock()
+	pool := d.pool
+	d.mu.Unlock()
+
+	if pool == nil {
+		return errClosed
+	}
+	return pool.Ping(ctx)
 }
 
-// 🛡️ GracefulShutdown performs clean database shutdown with proper logging
-func GracefulShutdown() error {
-	if db != nil {
-		log.Println("🗄️ Starting graceful database shutdown...")
+func (d *DB) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-		// Give database time to finish ongoing transactions
-		if err := db.Close(); err != nil {
-			log.Printf("⚠️ Error closing database connections: %v", err)
-			return err
-		}
+	if d.db != nil {
+		slog.Info("closing database connection pool")
+		err := d.db.Close()
+		d.db, d.pool = nil, nil
+		return err
+	}
+	return nil
+}
+
+// GracefulShutdown performs clean database shutdown with proper logging
+func (d *DB) GracefulShutdown() error {
+	d.mu.Lock()
+	initialized := d.db != nil
+	d.mu.Unlock()
 
-		log.Println("✅ Database connections closed gracefully")
+	if !initialized {
+		slog.Info("database was not initialized, nothing to close")
 		return nil
 	}
 
-	log.Println("ℹ️ Database was not initialized, nothing to close")
+	slog.Info("starting graceful database shutdown")
+	if err := d.Close(); err != nil {
+		slog.Error("failed to close database connections", "err", err)
+		return err
+	}
+
+	slog.Info("database connections closed")
 	return nil
 }
 
-func GetDBStats() map[string]interface{} {
-	if db == nil {
+// PoolStat returns the connection pool stats, or nil while the pool is not
+// open.
+func (d *DB) PoolStat() *pgxpool.Stat {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pool == nil {
+		return nil
+	}
+	return d.pool.Stat()
+}
+
+// Stats reports the live state of the connection pool.
+func (d *DB) Stats() map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pool == nil {
 		return map[string]interface{}{
 			"status": "disconnected",
 		}
 	}
 
+	stat := d.pool.Stat()
 	return map[string]interface{}{
-		"status":          "connected",
-		"max_connections": 10,
-		"pool_optimized":  true,
-		"cost_optimized":  true,
+		"status":                 "connected",
+		"max_connections":        stat.MaxConns(),
+		"open_connections":       stat.TotalConns(),
+		"in_use":                 stat.AcquiredConns(),
+		"idle":                   stat.IdleConns(),
+		"wait_count":             stat.EmptyAcquireCount(),
+		"acquire_count":          stat.AcquireCount(),
+		"acquire_duration_ms":    stat.AcquireDuration().Milliseconds(),
+		"canceled_acquire_count": stat.CanceledAcquireCount(),
 	}
 }
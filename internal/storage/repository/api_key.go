@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	postgres "backend-test/internal/storage/database"
+	"context"
+	"time"
+)
+
+type APIKeyRepository struct {
+	db *postgres.DB
+}
+
+func NewAPIKeyRepository(db *postgres.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (a APIKeyRepository) CreateAPIKey(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := a.db.Conn(ctx)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	var created domain.APIKey
+	err = db.QueryOne(ctx, &created, a.createAPIKeyQuery(), apiKey.Name, apiKey.Prefix, apiKey.KeyHash, apiKey.Role)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return created, nil
+}
+
+// GetActiveAPIKeyByHash returns sql.ErrNoRows when no key has the hash or the
+// key was revoked.
+func (a APIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := a.db.Conn(ctx)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	var apiKey domain.APIKey
+	err = db.QueryOne(ctx, &apiKey, a.getActiveAPIKeyByHashQuery(), keyHash)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return apiKey, nil
+}
+
+func (a APIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := a.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKeys []domain.APIKey
+	err = db.Query(ctx, &apiKeys, a.listAPIKeysQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey returns sql.ErrNoRows when the key does not exist or was
+// already revoked.
+func (a APIKeyRepository) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := a.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var revoked domain.APIKey
+	err = db.QueryOne(ctx, &revoked, a.revokeAPIKeyQuery(), keyUUID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (APIKeyRepository) createAPIKeyQuery() string {
+	return `
+		INSERT INTO api_keys (name, prefix, key_hash, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING uuid, name, prefix, key_hash, role, created_at, revoked_at;
+	`
+}
+
+func (APIKeyRepository) getActiveAPIKeyByHashQuery() string {
+	return `
+		SELECT uuid, name, prefix, key_hash, role, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL
+	`
+}
+
+func (APIKeyRepository) listAPIKeysQuery() string {
+	return `
+		SELECT uuid, name, prefix, key_hash, role, created_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at
+	`
+}
+
+func (APIKeyRepository) revokeAPIKeyQuery() string {
+	return `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE uuid = $1 AND revoked_at IS NULL
+		RETURNING uuid, name, prefix, key_hash, role, created_at, revoked_at;
+	`
+}
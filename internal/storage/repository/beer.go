@@ -7,16 +7,25 @@ import (
 	"time"
 )
 
-type BeerRepository struct{}
+type BeerRepository struct {
+	db *postgres.DB
+}
+
+func NewBeerRepository(db *postgres.DB) *BeerRepository {
+	return &BeerRepository{db: db}
+}
 
-func (u BeerRepository) ListAllBeerStyles() ([]domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db := postgres.GetDB()
+	db, err := u.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	var beerStyles []domain.BeerStyle
-	err := db.Query(ctx, &beerStyles, u.getAllBeerStylesQuery())
+	err = db.Query(ctx, &beerStyles, u.getAllBeerStylesQuery())
 	if err != nil {
 		return nil, err
 	}
@@ -24,14 +33,17 @@ func (u BeerRepository) ListAllBeerStyles() ([]domain.BeerStyle, error) {
 	return beerStyles, nil
 }
 
-func (u BeerRepository) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db := postgres.GetDB()
+	db, err := u.db.Conn(ctx)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
 
 	var createdBeerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &createdBeerStyle, u.createBeerStyleQuery(), beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax)
+	err = db.QueryOne(ctx, &createdBeerStyle, u.createBeerStyleQuery(), beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax, beerStyle.Category, beerStyle.ABV, aliasesOrEmpty(beerStyle.Aliases))
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
@@ -39,14 +51,17 @@ func (u BeerRepository) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Beer
 	return createdBeerStyle, nil
 }
 
-func (u BeerRepository) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db := postgres.GetDB()
+	db, err := u.db.Conn(ctx)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
 
 	var beerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &beerStyle, u.getBeerStyleByUUIDQuery(), beerUUID)
+	err = db.QueryOne(ctx, &beerStyle, u.getBeerStyleByUUIDQuery(), beerUUID)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
@@ -54,15 +69,18 @@ func (u BeerRepository) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, e
 	return beerStyle, nil
 }
 
-func (u BeerRepository) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db := postgres.GetDB()
+	db, err := u.db.Conn(ctx)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
 
 	var updatedBeerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &updatedBeerStyle, u.updateBeerStyleQuery(),
-		beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax, beerStyle.UUID)
+	err = db.QueryOne(ctx, &updatedBeerStyle, u.updateBeerStyleQuery(),
+		beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax, beerStyle.Category, beerStyle.ABV, aliasesOrEmpty(beerStyle.Aliases), beerStyle.UUID)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
@@ -70,13 +88,16 @@ func (u BeerRepository) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.Beer
 	return updatedBeerStyle, nil
 }
 
-func (u BeerRepository) DeleteBeerStyle(beerUUID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	db := postgres.GetDB()
+	db, err := u.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
 
-	_, err := db.Exec(ctx, u.deleteBeerStyleQuery(), beerUUID)
+	_, err = db.Exec(ctx, u.deleteBeerStyleQuery(), beerUUID)
 	if err != nil {
 		return err
 	}
@@ -86,14 +107,14 @@ func (u BeerRepository) DeleteBeerStyle(beerUUID string) error {
 
 func (BeerRepository) getAllBeerStylesQuery() string {
 	return `
-		SELECT uuid, name, temp_min, temp_max, created_at, updated_at
+		SELECT uuid, name, temp_min, temp_max, category, abv, aliases, created_at, updated_at
 		FROM beer_styles
 	`
 }
 
 func (BeerRepository) getBeerStyleByUUIDQuery() string {
 	return `
-		SELECT uuid, name, temp_min, temp_max, created_at, updated_at
+		SELECT uuid, name, temp_min, temp_max, category, abv, aliases, created_at, updated_at
 		FROM beer_styles
 		WHERE uuid = $1
 	`
@@ -101,9 +122,9 @@ func (BeerRepository) getBeerStyleByUUIDQuery() string {
 
 func (BeerRepository) createBeerStyleQuery() string {
 	return `
-		INSERT INTO beer_styles (name, temp_min, temp_max)
-		VALUES ($1, $2, $3)
-		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at;
+		INSERT INTO beer_styles (name, temp_min, temp_max, category, abv, aliases)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING uuid, name, temp_min, temp_max, category, abv, aliases, created_at, updated_at;
 	`
 }
 
@@ -113,9 +134,12 @@ func (BeerRepository) updateBeerStyleQuery() string {
 		SET name = $1,
 		temp_min = $2,
 		temp_max = $3,
+		category = $4,
+		abv = $5,
+		aliases = $6,
 		updated_at = NOW()
-		WHERE uuid = $4
-		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at;
+		WHERE uuid = $7
+		RETURNING uuid, name, temp_min, temp_max, category, abv, aliases, created_at, updated_at;
 	`
 }
 
@@ -125,3 +149,11 @@ func (BeerRepository) deleteBeerStyleQuery() string {
 		WHERE uuid = $1;
 	`
 }
+
+// aliasesOrEmpty keeps a nil alias list from being written as NULL.
+func aliasesOrEmpty(aliases []string) []string {
+	if aliases == nil {
+		return []string{}
+	}
+	return aliases
+}
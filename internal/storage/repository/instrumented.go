@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/metrics"
+	"backend-test/internal/tracing"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// observe starts a span for a repository method and returns the function
+// that ends it, records the query latency in db_query_duration_seconds and
+// logs failed queries. Lookups that find no row are not failures.
+func observe(ctx context.Context, logger *slog.Logger, repository, spanPrefix, method string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, spanPrefix+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", method),
+		),
+	)
+
+	return ctx, func(err error) {
+		metrics.ObserveDBQuery(repository, method, start, err)
+		tracing.End(span, err)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			logger.ErrorContext(ctx, "query failed", "method", method, "latency_ms", time.Since(start).Milliseconds(), "err", err)
+		}
+	}
+}
+
+// InstrumentedBeerRepository records a span and the query latency of every
+// BeerRepository method.
+type InstrumentedBeerRepository struct {
+	next   BeerRepositoryInterface
+	logger *slog.Logger
+}
+
+func NewInstrumentedBeerRepository(next BeerRepositoryInterface, logger *slog.Logger) *InstrumentedBeerRepository {
+	return &InstrumentedBeerRepository{next: next, logger: logger.With("repository", "BeerRepository")}
+}
+
+func (r *InstrumentedBeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	ctx, done := observe(ctx, r.logger, "beer", "BeerRepository", "ListAllBeerStyles")
+	beerStyles, err := r.next.ListAllBeerStyles(ctx)
+	done(err)
+	return beerStyles, err
+}
+
+func (r *InstrumentedBeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	ctx, done := observe(ctx, r.logger, "beer", "BeerRepository", "GetBeerStyleByUUID")
+	beerStyle, err := r.next.GetBeerStyleByUUID(ctx, beerUUID)
+	done(err)
+	return beerStyle, err
+}
+
+func (r *InstrumentedBeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, done := observe(ctx, r.logger, "beer", "BeerRepository", "CreateBeerStyle")
+	created, err := r.next.CreateBeerStyle(ctx, beerStyle)
+	done(err)
+	return created, err
+}
+
+func (r *InstrumentedBeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, done := observe(ctx, r.logger, "beer", "BeerRepository", "UpdateBeerStyle")
+	updated, err := r.next.UpdateBeerStyle(ctx, beerStyle)
+	done(err)
+	return updated, err
+}
+
+func (r *InstrumentedBeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	ctx, done := observe(ctx, r.logger, "beer", "BeerRepository", "DeleteBeerStyle")
+	err := r.next.DeleteBeerStyle(ctx, beerUUID)
+	done(err)
+	return err
+}
+
+// InstrumentedPlaylistMappingRepository records a span and the query latency
+// of every PlaylistMappingRepository method.
+type InstrumentedPlaylistMappingRepository struct {
+	next   PlaylistMappingRepositoryInterface
+	logger *slog.Logger
+}
+
+func NewInstrumentedPlaylistMappingRepository(next PlaylistMappingRepositoryInterface, logger *slog.Logger) *InstrumentedPlaylistMappingRepository {
+	return &InstrumentedPlaylistMappingRepository{next: next, logger: logger.With("repository", "PlaylistMappingRepository")}
+}
+
+func (r *InstrumentedPlaylistMappingRepository) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	ctx, done := observe(ctx, r.logger, "playlist_mapping", "PlaylistMappingRepository", "ListPlaylistsForBeerStyle")
+	playlists, err := r.next.ListPlaylistsForBeerStyle(ctx, beerStyleUUID)
+	done(err)
+	return playlists, err
+}
+
+func (r *InstrumentedPlaylistMappingRepository) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) ([]domain.BeerStylePlaylist, error) {
+	ctx, done := observe(ctx, r.logger, "playlist_mapping", "PlaylistMappingRepository", "ListPlaylistsForBeerStyles")
+	playlists, err := r.next.ListPlaylistsForBeerStyles(ctx, beerStyleUUIDs)
+	done(err)
+	return playlists, err
+}
+
+func (r *InstrumentedPlaylistMappingRepository) CreatePlaylistMapping(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	ctx, done := observe(ctx, r.logger, "playlist_mapping", "PlaylistMappingRepository", "CreatePlaylistMapping")
+	created, err := r.next.CreatePlaylistMapping(ctx, playlist)
+	done(err)
+	return created, err
+}
+
+func (r *InstrumentedPlaylistMappingRepository) DeletePlaylistMapping(ctx context.Context, beerStyleUUID string, playlistUUID string) error {
+	ctx, done := observe(ctx, r.logger, "playlist_mapping", "PlaylistMappingRepository", "DeletePlaylistMapping")
+	err := r.next.DeletePlaylistMapping(ctx, beerStyleUUID, playlistUUID)
+	done(err)
+	return err
+}
+
+// InstrumentedAPIKeyRepository records a span and the query latency of every
+// APIKeyRepository method.
+type InstrumentedAPIKeyRepository struct {
+	next   APIKeyRepositoryInterface
+	logger *slog.Logger
+}
+
+func NewInstrumentedAPIKeyRepository(next APIKeyRepositoryInterface, logger *slog.Logger) *InstrumentedAPIKeyRepository {
+	return &InstrumentedAPIKeyRepository{next: next, logger: logger.With("repository", "APIKeyRepository")}
+}
+
+func (r *InstrumentedAPIKeyRepository) CreateAPIKey(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	ctx, done := observe(ctx, r.logger, "api_key", "APIKeyRepository", "CreateAPIKey")
+	created, err := r.next.CreateAPIKey(ctx, apiKey)
+	done(err)
+	return created, err
+}
+
+func (r *InstrumentedAPIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	ctx, done := observe(ctx, r.logger, "api_key", "APIKeyRepository", "GetActiveAPIKeyByHash")
+	apiKey, err := r.next.GetActiveAPIKeyByHash(ctx, keyHash)
+	done(err)
+	return apiKey, err
+}
+
+func (r *InstrumentedAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, done := observe(ctx, r.logger, "api_key", "APIKeyRepository", "ListAPIKeys")
+	apiKeys, err := r.next.ListAPIKeys(ctx)
+	done(err)
+	return apiKeys, err
+}
+
+func (r *InstrumentedAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyUUID string) error {
+	ctx, done := observe(ctx, r.logger, "api_key", "APIKeyRepository", "RevokeAPIKey")
+	err := r.next.RevokeAPIKey(ctx, keyUUID)
+	done(err)
+	return err
+}
+
+// InstrumentedRecommendationHistoryRepository records a span and the query
+// latency of every RecommendationHistoryRepository method.
+type InstrumentedRecommendationHistoryRepository struct {
+	next   RecommendationHistoryRepositoryInterface
+	logger *slog.Logger
+}
+
+func NewInstrumentedRecommendationHistoryRepository(next RecommendationHistoryRepositoryInterface, logger *slog.Logger) *InstrumentedRecommendationHistoryRepository {
+	return &InstrumentedRecommendationHistoryRepository{next: next, logger: logger.With("repository", "RecommendationHistoryRepository")}
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "SaveRecommendations")
+	err := r.next.SaveRecommendations(ctx, records)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) CountByBeerStyle(ctx context.Context, from, to time.Time) ([]domain.BeerStyleCount, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "CountByBeerStyle")
+	counts, err := r.next.CountByBeerStyle(ctx, from, to)
+	done(err)
+	return counts, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) CountByTemperature(ctx context.Context, from, to time.Time, bucketSize float64) ([]domain.TemperatureBucketCount, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "CountByTemperature")
+	counts, err := r.next.CountByTemperature(ctx, from, to, bucketSize)
+	done(err)
+	return counts, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) CountByDay(ctx context.Context, from, to time.Time) ([]domain.DayCount, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "CountByDay")
+	counts, err := r.next.CountByDay(ctx, from, to)
+	done(err)
+	return counts, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) PurgeRecommendations(ctx context.Context, before time.Time) (int64, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "PurgeRecommendations")
+	purged, err := r.next.PurgeRecommendations(ctx, before)
+	done(err)
+	return purged, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "GetRecommendation")
+	record, err := r.next.GetRecommendation(ctx, id)
+	done(err)
+	return record, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) SaveFeedback(ctx context.Context, feedback domain.RecommendationFeedback) (domain.RecommendationFeedback, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "SaveFeedback")
+	saved, err := r.next.SaveFeedback(ctx, feedback)
+	done(err)
+	return saved, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) CountFeedback(ctx context.Context, temperature, bucketSize float64) ([]domain.FeedbackTally, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "CountFeedback")
+	tallies, err := r.next.CountFeedback(ctx, temperature, bucketSize)
+	done(err)
+	return tallies, err
+}
+
+func (r *InstrumentedRecommendationHistoryRepository) ListRatedRecommendations(ctx context.Context, from, to time.Time) ([]domain.RatedRecommendation, error) {
+	ctx, done := observe(ctx, r.logger, "recommendation_history", "RecommendationHistoryRepository", "ListRatedRecommendations")
+	ratings, err := r.next.ListRatedRecommendations(ctx, from, to)
+	done(err)
+	return ratings, err
+}
@@ -1,11 +1,41 @@
 package repository
 
-import "backend-test/internal/domain"
+import (
+	"backend-test/internal/domain"
+	"context"
+	"time"
+)
 
 type BeerRepositoryInterface interface {
-	ListAllBeerStyles() ([]domain.BeerStyle, error)
-	GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error)
-	CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	DeleteBeerStyle(beerUUID string) error
+	ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error)
+	GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	DeleteBeerStyle(ctx context.Context, beerUUID string) error
+}
+
+type PlaylistMappingRepositoryInterface interface {
+	ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error)
+	ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) ([]domain.BeerStylePlaylist, error)
+	CreatePlaylistMapping(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error)
+	DeletePlaylistMapping(ctx context.Context, beerStyleUUID string, playlistUUID string) error
+}
+
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyUUID string) error
+}
+
+type RecommendationHistoryRepositoryInterface interface {
+	SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error
+	CountByBeerStyle(ctx context.Context, from, to time.Time) ([]domain.BeerStyleCount, error)
+	CountByTemperature(ctx context.Context, from, to time.Time, bucketSize float64) ([]domain.TemperatureBucketCount, error)
+	CountByDay(ctx context.Context, from, to time.Time) ([]domain.DayCount, error)
+	PurgeRecommendations(ctx context.Context, before time.Time) (int64, error)
+	GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error)
+	SaveFeedback(ctx context.Context, feedback domain.RecommendationFeedback) (domain.RecommendationFeedback, error)
+	CountFeedback(ctx context.Context, temperature, bucketSize float64) ([]domain.FeedbackTally, error)
+	ListRatedRecommendations(ctx context.Context, from, to time.Time) ([]domain.RatedRecommendation, error)
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	postgres "backend-test/internal/storage/database"
+	"context"
+	"time"
+)
+
+type PlaylistMappingRepository struct {
+	db *postgres.DB
+}
+
+func NewPlaylistMappingRepository(db *postgres.DB) *PlaylistMappingRepository {
+	return &PlaylistMappingRepository{db: db}
+}
+
+func (p PlaylistMappingRepository) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var playlists []domain.BeerStylePlaylist
+	err = db.Query(ctx, &playlists, p.listPlaylistsForBeerStyleQuery(), beerStyleUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+// ListPlaylistsForBeerStyles returns the playlists pinned to any of the beer
+// styles in one query, ordered as ListPlaylistsForBeerStyle within each
+// style.
+func (p PlaylistMappingRepository) ListPlaylistsForBeerStyles(ctx context.Context, beerStyleUUIDs []string) ([]domain.BeerStylePlaylist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var playlists []domain.BeerStylePlaylist
+	err = db.Query(ctx, &playlists, p.listPlaylistsForBeerStylesQuery(), beerStyleUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (p PlaylistMappingRepository) CreatePlaylistMapping(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := p.db.Conn(ctx)
+	if err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+
+	var createdPlaylist domain.BeerStylePlaylist
+	err = db.QueryOne(ctx, &createdPlaylist, p.createPlaylistMappingQuery(),
+		playlist.BeerStyleUUID, playlist.Provider, playlist.PlaylistID, playlist.Weight)
+	if err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+
+	return createdPlaylist, nil
+}
+
+func (p PlaylistMappingRepository) DeletePlaylistMapping(ctx context.Context, beerStyleUUID string, playlistUUID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deleted domain.BeerStylePlaylist
+	err = db.QueryOne(ctx, &deleted, p.deletePlaylistMappingQuery(), beerStyleUUID, playlistUUID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (PlaylistMappingRepository) listPlaylistsForBeerStyleQuery() string {
+	return `
+		SELECT uuid, beer_style_uuid, provider, playlist_id, weight, created_at, updated_at
+		FROM beer_style_playlists
+		WHERE beer_style_uuid = $1
+		ORDER BY weight DESC, created_at
+	`
+}
+
+func (PlaylistMappingRepository) listPlaylistsForBeerStylesQuery() string {
+	return `
+		SELECT uuid, beer_style_uuid, provider, playlist_id, weight, created_at, updated_at
+		FROM beer_style_playlists
+		WHERE beer_style_uuid = ANY($1)
+		ORDER BY beer_style_uuid, weight DESC, created_at
+	`
+}
+
+func (PlaylistMappingRepository) createPlaylistMappingQuery() string {
+	return `
+		INSERT INTO beer_style_playlists (beer_style_uuid, provider, playlist_id, weight)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (beer_style_uuid, provider, playlist_id)
+		DO UPDATE SET weight = EXCLUDED.weight, updated_at = NOW()
+		RETURNING uuid, beer_style_uuid, provider, playlist_id, weight, created_at, updated_at;
+	`
+}
+
+func (PlaylistMappingRepository) deletePlaylistMappingQuery() string {
+	return `
+		DELETE FROM beer_style_playlists
+		WHERE beer_style_uuid = $1 AND uuid = $2
+		RETURNING uuid, beer_style_uuid, provider, playlist_id, weight, created_at, updated_at;
+	`
+}
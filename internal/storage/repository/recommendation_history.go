@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	postgres "backend-test/internal/storage/database"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recommendationColumns are the columns written by SaveRecommendations, in
+// the order of recordValues.
+var recommendationColumns = []string{
+	"id", "temperature", "beer_style_uuid", "beer_style", "playlist_id",
+	"provider", "fallback_step", "latency_ms", "client_id", "created_at",
+}
+
+type RecommendationHistoryRepository struct {
+	db *postgres.DB
+}
+
+func NewRecommendationHistoryRepository(db *postgres.DB) *RecommendationHistoryRepository {
+	return &RecommendationHistoryRepository{db: db}
+}
+
+// SaveRecommendations inserts the records in a single statement.
+func (r RecommendationHistoryRepository) SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := make([]interface{}, 0, len(records)*len(recommendationColumns))
+	for _, record := range records {
+		params = append(params, recordValues(record)...)
+	}
+
+	_, err = db.Exec(ctx, r.saveRecommendationsQuery(len(records)), params...)
+	return err
+}
+
+func (r RecommendationHistoryRepository) CountByBeerStyle(ctx context.Context, from, to time.Time) ([]domain.BeerStyleCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []domain.BeerStyleCount
+	err = db.Query(ctx, &counts, r.countByBeerStyleQuery(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountByTemperature groups the requested temperatures in buckets of
+// bucketSize degrees. Only the lower bound of each bucket is filled.
+func (r RecommendationHistoryRepository) CountByTemperature(ctx context.Context, from, to time.Time, bucketSize float64) ([]domain.TemperatureBucketCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []domain.TemperatureBucketCount
+	err = db.Query(ctx, &counts, r.countByTemperatureQuery(), from, to, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r RecommendationHistoryRepository) CountByDay(ctx context.Context, from, to time.Time) ([]domain.DayCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []domain.DayCount
+	err = db.Query(ctx, &counts, r.countByDayQuery(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// PurgeRecommendations deletes the records created before the given time
+// and returns how many were deleted.
+func (r RecommendationHistoryRepository) PurgeRecommendations(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec(ctx, r.purgeRecommendationsQuery(), before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetRecommendation returns sql.ErrNoRows when no record has the ID.
+func (r RecommendationHistoryRepository) GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return domain.RecommendationRecord{}, err
+	}
+
+	var record domain.RecommendationRecord
+	err = db.QueryOne(ctx, &record, r.getRecommendationQuery(), id)
+	if err != nil {
+		return domain.RecommendationRecord{}, err
+	}
+
+	return record, nil
+}
+
+// SaveFeedback stores the feedback of a recommendation, replacing the
+// previous vote.
+func (r RecommendationHistoryRepository) SaveFeedback(ctx context.Context, feedback domain.RecommendationFeedback) (domain.RecommendationFeedback, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return domain.RecommendationFeedback{}, err
+	}
+
+	var saved domain.RecommendationFeedback
+	err = db.QueryOne(ctx, &saved, r.saveFeedbackQuery(), feedback.RecommendationID, feedback.Vote, nullable(feedback.PreferredStyleUUID), nullable(feedback.PreferredStyle))
+	if err != nil {
+		return domain.RecommendationFeedback{}, err
+	}
+
+	return saved, nil
+}
+
+// CountFeedback tallies, by beer style UUID, the feedback on the recommendations
+// whose temperature falls in the bucket of bucketSize degrees holding
+// temperature.
+func (r RecommendationHistoryRepository) CountFeedback(ctx context.Context, temperature, bucketSize float64) ([]domain.FeedbackTally, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tallies []domain.FeedbackTally
+	err = db.Query(ctx, &tallies, r.countFeedbackQuery(), temperature, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return tallies, nil
+}
+
+// ListRatedRecommendations returns the recommendations created in
+// [from, to) that received feedback, oldest first.
+func (r RecommendationHistoryRepository) ListRatedRecommendations(ctx context.Context, from, to time.Time) ([]domain.RatedRecommendation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	db, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ratings []domain.RatedRecommendation
+	err = db.Query(ctx, &ratings, r.listRatedRecommendationsQuery(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// recordValues returns the parameters of a record. Empty optional values
+// are stored as NULL.
+func recordValues(record domain.RecommendationRecord) []interface{} {
+	return []interface{}{
+		record.ID,
+		record.Temperature,
+		nullable(record.BeerStyleUUID),
+		record.BeerStyle,
+		record.PlaylistID,
+		record.Provider,
+		record.FallbackStep,
+		record.LatencyMs,
+		nullable(record.ClientID),
+		record.CreatedAt,
+	}
+}
+
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (RecommendationHistoryRepository) saveRecommendationsQuery(rows int) string {
+	values := make([]string, 0, rows)
+	for row := 0; row < rows; row++ {
+		placeholders := make([]string, len(recommendationColumns))
+		for column := range recommendationColumns {
+			placeholders[column] = fmt.Sprintf("$%d", row*len(recommendationColumns)+column+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	return `
+		INSERT INTO recommendation_history (` + strings.Join(recommendationColumns, ", ") + `)
+		VALUES ` + strings.Join(values, ",\n\t\t\t") + `
+		ON CONFLICT (id) DO NOTHING;
+	`
+}
+
+func (RecommendationHistoryRepository) countByBeerStyleQuery() string {
+	return `
+		SELECT beer_style, COUNT(*) AS count, AVG(latency_ms)::DOUBLE PRECISION AS avg_latency_ms
+		FROM recommendation_history
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY beer_style
+		ORDER BY count DESC, beer_style
+	`
+}
+
+func (RecommendationHistoryRepository) countByTemperatureQuery() string {
+	return `
+		SELECT FLOOR(temperature / $3) * $3 AS bucket_min, COUNT(*) AS count
+		FROM recommendation_history
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY bucket_min
+		ORDER BY bucket_min
+	`
+}
+
+func (RecommendationHistoryRepository) countByDayQuery() string {
+	return `
+		SELECT TO_CHAR(DATE_TRUNC('day', created_at), 'YYYY-MM-DD') AS day, COUNT(*) AS count
+		FROM recommendation_history
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY day
+		ORDER BY day
+	`
+}
+
+func (RecommendationHistoryRepository) getRecommendationQuery() string {
+	return `
+		SELECT id, temperature, COALESCE(beer_style_uuid::TEXT, '') AS beer_style_uuid, beer_style, playlist_id,
+			provider, fallback_step, latency_ms, COALESCE(client_id, '') AS client_id, created_at
+		FROM recommendation_history
+		WHERE id = $1
+	`
+}
+
+func (RecommendationHistoryRepository) saveFeedbackQuery() string {
+	return `
+		INSERT INTO recommendation_feedback (recommendation_id, vote, preferred_style_uuid, preferred_style)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (recommendation_id) DO UPDATE
+		SET vote = EXCLUDED.vote, preferred_style_uuid = EXCLUDED.preferred_style_uuid,
+			preferred_style = EXCLUDED.preferred_style, updated_at = NOW()
+		RETURNING recommendation_id, vote, COALESCE(preferred_style_uuid::TEXT, '') AS preferred_style_uuid,
+			COALESCE(preferred_style, '') AS preferred_style, created_at, updated_at;
+	`
+}
+
+// countFeedbackQuery counts each vote for the UUID of the recommended
+// style and, when set, once more as preferred for the preferred style.
+// Records without a style UUID are not counted.
+func (RecommendationHistoryRepository) countFeedbackQuery() string {
+	return `
+		SELECT beer_style_uuid::TEXT AS beer_style_uuid, SUM(up) AS up, SUM(down) AS down, SUM(preferred) AS preferred
+		FROM (
+			SELECT h.beer_style_uuid,
+				CASE WHEN f.vote = 'up' THEN 1 ELSE 0 END AS up,
+				CASE WHEN f.vote = 'down' THEN 1 ELSE 0 END AS down,
+				0 AS preferred
+			FROM recommendation_feedback f
+			JOIN recommendation_history h ON h.id = f.recommendation_id
+			WHERE h.beer_style_uuid IS NOT NULL
+				AND FLOOR(h.temperature / $2::DOUBLE PRECISION) = FLOOR($1::DOUBLE PRECISION / $2::DOUBLE PRECISION)
+			UNION ALL
+			SELECT f.preferred_style_uuid, 0, 0, 1
+			FROM recommendation_feedback f
+			JOIN recommendation_history h ON h.id = f.recommendation_id
+			WHERE f.preferred_style_uuid IS NOT NULL
+				AND FLOOR(h.temperature / $2::DOUBLE PRECISION) = FLOOR($1::DOUBLE PRECISION / $2::DOUBLE PRECISION)
+		) votes
+		GROUP BY beer_style_uuid
+	`
+}
+
+func (RecommendationHistoryRepository) listRatedRecommendationsQuery() string {
+	return `
+		SELECT h.id, h.temperature, COALESCE(h.beer_style_uuid::TEXT, '') AS beer_style_uuid, h.beer_style, f.vote,
+			COALESCE(f.preferred_style_uuid::TEXT, '') AS preferred_style_uuid,
+			COALESCE(f.preferred_style, '') AS preferred_style, h.created_at
+		FROM recommendation_history h
+		JOIN recommendation_feedback f ON f.recommendation_id = h.id
+		WHERE h.created_at >= $1 AND h.created_at < $2
+		ORDER BY h.created_at, h.id
+	`
+}
+
+func (RecommendationHistoryRepository) purgeRecommendationsQuery() string {
+	return `
+		DELETE FROM recommendation_history
+		WHERE created_at < $1
+	`
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"backend-test/external/music"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedProvider wraps every call to a music provider in a client span.
+type tracedProvider struct {
+	next music.Provider
+}
+
+// InstrumentProvider wraps provider so its calls show up in traces.
+func InstrumentProvider(provider music.Provider) music.Provider {
+	return &tracedProvider{next: provider}
+}
+
+func (p *tracedProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *tracedProvider) SearchPlaylists(ctx context.Context, query string, limit int) ([]music.PlaylistSummary, error) {
+	ctx, span := Tracer().Start(ctx, "music.SearchPlaylists", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("music.provider", p.next.Name()),
+		attribute.String("music.query", query),
+		attribute.Int("music.limit", limit),
+	))
+
+	playlists, err := p.next.SearchPlaylists(ctx, query, limit)
+	span.SetAttributes(attribute.Int("music.results", len(playlists)))
+	End(span, ignoreNotFound(err))
+	return playlists, err
+}
+
+func (p *tracedProvider) GetPlaylist(ctx context.Context, playlistID string) (*music.Playlist, error) {
+	ctx, span := Tracer().Start(ctx, "music.GetPlaylist", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("music.provider", p.next.Name()),
+		attribute.String("music.playlist_id", playlistID),
+	))
+
+	playlist, err := p.next.GetPlaylist(ctx, playlistID)
+	if playlist != nil {
+		span.SetAttributes(attribute.Int("music.tracks", len(playlist.Tracks)))
+	}
+	End(span, ignoreNotFound(err))
+	return playlist, err
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, music.ErrPlaylistNotFound) {
+		return nil
+	}
+	return err
+}
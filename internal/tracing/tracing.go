@@ -0,0 +1,117 @@
+// Package tracing configures OpenTelemetry tracing: the tracer provider and
+// exporter selected by environment, W3C trace context propagation and the
+// helpers used to instrument Gin, outgoing HTTP calls and music providers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "backend-test"
+	defaultServiceName  = "beer-recommendation-api"
+)
+
+func init() {
+	// Propagation is enabled even without an exporter so trace context from
+	// callers is forwarded to the music providers.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Options selects the trace exporter.
+type Options struct {
+	// Exporter is "otlp" to export spans; any other value keeps the no-op
+	// provider.
+	Exporter string
+	// Endpoint is the OTLP/HTTP collector URL. When empty the exporter falls
+	// back to OTEL_EXPORTER_OTLP_ENDPOINT and its own default.
+	Endpoint    string
+	ServiceName string
+}
+
+// Setup installs the tracer provider selected by options. With the "otlp"
+// exporter spans are exported over OTLP/HTTP; otherwise the no-op provider
+// is kept. The returned function flushes and stops the provider.
+func Setup(ctx context.Context, options Options) (func(context.Context) error, error) {
+	if !strings.EqualFold(options.Exporter, "otlp") {
+		slog.Info("tracing disabled, set OTEL_TRACES_EXPORTER=otlp to export spans")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporterOptions []otlptracehttp.Option
+	if options.Endpoint != "" {
+		exporterOptions = append(exporterOptions, otlptracehttp.WithEndpointURL(options.Endpoint))
+	}
+
+	otlpExporter, err := otlptracehttp.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := serviceNameOrDefault(options.ServiceName)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(otlpExporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("tracing enabled, exporting spans over OTLP", "service_name", serviceName)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer used for the spans created by the API itself.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Middleware starts a server span for every request, continuing the trace of
+// an incoming traceparent header.
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceNameOrDefault(serviceName))
+}
+
+func serviceNameOrDefault(serviceName string) string {
+	if serviceName == "" {
+		return defaultServiceName
+	}
+	return serviceName
+}
+
+// NewHTTPClient returns an HTTP client whose requests are traced and carry
+// the traceparent header.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,96 @@
+package tracing_test
+
+import (
+	"backend-test/external/deezer"
+	"backend-test/external/music/musictest"
+	"backend-test/internal/tracing"
+	"backend-test/internal/tracing/tracingtest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := tracingtest.Install(t)
+
+	var traceparent string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer downstream.Close()
+
+	client := tracing.NewHTTPClient(time.Second)
+
+	router := gin.New()
+	router.Use(tracing.Middleware(""))
+	router.GET("/api/beer-styles/list", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/beer-styles/list", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(w, req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected server and client spans, got %v", tracingtest.SpanNames(exporter))
+	}
+	for _, span := range spans {
+		if span.SpanContext.TraceID().String() != incomingTraceID {
+			t.Errorf("Expected span %s in trace %s, got %s", span.Name, incomingTraceID, span.SpanContext.TraceID())
+		}
+	}
+
+	if !strings.Contains(traceparent, incomingTraceID) {
+		t.Errorf("Expected traceparent with trace %s to reach the provider, got '%s'", incomingTraceID, traceparent)
+	}
+}
+
+func TestInstrumentProvider_RecordsSpans(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	server := musictest.NewDeezerServer(musictest.SampleCatalog())
+	defer server.Close()
+
+	provider := tracing.InstrumentProvider(deezer.NewProvider(server.URL, server.Client()))
+
+	ctx, parent := tracing.Tracer().Start(t.Context(), "test")
+	if _, err := provider.SearchPlaylists(ctx, "nothing matches this", 1); err == nil {
+		t.Fatal("Expected no hits for the query")
+	}
+	if _, err := provider.GetPlaylist(ctx, "1001"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	names := tracingtest.SpanNames(exporter)
+	if len(spans) != 3 || names[0] != "music.SearchPlaylists" || names[1] != "music.GetPlaylist" {
+		t.Fatalf("Expected search and get spans under the parent, got %v", names)
+	}
+	for _, span := range spans[:2] {
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Expected %s to be a child of the test span", span.Name)
+		}
+		if span.SpanKind != trace.SpanKindClient {
+			t.Errorf("Expected %s to be a client span, got %s", span.Name, span.SpanKind)
+		}
+		if span.Status.Code.String() == "Error" {
+			t.Errorf("Expected %s not to be marked as error", span.Name)
+		}
+	}
+}
@@ -1,23 +1,84 @@
 package main
 
 import (
-	_ "backend-test/internal/cmd/server"
-	"backend-test/internal/http/handler"
-	"backend-test/internal/http/router"
-	postgres "backend-test/internal/storage/database"
+	"backend-test/internal/app"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/http/server"
+	"backend-test/internal/logging"
+	"backend-test/internal/rpc"
+	"backend-test/internal/tracing"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 )
 
 func main() {
-	defer postgres.CloseDB()
+	configFile := flag.String("config", "", "optional YAML or TOML configuration file (default $CONFIG_FILE)")
+	flag.Parse()
 
-	r := router.NewRouter()
-	handler.HandleRequests(r)
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format, cfg.Secrets()...)
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded", "config", cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "err", err)
+		os.Exit(1)
+	}
+
+	application, err := app.New(cfg, app.WithLogger(logger))
+	if err != nil {
+		logger.Error("failed to build the application", "err", err)
+		os.Exit(1)
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "1111"
+	srv := server.New(":"+strconv.Itoa(cfg.Server.Port), application.Router, application.Readiness, server.Options{
+		DrainTimeout:   cfg.Server.ShutdownTimeout,
+		ReadinessDelay: cfg.Server.ShutdownReadinessDelay,
+	})
+	if application.GRPC != nil {
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.GRPC.Port))
+		if err != nil {
+			logger.Error("failed to listen for gRPC", "err", err)
+			os.Exit(1)
+		}
+		go func() {
+			logger.Info("gRPC server listening", "addr", listener.Addr().String())
+			if err := application.GRPC.Serve(listener); err != nil {
+				logger.Error("gRPC server stopped with error", "err", err)
+				stop()
+			}
+		}()
+		// Registered first so in-flight calls finish before the
+		// application resources close.
+		srv.OnShutdown("grpc server", rpc.Shutdown(application.GRPC))
 	}
+	srv.OnShutdown("application", func(ctx context.Context) error {
+		return application.Close()
+	})
+	srv.OnShutdown("tracer provider", shutdownTracing)
 
-	r.Run(":" + port)
+	if err := srv.ListenAndServe(ctx); err != nil {
+		logger.Error("server stopped with error", "err", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func beerStylePath(beerUUID string) string {
+	return "/api/beer-styles/" + url.PathEscape(beerUUID)
+}
+
+func (c *Client) ListBeerStyles(ctx context.Context) ([]BeerStyle, error) {
+	var response struct {
+		BeerStyles []BeerStyle `json:"beerStyles"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/api/beer-styles/list", idempotent: true}, &response); err != nil {
+		return nil, err
+	}
+	return response.BeerStyles, nil
+}
+
+func (c *Client) GetBeerStyle(ctx context.Context, beerUUID string) (BeerStyle, error) {
+	var response struct {
+		Data BeerStyle `json:"data"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: beerStylePath(beerUUID), idempotent: true}, &response); err != nil {
+		return BeerStyle{}, err
+	}
+	return response.Data, nil
+}
+
+// CreateBeerStyle creates beerStyle; its UUID and timestamps are ignored.
+// Server errors are not retried, as the style may have been created.
+func (c *Client) CreateBeerStyle(ctx context.Context, beerStyle BeerStyle) (BeerStyle, error) {
+	var response struct {
+		Data BeerStyle `json:"data"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/beer-styles/create", body: beerStyle}, &response); err != nil {
+		return BeerStyle{}, err
+	}
+	return response.Data, nil
+}
+
+// UpdateBeerStyle applies changes and returns the style as stored.
+func (c *Client) UpdateBeerStyle(ctx context.Context, beerUUID string, changes BeerStyleUpdate) (BeerStyle, error) {
+	var response struct {
+		Data BeerStyle `json:"data"`
+	}
+	request := call{method: http.MethodPut, path: "/api/beer-styles/edit/" + url.PathEscape(beerUUID), body: changes, idempotent: true}
+	if err := c.do(ctx, request, &response); err != nil {
+		return BeerStyle{}, err
+	}
+	return response.Data, nil
+}
+
+func (c *Client) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: beerStylePath(beerUUID), idempotent: true}, nil)
+}
@@ -0,0 +1,231 @@
+// Package client is the Go client of the beer recommendation API. It
+// covers the beer style CRUD and the recommendations with the server's own
+// types, retries throttled and failed calls with backoff, and reports the
+// error responses as *Error values matching the sentinels of this package.
+//
+//	c, err := client.New("http://localhost:1112", client.WithAPIKey(key))
+//	recommendation, err := c.Recommend(ctx, client.RecommendationRequest{Temperature: -7})
+//	if errors.Is(err, client.ErrNotFound) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides how calls are retried. Rate limited calls are always
+// retried; server errors and network failures only for calls that are safe
+// to repeat, which leaves out creating a beer style.
+type RetryPolicy struct {
+	// MaxAttempts counts the first call; 1 disables retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry, doubled on each
+	// following one up to MaxBackoff, with jitter.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts, waiting 200ms then 400ms.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	apiKey      string
+	bearerToken string
+	userAgent   string
+	retry       RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the calls through httpClient, for its timeouts,
+// transport or instrumentation. The default is a client with a 30s timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAPIKey authenticates with an API key, sent in X-API-Key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken authenticates with a JWT, sent in Authorization.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.bearerToken = token }
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// New returns a client of the API served at baseURL, such as
+// http://localhost:1112.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "beer-api-go-client",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// call is one API call. idempotent calls are retried on server errors and
+// network failures as well as on 429.
+type call struct {
+	method     string
+	path       string
+	body       interface{}
+	idempotent bool
+}
+
+// do sends request and decodes the 2xx response into out, when not nil.
+func (c *Client) do(ctx context.Context, request call, out interface{}) error {
+	var body []byte
+	if request.body != nil {
+		var err error
+		if body, err = json.Marshal(request.body); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := c.send(ctx, request, body)
+		if err != nil {
+			if ctx.Err() != nil || !request.idempotent || attempt >= c.retry.MaxAttempts {
+				return err
+			}
+			if err := c.wait(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if response.StatusCode >= 200 && response.StatusCode < 300 {
+			defer response.Body.Close()
+			if out == nil {
+				return nil
+			}
+			if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+				return fmt.Errorf("decode %s %s response: %w", request.method, request.path, err)
+			}
+			return nil
+		}
+
+		apiErr := readError(response)
+		delay, retry := c.retryDelay(request, apiErr, attempt)
+		if !retry {
+			return apiErr
+		}
+		if err := c.wait(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, request call, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, request.method, c.baseURL.String()+request.path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", request.method, request.path, err)
+	}
+	return response, nil
+}
+
+// retryDelay tells whether the failed attempt is retried and after how
+// long. A Retry-After longer than MaxBackoff is not waited for: the error
+// is returned so the caller can decide.
+func (c *Client) retryDelay(request call, apiErr *Error, attempt int) (time.Duration, bool) {
+	if attempt >= c.retry.MaxAttempts {
+		return 0, false
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+	case apiErr.StatusCode >= 500 && request.idempotent:
+	default:
+		return 0, false
+	}
+
+	delay := c.backoff(attempt)
+	if apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > c.retry.MaxBackoff {
+			return 0, false
+		}
+		delay = max(delay, apiErr.RetryAfter)
+	}
+	return delay, true
+}
+
+// backoff doubles MinBackoff for each attempt, capped at MaxBackoff, and
+// waits between half of it and all of it so clients do not retry in step.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.MinBackoff
+	for i := 1; i < attempt && delay < c.retry.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, c.retry.MaxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (c *Client) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,436 @@
+package client
+
+import (
+	"backend-test/external/deezer"
+	"backend-test/external/music"
+	"backend-test/external/music/musictest"
+	"backend-test/internal/app"
+	"backend-test/internal/cache"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/logging"
+	"backend-test/internal/storage/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type memoryBeerRepository struct {
+	mu     sync.Mutex
+	styles []domain.BeerStyle
+}
+
+func (m *memoryBeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]domain.BeerStyle(nil), m.styles...), nil
+}
+
+func (m *memoryBeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, style := range m.styles {
+		if style.UUID == beerUUID {
+			return style, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	beerStyle.UUID = fmt.Sprintf("c8a1f1e2-0000-4000-8000-%012d", len(m.styles)+1)
+	beerStyle.CreatedAt = testNow
+	beerStyle.UpdatedAt = testNow
+	m.styles = append(m.styles, beerStyle)
+	return beerStyle, nil
+}
+
+func (m *memoryBeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.styles {
+		if m.styles[i].UUID == beerStyle.UUID {
+			m.styles[i] = beerStyle
+			return beerStyle, nil
+		}
+	}
+	return domain.BeerStyle{}, sql.ErrNoRows
+}
+
+func (m *memoryBeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.styles {
+		if m.styles[i].UUID == beerUUID {
+			m.styles = append(m.styles[:i], m.styles[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+var _ repository.BeerRepositoryInterface = (*memoryBeerRepository)(nil)
+
+type memoryPlaylistMappingRepository struct {
+	repository.PlaylistMappingRepositoryInterface
+}
+
+func (m *memoryPlaylistMappingRepository) ListPlaylistsForBeerStyle(ctx context.Context, beerStyleUUID string) ([]domain.BeerStylePlaylist, error) {
+	return nil, nil
+}
+
+// noAPIKeys stores no key; the bootstrap key is enough for the tests.
+type noAPIKeys struct {
+	repository.APIKeyRepositoryInterface
+}
+
+func (noAPIKeys) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	return domain.APIKey{}, sql.ErrNoRows
+}
+
+// discardHistory drops the recommendation history.
+type discardHistory struct {
+	repository.RecommendationHistoryRepositoryInterface
+}
+
+func (discardHistory) SaveRecommendations(ctx context.Context, records []domain.RecommendationRecord) error {
+	return nil
+}
+
+func (discardHistory) GetRecommendation(ctx context.Context, id string) (domain.RecommendationRecord, error) {
+	return domain.RecommendationRecord{}, sql.ErrNoRows
+}
+
+func (discardHistory) PurgeRecommendations(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+const bootstrapKey = "bootstrap-admin-key-with-32-characters"
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// failingFirst answers the first failures requests with status and
+// Retry-After: 0 before handing over to the router, like a proxy in front
+// of a restarting instance.
+type failingFirst struct {
+	next     http.Handler
+	status   int
+	failures atomic.Int32
+	calls    atomic.Int32
+}
+
+func (f *failingFirst) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.calls.Add(1)
+	if f.failures.Add(-1) >= 0 {
+		w.Header().Set("Retry-After", "0")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.status)
+		fmt.Fprintf(w, `{"message": %q}`, http.StatusText(f.status))
+		return
+	}
+	f.next.ServeHTTP(w, r)
+}
+
+type testServer struct {
+	url         string
+	handler     *failingFirst
+	application *app.App
+}
+
+func setupServer(t *testing.T, configure func(*config.Config)) testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	deezerServer := musictest.NewDeezerServer(musictest.SampleCatalog())
+	t.Cleanup(deezerServer.Close)
+
+	cfg := config.Default()
+	cfg.Database.URL = "postgres://unused@localhost:5432/beerdb"
+	cfg.Music.DefaultProvider = music.ProviderDeezer
+	cfg.Auth.BootstrapAPIKey = bootstrapKey
+	cfg.GRPC.Enabled = false
+	if configure != nil {
+		configure(cfg)
+	}
+
+	application, err := app.New(cfg,
+		app.WithLogger(logging.Discard()),
+		app.WithBeerRepository(&memoryBeerRepository{styles: []domain.BeerStyle{
+			{UUID: "c8a1f1e2-0000-4000-8000-000000000000", Name: "IPA", TempMin: 7, TempMax: 10, Category: "Ale", CreatedAt: testNow, UpdatedAt: testNow},
+		}}),
+		app.WithPlaylistMappingRepository(&memoryPlaylistMappingRepository{}),
+		app.WithAPIKeyRepository(noAPIKeys{}),
+		app.WithRecommendationHistoryRepository(discardHistory{}),
+		app.WithMusicProviders(music.NewRegistry(music.ProviderDeezer, deezer.NewProvider(deezerServer.URL, deezerServer.Client()))),
+		app.WithCache(cache.NewMemory(func() time.Time { return testNow })),
+		app.WithClock(func() time.Time { return testNow }),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { application.Close() })
+
+	handler := &failingFirst{next: application.Router}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return testServer{url: server.URL, handler: handler, application: application}
+}
+
+func (s testServer) failFirst(status, failures int) {
+	s.handler.status = status
+	s.handler.failures.Store(int32(failures))
+	s.handler.calls.Store(0)
+}
+
+var fastRetries = WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+
+func newClient(t *testing.T, server testServer, opts ...Option) *Client {
+	t.Helper()
+	c, err := New(server.url, append([]Option{fastRetries}, opts...)...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return c
+}
+
+func TestNew_RejectsRelativeURLs(t *testing.T) {
+	for _, baseURL := range []string{"localhost:1112", "/api", "ftp://example.com"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("expected %q to be rejected", baseURL)
+		}
+	}
+}
+
+func TestClient_BeerStyles(t *testing.T) {
+	server := setupServer(t, nil)
+	ctx := context.Background()
+	editor := newClient(t, server, WithAPIKey(bootstrapKey))
+	anonymous := newClient(t, server)
+
+	abv := 7.5
+	created, err := editor.CreateBeerStyle(ctx, BeerStyle{Name: "Dubbel", TempMin: 8, TempMax: 12, ABV: &abv, Aliases: []string{"Abbey Dubbel"}})
+	if err != nil {
+		t.Fatalf("expected the style to be created, got %v", err)
+	}
+	if created.UUID == "" || created.ABV == nil || *created.ABV != 7.5 || !created.CreatedAt.Equal(testNow) {
+		t.Errorf("unexpected created style %+v", created)
+	}
+
+	got, err := anonymous.GetBeerStyle(ctx, created.UUID)
+	if err != nil || got.Name != "Dubbel" || len(got.Aliases) != 1 {
+		t.Errorf("expected the created style, got %+v %v", got, err)
+	}
+	list, err := anonymous.ListBeerStyles(ctx)
+	if err != nil || len(list) != 2 {
+		t.Errorf("expected two styles, got %+v %v", list, err)
+	}
+
+	tempMax := 14.0
+	updated, err := editor.UpdateBeerStyle(ctx, created.UUID, BeerStyleUpdate{TempMax: &tempMax})
+	if err != nil || updated.TempMax != 14 || updated.Name != "Dubbel" {
+		t.Errorf("expected the range to be updated, got %+v %v", updated, err)
+	}
+
+	if err := editor.DeleteBeerStyle(ctx, created.UUID); err != nil {
+		t.Errorf("expected the style to be deleted, got %v", err)
+	}
+	_, err = anonymous.GetBeerStyle(ctx, created.UUID)
+	var apiErr *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.RequestID == "" || apiErr.Message != "beer style not found" {
+		t.Errorf("expected a not found error with its request ID, got %#v", err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := setupServer(t, nil)
+	ctx := context.Background()
+	editor := newClient(t, server, WithAPIKey(bootstrapKey))
+
+	tests := []struct {
+		name     string
+		call     func() error
+		sentinel error
+		status   int
+	}{
+		{"missing credentials", func() error {
+			_, err := newClient(t, server).CreateBeerStyle(ctx, BeerStyle{Name: "Gose", TempMin: 4, TempMax: 7})
+			return err
+		}, ErrUnauthorized, http.StatusUnauthorized},
+		{"unknown key", func() error {
+			_, err := newClient(t, server, WithBearerToken("bk_unknown")).ListBeerStyles(ctx)
+			return err
+		}, ErrUnauthorized, http.StatusUnauthorized},
+		{"duplicate name", func() error {
+			_, err := editor.CreateBeerStyle(ctx, BeerStyle{Name: "IPA", TempMin: 7, TempMax: 10})
+			return err
+		}, ErrConflict, http.StatusConflict},
+		{"invalid range", func() error {
+			_, err := editor.CreateBeerStyle(ctx, BeerStyle{Name: "Tripel", TempMin: 12, TempMax: 8})
+			return err
+		}, ErrBadRequest, http.StatusBadRequest},
+		{"unknown style", func() error {
+			return editor.DeleteBeerStyle(ctx, "c8a1f1e2-0000-4000-8000-999999999999")
+		}, ErrNotFound, http.StatusNotFound},
+		{"invalid temperature", func() error {
+			_, err := editor.Recommend(ctx, RecommendationRequest{Temperature: 200})
+			return err
+		}, ErrBadRequest, http.StatusBadRequest},
+		{"unknown recommendation", func() error {
+			_, err := editor.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-999999999999", FeedbackRequest{Vote: "up"})
+			return err
+		}, ErrNotFound, http.StatusNotFound},
+		{"invalid vote", func() error {
+			_, err := editor.SubmitFeedback(ctx, "4f0e8a52-0000-4000-8000-999999999999", FeedbackRequest{Vote: "maybe"})
+			return err
+		}, ErrBadRequest, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var apiErr *Error
+			if !errors.Is(err, tt.sentinel) || !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("expected %v with status %d, got %v", tt.sentinel, tt.status, err)
+			}
+			if errors.Is(err, ErrServer) {
+				t.Errorf("expected %v not to be a server error", err)
+			}
+		})
+	}
+
+	maxABV := 1.0
+	_, err := editor.Recommend(ctx, RecommendationRequest{Temperature: 8, MaxABV: &maxABV})
+	var apiErr *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Constraint != "max_abv" {
+		t.Errorf("expected the unsatisfiable constraint, got %#v", err)
+	}
+}
+
+func TestClient_Recommend(t *testing.T) {
+	server := setupServer(t, nil)
+	c := newClient(t, server)
+
+	recommendation, err := c.Recommend(context.Background(), RecommendationRequest{Temperature: 8, TrackLimit: 2})
+	if err != nil {
+		t.Fatalf("expected a recommendation, got %v", err)
+	}
+	if recommendation.BeerStyle != "IPA" || recommendation.Playlist.Name != "IPA Session" || len(recommendation.Playlist.Tracks) != 2 {
+		t.Errorf("unexpected recommendation %+v", recommendation)
+	}
+
+	results := c.RecommendBatch(context.Background(), []RecommendationRequest{{Temperature: 8}, {Temperature: 200}, {Temperature: 9}}, 2)
+	if len(results) != 3 {
+		t.Fatalf("expected three results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Recommendation.BeerStyle != "IPA" || results[2].Err != nil {
+		t.Errorf("expected the valid requests to succeed, got %+v", results)
+	}
+	if !errors.Is(results[1].Err, ErrBadRequest) || results[1].Recommendation != nil {
+		t.Errorf("expected the invalid temperature to fail alone, got %+v", results[1])
+	}
+}
+
+func TestClient_Explain(t *testing.T) {
+	server := setupServer(t, nil)
+	c := newClient(t, server)
+
+	explanation, err := c.Explain(context.Background(), RecommendationRequest{Temperature: 8}, false)
+	if err != nil {
+		t.Fatalf("expected an explanation, got %v", err)
+	}
+	if explanation.BeerStyle != "IPA" || len(explanation.Candidates) != 1 || explanation.Playlist.Resolved || explanation.Playlist.PlaylistID != "" {
+		t.Errorf("expected the IPA plan without a playlist, got %+v", explanation)
+	}
+
+	explanation, err = c.Explain(context.Background(), RecommendationRequest{Temperature: 8}, true)
+	if err != nil || explanation.Playlist.PlaylistID != "1001" || explanation.Playlist.Attempts[0].ResultIDs[0] != "1001" {
+		t.Errorf("expected the resolved IPA Session, got %+v %v", explanation, err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	server := setupServer(t, nil)
+	ctx := context.Background()
+	c := newClient(t, server, WithAPIKey(bootstrapKey))
+
+	server.failFirst(http.StatusBadGateway, 2)
+	if _, err := c.ListBeerStyles(ctx); err != nil || server.handler.calls.Load() != 3 {
+		t.Errorf("expected two retries to succeed, got %v after %d calls", err, server.handler.calls.Load())
+	}
+
+	server.failFirst(http.StatusServiceUnavailable, 3)
+	_, err := c.Recommend(ctx, RecommendationRequest{Temperature: 8})
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, ErrServer) || server.handler.calls.Load() != 3 {
+		t.Errorf("expected to give up after three attempts, got %v after %d calls", err, server.handler.calls.Load())
+	}
+
+	server.failFirst(http.StatusInternalServerError, 1)
+	_, err = c.CreateBeerStyle(ctx, BeerStyle{Name: "Gose", TempMin: 4, TempMax: 7})
+	if !errors.Is(err, ErrServer) || server.handler.calls.Load() != 1 {
+		t.Errorf("expected creates not to be retried on server errors, got %v after %d calls", err, server.handler.calls.Load())
+	}
+
+	server.failFirst(http.StatusTooManyRequests, 1)
+	if _, err := c.CreateBeerStyle(ctx, BeerStyle{Name: "Gose", TempMin: 4, TempMax: 7}); err != nil || server.handler.calls.Load() != 2 {
+		t.Errorf("expected rate limited creates to be retried, got %v after %d calls", err, server.handler.calls.Load())
+	}
+}
+
+func TestClient_DoesNotWaitLongerThanMaxBackoff(t *testing.T) {
+	server := setupServer(t, func(cfg *config.Config) {
+		cfg.RateLimit.Read = config.RateLimitRule{RequestsPerMinute: 1, Burst: 1}
+	})
+	c := newClient(t, server)
+
+	if _, err := c.ListBeerStyles(context.Background()); err != nil {
+		t.Fatalf("expected the first call to pass, got %v", err)
+	}
+	server.failFirst(0, 0)
+	_, err := c.ListBeerStyles(context.Background())
+	var apiErr *Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Minute || server.handler.calls.Load() != 1 {
+		t.Errorf("expected the rate limit to be returned at once with its Retry-After, got %#v after %d calls", err, server.handler.calls.Load())
+	}
+}
+
+func TestClient_StopsWaitingWhenTheContextEnds(t *testing.T) {
+	server := setupServer(t, nil)
+	c := newClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}))
+	server.failFirst(http.StatusServiceUnavailable, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListBeerStyles(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline, got %v", err)
+	}
+}
+
+func TestClient_Health(t *testing.T) {
+	server := setupServer(t, nil)
+	c := newClient(t, server)
+
+	report, err := c.Health(context.Background())
+	if err != nil || report.Status != domain.HealthStatusUp || len(report.Checks) == 0 {
+		t.Fatalf("expected an up report with its checks, got %+v %v", report, err)
+	}
+
+	server.application.Readiness.SetReady(false)
+	server.handler.calls.Store(0)
+	report, err = c.Health(context.Background())
+	if err != nil || report.Status != domain.HealthStatusDown {
+		t.Errorf("expected the 503 report without an error, got %+v %v", report, err)
+	}
+	if calls := server.handler.calls.Load(); calls != 1 {
+		t.Errorf("expected a single call, got %d", calls)
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinels of the error responses, for errors.Is. Every 5xx matches
+// ErrServer besides its own sentinel, if any.
+var (
+	ErrBadRequest    = errors.New("bad request")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrRateLimited   = errors.New("rate limited")
+	ErrUnavailable   = errors.New("unavailable")
+	ErrServer        = errors.New("server error")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusUnprocessableEntity: ErrUnprocessable,
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusServiceUnavailable:  ErrUnavailable,
+}
+
+// Error is an error response of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+	// RequestID matches the response with the server logs.
+	RequestID string
+	// Errors lists each mismatch of a body rejected by schema validation.
+	Errors []string
+	// Constraint is the recommendation preference no beer style satisfies.
+	Constraint string
+	// RetryAfter is how long a rate limited client should wait.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("beer api: %d %s", e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		message += " (request " + e.RequestID + ")"
+	}
+	return message
+}
+
+func (e *Error) Is(target error) bool {
+	if target == ErrServer {
+		return e.StatusCode >= 500
+	}
+	sentinel, ok := statusErrors[e.StatusCode]
+	return ok && sentinel == target
+}
+
+// readError decodes an error response: the {"message"} body of the API or
+// the problem+json body of rate limited calls.
+func readError(response *http.Response) *Error {
+	defer response.Body.Close()
+
+	apiErr := &Error{StatusCode: response.StatusCode, RequestID: response.Header.Get("X-Request-ID")}
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	var body struct {
+		Message    string   `json:"message"`
+		Detail     string   `json:"detail"`
+		RequestID  string   `json:"request_id"`
+		Errors     []string `json:"errors"`
+		Constraint string   `json:"constraint"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(response.Body, 1<<20)); err == nil {
+		_ = json.Unmarshal(data, &body)
+	}
+
+	apiErr.Message = body.Message
+	if apiErr.Message == "" {
+		apiErr.Message = body.Detail
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(response.StatusCode)
+	}
+	if body.RequestID != "" {
+		apiErr.RequestID = body.RequestID
+	}
+	apiErr.Errors = body.Errors
+	apiErr.Constraint = body.Constraint
+	return apiErr
+}
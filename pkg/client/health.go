@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Health returns the readiness report of the server. A server that is not
+// ready answers 503 with the report, which is returned without an error and
+// without retrying: its Status tells the outcome.
+func (c *Client) Health(ctx context.Context) (*HealthReport, error) {
+	request := call{method: http.MethodGet, path: "/readyz"}
+	response, err := c.send(ctx, request, nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusServiceUnavailable {
+		return nil, readError(response)
+	}
+	defer response.Body.Close()
+
+	var report HealthReport
+	if err := json.NewDecoder(response.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decode %s %s response: %w", request.method, request.path, err)
+	}
+	return &report, nil
+}
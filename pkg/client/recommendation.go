@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Recommend returns the beer style and playlist for the temperature and
+// preferences of request. It is retried like a read, as it changes
+// nothing.
+func (c *Client) Recommend(ctx context.Context, request RecommendationRequest) (*Recommendation, error) {
+	var recommendation Recommendation
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/recommendations/suggest", body: request, idempotent: true}, &recommendation); err != nil {
+		return nil, err
+	}
+	return &recommendation, nil
+}
+
+// BatchResult is the outcome of one request of RecommendBatch.
+type BatchResult struct {
+	Recommendation *Recommendation
+	Err            error
+}
+
+// RecommendBatch recommends for each request, at most concurrency at a
+// time (one when not positive), and returns the results in the order of
+// requests. A failed request does not stop the others.
+func (c *Client) RecommendBatch(ctx context.Context, requests []RecommendationRequest, concurrency int) []BatchResult {
+	results := make([]BatchResult, len(requests))
+	slots := make(chan struct{}, max(concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			results[i].Recommendation, results[i].Err = c.Recommend(ctx, request)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Explain returns how the recommendation for request is reached: the
+// ranking of every candidate style, the styles the preferences leave out
+// and the playlist search. The music provider is only called when
+// resolvePlaylist is set.
+func (c *Client) Explain(ctx context.Context, request RecommendationRequest, resolvePlaylist bool) (*RecommendationExplanation, error) {
+	path := "/api/recommendations/explain"
+	if resolvePlaylist {
+		path += "?resolve_playlist=true"
+	}
+	var explanation RecommendationExplanation
+	if err := c.do(ctx, call{method: http.MethodPost, path: path, body: request, idempotent: true}, &explanation); err != nil {
+		return nil, err
+	}
+	return &explanation, nil
+}
+
+// SubmitFeedback rates the recommendation with the ID returned by
+// Recommend, replacing a previous vote; it is retried as it can be
+// repeated. Recommendations can be rated once the server has written them,
+// a few seconds after they are served: until then it fails with
+// ErrNotFound.
+func (c *Client) SubmitFeedback(ctx context.Context, recommendationID string, request FeedbackRequest) (Feedback, error) {
+	var response struct {
+		Data Feedback `json:"data"`
+	}
+	path := "/api/recommendations/" + url.PathEscape(recommendationID) + "/feedback"
+	if err := c.do(ctx, call{method: http.MethodPost, path: path, body: request, idempotent: true}, &response); err != nil {
+		return Feedback{}, err
+	}
+	return response.Data, nil
+}
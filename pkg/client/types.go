@@ -0,0 +1,23 @@
+package client
+
+import "backend-test/internal/domain"
+
+// The request and response types are the server's, so they cannot drift
+// from what it sends and accepts.
+type (
+	BeerStyle = domain.BeerStyle
+	// BeerStyleUpdate changes the fields that are set; Aliases replaces the
+	// list, even with an empty one.
+	BeerStyleUpdate       = domain.BeerStyleUpdateRequest
+	RecommendationRequest = domain.TemperatureRequest
+	Recommendation        = domain.RecommendationResponse
+	Playlist              = domain.PlaylistInfo
+	Track                 = domain.TrackInfo
+	Fallback              = domain.FallbackInfo
+	HealthReport          = domain.HealthReport
+	// RecommendationExplanation is returned by Explain.
+	RecommendationExplanation = domain.RecommendationExplanation
+	FeedbackRequest           = domain.FeedbackRequest
+	// Feedback is the vote stored by SubmitFeedback.
+	Feedback = domain.RecommendationFeedback
+)
@@ -1,10 +1,13 @@
 package integration
 
 import (
+	config "backend-test/internal/cmd/server"
 	"backend-test/internal/domain"
 	"backend-test/internal/http/controller"
 	"backend-test/internal/http/router"
+	"backend-test/internal/logging"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -20,14 +23,14 @@ type MockBeerService struct {
 	errorMsg    string
 }
 
-func (m *MockBeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
+func (m *MockBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
 	if m.shouldError {
 		return nil, &MockError{message: m.errorMsg}
 	}
 	return m.beers, nil
 }
 
-func (m *MockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
+func (m *MockBeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
@@ -39,7 +42,7 @@ func (m *MockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle,
 	return domain.BeerStyle{}, &MockError{message: "beer not found"}
 }
 
-func (m *MockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *MockBeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
@@ -50,14 +53,14 @@ func (m *MockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Be
 	return beerStyle, nil
 }
 
-func (m *MockBeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *MockBeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
 	return beerStyle, nil
 }
 
-func (m *MockBeerService) DeleteBeerStyle(beerUUID string) error {
+func (m *MockBeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
 	if m.shouldError {
 		return &MockError{message: m.errorMsg}
 	}
@@ -85,14 +88,43 @@ func (m *MockValidationService) ValidateTemperatureInput(temperature float64) er
 	return nil
 }
 
-func (m *MockValidationService) ValidateUniqueNameForCreate(name string) error {
+func (m *MockValidationService) ValidateABV(abv *float64) error {
+	return nil
+}
+
+func (m *MockValidationService) ValidateRecommendationPreferences(request domain.TemperatureRequest) error {
+	return nil
+}
+
+func (m *MockValidationService) ValidatePlaylistMapping(request domain.BeerStylePlaylistRequest) error {
+	if m.shouldError {
+		return &MockError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *MockValidationService) ValidateAPIKeyRequest(request domain.APIKeyRequest) error {
+	if m.shouldError {
+		return &MockError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *MockValidationService) ValidateFeedbackRequest(request domain.FeedbackRequest) error {
+	if m.shouldError {
+		return &MockError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *MockValidationService) ValidateUniqueNameForCreate(ctx context.Context, name string) error {
 	if m.uniqueNameError {
 		return &MockError{message: m.errorMsg}
 	}
 	return nil
 }
 
-func (m *MockValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
+func (m *MockValidationService) ValidateUniqueNameForUpdate(ctx context.Context, name string, excludeUUID string) error {
 	if m.shouldError {
 		return &MockError{message: m.errorMsg}
 	}
@@ -128,11 +160,11 @@ func (e *MockError) Error() string {
 }
 
 func setupTestController(mockBeer *MockBeerService, mockValidation *MockValidationService, mockUpdate *MockUpdateService) *controller.BeerController {
-	return controller.NewBeerController(mockBeer, mockValidation, mockUpdate)
+	return controller.NewBeerController(mockBeer, mockValidation, mockUpdate, logging.Discard())
 }
 
 func setupTestRouter(beerController *controller.BeerController) *gin.Engine {
-	r := router.NewRouter()
+	r := router.NewRouter(config.Default(), logging.Discard())
 	api := r.Group("/api")
 
 	beer := api.Group("/beer-styles")
@@ -3,8 +3,10 @@ package integration
 import (
 	"backend-test/internal/domain"
 	"backend-test/internal/http/controller"
+	"backend-test/internal/logging"
 	"backend-test/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -19,17 +21,24 @@ type MockRecommendationService struct {
 	response    domain.RecommendationResponse
 }
 
-func (m *MockRecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
+func (m *MockRecommendationService) GetRecommendationForTemperature(ctx context.Context, request domain.TemperatureRequest) (*domain.RecommendationResponse, error) {
 	if m.shouldError {
 		return nil, &MockError{message: m.errorMsg}
 	}
 	return &m.response, nil
 }
 
+func (m *MockRecommendationService) ExplainRecommendation(ctx context.Context, request domain.TemperatureRequest, resolvePlaylist bool) (*domain.RecommendationExplanation, error) {
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	return &domain.RecommendationExplanation{Temperature: request.Temperature, BeerStyle: m.response.BeerStyle}, nil
+}
+
 func setupRecommendationTestRouter(recommendationService service.RecommendationServiceInterface, validationService service.ValidationServiceInterface) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
-	recommendationController := controller.NewRecommendationController(recommendationService, validationService)
+	recommendationController := controller.NewRecommendationController(recommendationService, validationService, logging.Discard())
 
 	r := gin.New()
 	r.Use(gin.Logger())